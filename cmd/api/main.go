@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/yourusername/transcription-service/internal/api"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/tenant"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+func main() {
+	log.Println("Starting jobs API Lambda function")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logging.SetDefault(logging.New(os.Stdout, cfg.LogLevel))
+
+	tp, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		ServiceName: "jobs-api",
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		log.Fatalf("Failed to initialize AWS clients: %v", err)
+	}
+
+	s3Ops := awsclient.NewS3Operations(clients.GetS3())
+	ddbOps := awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName)
+	ddbOps.SetTranscriptReader(s3Ops)
+
+	h := api.NewHandler(ddbOps, s3Ops, cfg.SubmissionBucket)
+	if cfg.TenantRegistry != "" {
+		registry, err := tenant.Load(context.Background(), cfg.TenantRegistry, s3Ops)
+		if err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+		h.SetTenants(registry)
+	}
+	if cfg.EncryptionKMSKeyID != "" {
+		encrypter := envelope.NewEncrypter(awsclient.NewKMSOperations(clients.GetKMS()), cfg.EncryptionKMSKeyID)
+		ddbOps.SetEncryption(encrypter)
+		h.SetEncryption(encrypter)
+	}
+	lambda.Start(tracing.Handler(tp, h.HandleRequest))
+}
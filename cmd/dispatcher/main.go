@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/notify"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+func main() {
+	log.Println("Starting outbox dispatcher Lambda function")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logging.SetDefault(logging.New(os.Stdout, cfg.LogLevel))
+
+	tp, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		ServiceName: "dispatcher",
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		log.Fatalf("Failed to initialize AWS clients: %v", err)
+	}
+
+	sinks, err := notificationSinks(context.Background(), cfg, clients)
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+	if len(sinks) == 0 {
+		log.Println("No notification sinks configured, outbox events will be dropped")
+	}
+
+	d := notify.NewDispatcher(
+		awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName),
+		sinks...,
+	)
+	lambda.Start(tracing.Handler(tp, d.HandleStreamEvent))
+}
+
+// notificationSinks builds the completion event sinks enabled in the configuration
+func notificationSinks(ctx context.Context, cfg *config.Config, clients *awsclient.Clients) ([]notify.Sink, error) {
+	var sinks []notify.Sink
+
+	if cfg.NotifySNSTopicARN != "" {
+		sinks = append(sinks, notify.NewSNSSink(clients.GetSNS(), cfg.NotifySNSTopicARN))
+	}
+
+	if cfg.NotifyEventBusName != "" {
+		sinks = append(sinks, notify.NewEventBridgeSink(clients.GetEventBridge(), cfg.NotifyEventBusName))
+	}
+
+	if len(cfg.NotifyWebhookURLs) > 0 {
+		// The signing key may be stored as a string or as binary
+		secret, err := cfg.Secrets(clients.GetSecretsManager()).GetSecretStage(ctx, cfg.NotifyWebhookSecretName, awsclient.StageCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load webhook signing secret: %w", err)
+		}
+
+		for _, url := range cfg.NotifyWebhookURLs {
+			sinks = append(sinks, notify.NewWebhookSink(url, secret.Bytes()))
+		}
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/jobs"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/tenant"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+func main() {
+	log.Println("Starting quota scheduler Lambda function")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logging.SetDefault(logging.New(os.Stdout, cfg.LogLevel))
+
+	tp, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		ServiceName: "scheduler",
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		log.Fatalf("Failed to initialize AWS clients: %v", err)
+	}
+
+	s3Ops := awsclient.NewS3Operations(clients.GetS3())
+	ddbOps := awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName)
+
+	var registry *tenant.Registry
+	if cfg.TenantRegistry != "" {
+		if registry, err = tenant.Load(context.Background(), cfg.TenantRegistry, s3Ops); err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+	}
+
+	service := jobs.NewService(ddbOps, s3Ops, cfg.SubmissionBucket)
+	service.SetTenants(registry)
+	scheduler := jobs.NewScheduler(service, ddbOps, registry, cfg.DrainBatchSize)
+
+	// Runs on a schedule; each run drains one batch of the deferred backlog
+	lambda.Start(tracing.ErrorHandler(tp, func(ctx context.Context, _ events.CloudWatchEvent) error {
+		_, err := scheduler.Drain(ctx)
+		return err
+	}))
+}
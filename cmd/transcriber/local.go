@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/elevenlabs"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/processor"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/settings"
+	"github.com/yourusername/transcription-service/internal/usage"
+)
+
+// services are the storage the transcriber works on: AWS, or files under LOCAL_DATA_DIR in local mode
+type services struct {
+	objects    processor.S3API
+	state      processor.DynamoDBAPI
+	usage      usage.Store
+	secrets    awsclient.SecretSource
+	parameters settings.ParameterReader
+
+	// quotas and clients are nil in local mode, local is nil otherwise
+	quotas  quota.Store
+	clients *awsclient.Clients
+	local   *awsclient.LocalS3
+}
+
+func newServices(cfg *config.Config) (*services, error) {
+	if cfg.LocalMode {
+		objects := awsclient.NewLocalS3(cfg.LocalDataDir)
+		state, err := awsclient.OpenLocalStore(filepath.Join(cfg.LocalDataDir, cfg.DynamoDBTableName+".json"))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Local mode: buckets and job state are in %s", cfg.LocalDataDir)
+
+		return &services{
+			objects: objects,
+			state:   state,
+			usage:   state,
+			secrets: cfg.Secrets(nil),
+			local:   objects,
+		}, nil
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		return nil, err
+	}
+	table := awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName)
+
+	return &services{
+		objects:    awsclient.NewS3Operations(clients.GetS3()),
+		state:      table,
+		usage:      table,
+		secrets:    cfg.Secrets(clients.GetSecretsManager()),
+		parameters: awsclient.NewSSMOperations(clients.GetSSM()),
+		quotas:     table,
+		clients:    clients,
+	}, nil
+}
+
+// startFakeAPI serves elevenlabs.FakeHandler on a local port and returns its base URL. The fake doesn't
+// check the API key, so a placeholder is used when none is configured.
+func startFakeAPI(secretName string) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	go http.Serve(listener, elevenlabs.FakeHandler())
+
+	if env := awsclient.SecretEnvVar(secretName); os.Getenv(env) == "" {
+		os.Setenv(env, "local")
+	}
+
+	baseURL := fmt.Sprintf("http://%s/v1", listener.Addr())
+	log.Printf("Local mode: transcribing with the fake API at %s", baseURL)
+	return baseURL, nil
+}
+
+// localSubmissionBucket receives the dispatch manifests of local runs unless SUBMISSION_BUCKET is set
+const localSubmissionBucket = "submissions"
+
+// runLocal processes every audio file and job manifest in the folders, as the S3 trigger would. Each folder
+// is served as a bucket named after it, so a file's job ID is its path within the folder; files that
+// already finished are skipped on later runs. The jobs that batch manifests dispatch to the submissions
+// bucket are processed after each folder.
+func runLocal(ctx context.Context, proc *processor.Processor, objects *awsclient.LocalS3, submissions string, dirs []string) error {
+	processed, failed := 0, 0
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		bucket := filepath.Base(abs)
+		objects.Mount(bucket, abs)
+
+		err = filepath.WalkDir(abs, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Hidden files and folders, such as the default .local data directory, aren't audio
+			if path != abs && strings.HasPrefix(entry.Name(), ".") {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(abs, path)
+			if err != nil {
+				return err
+			}
+			key := filepath.ToSlash(rel)
+			switch {
+			case model.IsManifestFile(key):
+				err = proc.ProcessManifest(ctx, bucket, key)
+			case model.IsAudioFile(key):
+				err = proc.ProcessFile(ctx, bucket, key)
+			default:
+				return nil
+			}
+			processed++
+			if err != nil {
+				log.Printf("Failed to process %s: %v", path, err)
+				failed++
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+
+		dispatched, dispatchFailed, err := runDispatched(ctx, proc, objects, submissions)
+		if err != nil {
+			return err
+		}
+		processed += dispatched
+		failed += dispatchFailed
+	}
+
+	log.Printf("Local run finished: %d files, %d failed", processed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, processed)
+	}
+	return nil
+}
+
+// runDispatched processes the dispatch manifests waiting in the submissions bucket, as the S3 trigger
+// would, and removes them as a lifecycle rule would
+func runDispatched(ctx context.Context, proc *processor.Processor, objects *awsclient.LocalS3, bucket string) (processed, failed int, err error) {
+	dir, err := objects.Path(bucket, strings.TrimSuffix(model.DispatchManifestPrefix, "/"))
+	if err != nil {
+		return 0, 0, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read dispatched jobs: %w", err)
+	}
+
+	for _, entry := range entries {
+		key := model.DispatchManifestPrefix + entry.Name()
+		if entry.IsDir() || !model.IsDispatchManifest(key) {
+			continue
+		}
+		processed++
+		if err := proc.ProcessManifest(ctx, bucket, key); err != nil {
+			log.Printf("Failed to process %s: %v", key, err)
+			failed++
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return processed, failed, fmt.Errorf("failed to remove %s: %w", key, err)
+		}
+	}
+	return processed, failed, nil
+}
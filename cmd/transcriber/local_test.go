@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/processor"
+)
+
+// stubClient transcribes every file as the same text
+type stubClient struct{}
+
+func (stubClient) TranscribeAudio(ctx context.Context, audioURL string) (*model.ElevenLabsResponse, error) {
+	return &model.ElevenLabsResponse{Text: "hello", Success: true}, nil
+}
+
+func (stubClient) TranscribeAudioWithOptions(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error) {
+	return &model.ElevenLabsResponse{Text: "hello", Success: true}, nil
+}
+
+func TestRunLocal_Manifest(t *testing.T) {
+	ctx := context.Background()
+	data := t.TempDir()
+	calls := filepath.Join(t.TempDir(), "calls")
+	assert.NoError(t, os.MkdirAll(filepath.Join(calls, "jobs"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(calls, "one.mp3"), []byte("audio"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(calls, "two.wav"), []byte("audio"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(calls, "notes.txt"), []byte("not audio"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(calls, "jobs", "b.manifest.json"),
+		[]byte(`{"batchId": "b", "files": [{"key": "one.mp3"}, {"key": "two.wav"}]}`), 0644))
+
+	objects := awsclient.NewLocalS3(data)
+	store, err := awsclient.OpenLocalStore(filepath.Join(data, "jobs.json"))
+	assert.NoError(t, err)
+	proc := processor.NewProcessorWithOperations(objects, store, stubClient{}, "")
+
+	proc.SetSubmissionBucket(localSubmissionBucket)
+
+	assert.NoError(t, runLocal(ctx, proc, objects, localSubmissionBucket, []string{calls}))
+
+	// The manifest is run as a batch and the text file is skipped rather than rejected
+	batch, err := store.GetBatchItem(ctx, "", "b")
+	assert.NoError(t, err)
+	if assert.NotNil(t, batch) {
+		assert.Equal(t, model.BatchStatusCompleted, batch.BatchStatus)
+		assert.Equal(t, 2, batch.CompletedFiles)
+	}
+	item, err := store.GetTranscriptionItem(ctx, "notes.txt")
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+
+	// The batch's jobs were dispatched through the submissions bucket, and their manifests are gone
+	dispatched, err := os.ReadDir(filepath.Join(data, localSubmissionBucket, "jobs", "dispatch"))
+	assert.NoError(t, err)
+	assert.Empty(t, dispatched)
+}
@@ -3,20 +3,172 @@ package main
 import (
 	"context"
 	"log"
-	
-	"github.com/aws/aws-lambda-go/events"
+	"os"
+
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/elevenlabs"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/handler"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/metrics"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/processor"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/redact"
+	"github.com/yourusername/transcription-service/internal/settings"
+	"github.com/yourusername/transcription-service/internal/tenant"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"github.com/yourusername/transcription-service/internal/usage"
 )
 
-func HandleRequest(ctx context.Context, s3Event events.S3Event) error {
-	log.Printf("Received S3 event with %d records", len(s3Event.Records))
-	for _, record := range s3Event.Records {
-		log.Printf("Processing file: s3://%s/%s", record.S3.Bucket.Name, record.S3.Object.Key)
+func main() {
+	log.Println("Starting Lambda function")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logging.SetDefault(logging.New(os.Stdout, cfg.LogLevel))
+
+	tp, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		ServiceName: "transcriber",
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	svc, err := newServices(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %v", err)
+	}
+
+	baseURL := cfg.ElevenLabsBaseURL
+	if cfg.LocalMode && baseURL == "" {
+		if baseURL, err = startFakeAPI(cfg.ElevenLabsSecretName); err != nil {
+			log.Fatalf("Failed to start the local transcription API: %v", err)
+		}
+	}
+
+	// Secrets are cached across warm invocations and shared by the tenants' clients
+	elevenlabsClient, err := elevenlabs.NewClientWithSecrets(context.Background(), svc.secrets, cfg.ElevenLabsSecretName)
+	if err != nil {
+		log.Fatalf("Failed to initialize ElevenLabs client: %v", err)
+	}
+	elevenlabsClient.SetBaseURL(baseURL)
+	elevenlabsClient.SetTimeout(cfg.ElevenLabsTimeout)
+	elevenlabsClient.SetRetryPolicy(cfg.ElevenLabsMaxRetries, cfg.ElevenLabsRetryBackoff)
+
+	// Metrics go to stdout in Embedded Metric Format, where CloudWatch Logs extracts them
+	var recorder metrics.Recorder = metrics.Nop{}
+	if cfg.MetricsNamespace != "" {
+		recorder = metrics.NewEMF(os.Stdout, cfg.MetricsNamespace)
+	}
+	elevenlabsClient.SetMetrics(recorder)
+
+	proc := processor.NewProcessorWithOperations(svc.objects, svc.state, elevenlabsClient, cfg.OutputS3Bucket)
+	proc.SetLanguageRouting(cfg.SupportedLanguages, cfg.LanguageRoutes)
+	proc.SetMetrics(recorder)
+	proc.SetUsage(usage.NewLedger(svc.usage, cfg.UsagePrices))
+
+	proc.SetPublishEvents(cfg.NotificationsEnabled())
+	proc.SetTranscriptStorage(cfg.ArtifactsS3Bucket, cfg.InlineTranscriptLimit)
+	proc.SetRetention(cfg.RetentionPolicies)
+
+	proc.SetVocabularies(cfg.Vocabularies)
+
+	// Batch manifests hand their jobs to other invocations through the submission bucket
+	submissions := cfg.SubmissionBucket
+	if cfg.LocalMode && submissions == "" {
+		submissions = localSubmissionBucket
 	}
-	return nil
+	proc.SetSubmissionBucket(submissions)
+	if len(cfg.BatchCallbackHosts) > 0 {
+		secret, err := svc.secrets.GetSecretStage(context.Background(), cfg.NotifyWebhookSecretName, awsclient.StageCurrent)
+		if err != nil {
+			log.Fatalf("Failed to load batch callback signing secret: %v", err)
+		}
+		proc.SetBatchCallbacks(secret.Bytes(), cfg.BatchCallbackHosts)
+	}
+	if cfg.TenantRegistry != "" {
+		registry, err := tenant.Load(context.Background(), cfg.TenantRegistry, svc.objects)
+		if err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+
+		// Tenants with their own API key get their own ElevenLabs client
+		proc.SetTenants(registry, func(ctx context.Context, secretName string) (processor.TranscriptionClient, error) {
+			client, err := elevenlabs.NewClientWithSecrets(ctx, svc.secrets, secretName)
+			if err != nil {
+				return nil, err
+			}
+			client.SetBaseURL(baseURL)
+			client.SetTimeout(cfg.ElevenLabsTimeout)
+			client.SetRetryPolicy(cfg.ElevenLabsMaxRetries, cfg.ElevenLabsRetryBackoff)
+			client.SetMetrics(recorder)
+			return client, nil
+		})
+		if svc.quotas != nil {
+			proc.SetQuotas(quota.NewLimiter(svc.quotas, cfg.QuotaRetryDelay))
+		} else {
+			log.Println("Tenant quotas aren't enforced in local mode")
+		}
+	}
+	if cfg.RuntimeSettings != "" {
+		source, err := settings.NewSource(cfg.RuntimeSettings, svc.parameters, svc.objects)
+		if err != nil {
+			log.Fatalf("Failed to configure runtime settings: %v", err)
+		}
+		proc.SetSettings(settings.NewCache(source, cfg.RuntimeSettingsTTL))
+	}
+	if cfg.EncryptionKMSKeyID != "" {
+		proc.SetEncryption(envelope.NewEncrypter(awsclient.NewKMSOperations(svc.clients.GetKMS()), cfg.EncryptionKMSKeyID))
+	}
+
+	pipeline, err := newPipeline(cfg, proc)
+	if err != nil {
+		log.Fatalf("Failed to configure post-processing: %v", err)
+	}
+	proc.SetPostProcessing(pipeline)
+
+	// In local mode, folders named on the command line are processed instead of serving Lambda events
+	if cfg.LocalMode && len(os.Args) > 1 {
+		if err := runLocal(context.Background(), proc, svc.local, submissions, os.Args[1:]); err != nil {
+			log.Fatalf("Local run failed: %v", err)
+		}
+		return
+	}
+
+	h := handler.NewHandler(proc)
+	lambda.Start(tracing.ErrorHandler(tp, h.HandleS3Event))
 }
 
-func main() {
-	log.Println("Starting Lambda function")
-	lambda.Start(HandleRequest)
+// newPipeline builds the configured post-processing stages. Stages that need configuration are only
+// available when it is present.
+func newPipeline(cfg *config.Config, proc *processor.Processor) (*postprocess.Pipeline, error) {
+	available := map[string]postprocess.Stage{
+		"normalize":  postprocess.Normalize(),
+		"vocabulary": postprocess.Vocabulary(),
+	}
+
+	if len(cfg.ReplaceDictionary) > 0 {
+		replace, err := postprocess.Replace(cfg.ReplaceDictionary)
+		if err != nil {
+			return nil, err
+		}
+		available["replace"] = replace
+	}
+
+	if cfg.RedactionEnabled() {
+		redactor, err := redact.NewRedactor(cfg.RedactPIITypes, cfg.RedactCustomPatterns)
+		if err != nil {
+			return nil, err
+		}
+		available["redact"] = proc.RedactionStage(redactor, cfg.UnredactedS3Bucket, cfg.UnredactedKMSKeyID)
+	}
+
+	return postprocess.New(cfg.PostProcessStages, available)
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/transcription-service/internal/config"
+)
+
+// configFlags parses the layer flags shared by the config commands; they default to CONFIG_FILE and
+// CONFIG_SOURCE
+func configFlags(name string, args []string) (*flag.FlagSet, *config.Layers, *bool, error) {
+	layers := config.LayersFromEnv()
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&layers.File, "file", layers.File, "JSON or YAML config file")
+	flags.StringVar(&layers.Remote, "source", layers.Remote, "remote config document: ssm:<parameter> or s3://bucket/key")
+	redacted := flags.Bool("redacted", false, "mask secret values")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, nil, err
+	}
+	return flags, &layers, redacted, nil
+}
+
+// runConfigValidate resolves every config layer and lists all problems found
+func runConfigValidate(ctx context.Context, args []string) error {
+	_, layers, _, err := configFlags("config validate", args)
+	if err != nil {
+		return err
+	}
+
+	_, err = config.Load(ctx, *layers, nil)
+
+	var validation *config.ValidationError
+	if errors.As(err, &validation) {
+		for _, problem := range validation.Problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		return fmt.Errorf("configuration has %d problems", len(validation.Problems))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("configuration is valid")
+	return nil
+}
+
+// runConfigPrint prints every resolved setting with the layer it came from
+func runConfigPrint(ctx context.Context, args []string) error {
+	_, layers, redacted, err := configFlags("config print", args)
+	if err != nil {
+		return err
+	}
+
+	values, err := config.Resolve(ctx, *layers, nil)
+	if err != nil {
+		return err
+	}
+	return values.Print(os.Stdout, *redacted)
+}
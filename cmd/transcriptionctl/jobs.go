@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/jobs"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// runJobsList queries jobs through the secondary indexes and prints one JSON page
+func runJobsList(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("jobs list", flag.ContinueOnError)
+	status := flags.String("status", "", "filter by status (PENDING, CLAIMED, SUBMITTED, TRANSCRIBING, POSTPROCESSING, COMPLETED, FAILED, CANCELLED, REJECTED)")
+	bucket := flags.String("bucket", "", "filter by source bucket")
+	since := flags.String("since", "", "lower time bound: duration ago (24h), date (2006-01-02) or RFC3339")
+	until := flags.String("until", "", "upper time bound: duration ago, date or RFC3339")
+	limit := flags.Int("limit", 50, "maximum number of jobs to return")
+	cursor := flags.String("cursor", "", "continue from a previous page's nextCursor")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	query := model.TranscriptionQuery{
+		Status:       model.TranscriptionStatus(*status),
+		SourceBucket: *bucket,
+		Limit:        int32(*limit),
+		Cursor:       *cursor,
+	}
+
+	if query.Status != "" && !query.Status.Valid() {
+		return fmt.Errorf("unknown -status %q", *status)
+	}
+
+	var err error
+	now := time.Now()
+	if query.Since, err = model.ParseTimeBound(*since, now); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if query.Until, err = model.ParseTimeBound(*until, now); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	ops, err := newDynamoDBOperations()
+	if err != nil {
+		return err
+	}
+
+	page, err := ops.QueryTranscriptionItems(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(page)
+}
+
+// runJobsReprocess requeues a job, optionally overriding its language, provider or model
+func runJobsReprocess(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("jobs reprocess", flag.ContinueOnError)
+	language := flags.String("language", "", "language hint, or \"auto\" to detect")
+	provider := flags.String("provider", "", "transcription provider to use")
+	modelID := flags.String("model", "", "provider model to use")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: transcriptionctl jobs reprocess [flags] <job-id>")
+	}
+
+	service, err := newJobService()
+	if err != nil {
+		return err
+	}
+
+	item, err := service.Reprocess(ctx, flags.Arg(0), model.JobOptions{
+		Language: *language,
+		Provider: *provider,
+		ModelID:  *modelID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJSON(item)
+}
+
+// runJobsCancel cancels a pending or running job
+func runJobsCancel(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("jobs cancel", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: transcriptionctl jobs cancel <job-id>")
+	}
+
+	service, err := newJobService()
+	if err != nil {
+		return err
+	}
+
+	item, err := service.Cancel(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(item)
+}
+
+// runJobsPurge erases a finished job and prints the purge audit record
+func runJobsPurge(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("jobs purge", flag.ContinueOnError)
+	requestedBy := flags.String("requested-by", os.Getenv("USER"), "who requested the purge, kept in the audit record")
+	reason := flags.String("reason", "", "justification kept in the audit record, e.g. a ticket reference")
+	source := flags.Bool("source", false, "also delete the source audio file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: transcriptionctl jobs purge [flags] <job-id>")
+	}
+
+	purger, err := newPurger()
+	if err != nil {
+		return err
+	}
+
+	record, err := purger.Purge(ctx, flags.Arg(0), jobs.PurgeOptions{
+		RequestedBy:  *requestedBy,
+		Reason:       *reason,
+		DeleteSource: *source,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJSON(record)
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,121 @@
+// Command transcriptionctl is an operator CLI for the transcription service.
+//
+// Usage:
+//
+//	transcriptionctl jobs list -status FAILED -since 24h
+//	transcriptionctl jobs reprocess -model scribe_v1 audio/call.mp3
+//	transcriptionctl jobs cancel audio/call.mp3
+//	transcriptionctl jobs purge -requested-by ops@example.com -reason DSR-1234 -source audio/call.mp3
+//	transcriptionctl usage report -since 2024-03-01 -until 2024-03-31 -format json
+//	transcriptionctl config validate -file config.yaml
+//	transcriptionctl config print -source ssm:/transcription/config -redacted
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/config"
+	"github.com/yourusername/transcription-service/internal/jobs"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	var err error
+	switch os.Args[1] + " " + os.Args[2] {
+	case "jobs list":
+		err = runJobsList(ctx, os.Args[3:])
+	case "jobs reprocess":
+		err = runJobsReprocess(ctx, os.Args[3:])
+	case "jobs cancel":
+		err = runJobsCancel(ctx, os.Args[3:])
+	case "jobs purge":
+		err = runJobsPurge(ctx, os.Args[3:])
+	case "usage report":
+		err = runUsageReport(ctx, os.Args[3:])
+	case "config validate":
+		err = runConfigValidate(ctx, os.Args[3:])
+	case "config print":
+		err = runConfigPrint(ctx, os.Args[3:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the supported commands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: transcriptionctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  jobs list        List transcription jobs by status, source bucket and time range")
+	fmt.Fprintln(os.Stderr, "  jobs reprocess   Re-run a job, optionally with a new language, provider or model")
+	fmt.Fprintln(os.Stderr, "  jobs cancel      Cancel a pending or running job")
+	fmt.Fprintln(os.Stderr, "  jobs purge       Erase a finished job and its transcripts, leaving an audit record")
+	fmt.Fprintln(os.Stderr, "  usage report     Summarize audio minutes and estimated cost per prefix for a date range")
+	fmt.Fprintln(os.Stderr, "  config validate  Check the merged configuration and list every problem")
+	fmt.Fprintln(os.Stderr, "  config print     Show each setting and the layer it came from (-redacted masks secrets)")
+}
+
+// newJobService builds the job service used by reprocess and cancel. Requeued jobs
+// are written as manifests to SUBMISSION_BUCKET, or to the audio file's bucket.
+func newJobService() (*jobs.Service, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AWS clients: %w", err)
+	}
+
+	store := awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName)
+	objects := awsclient.NewS3Operations(clients.GetS3())
+	return jobs.NewService(store, objects, cfg.SubmissionBucket), nil
+}
+
+// newDynamoDBOperations builds DynamoDB operations from the environment configuration
+func newDynamoDBOperations() (*awsclient.DynamoDBOperations, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AWS clients: %w", err)
+	}
+
+	return awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName), nil
+}
+
+// newPurger builds the purger used by jobs purge
+func newPurger() (*jobs.Purger, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := awsclient.NewClients(cfg.AWSRegion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AWS clients: %w", err)
+	}
+
+	store := awsclient.NewDynamoDBOperations(clients.GetDynamoDB(), cfg.DynamoDBTableName)
+	objects := awsclient.NewS3Operations(clients.GetS3())
+	return jobs.NewPurger(store, objects), nil
+}
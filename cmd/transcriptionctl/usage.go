@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	ledger "github.com/yourusername/transcription-service/internal/usage"
+)
+
+// runUsageReport prints the usage totals for a date range as CSV or JSON
+func runUsageReport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("usage report", flag.ContinueOnError)
+	since := flags.String("since", "720h", "first day: duration ago (720h), date (2006-01-02) or RFC3339")
+	until := flags.String("until", "", "last day: duration ago, date or RFC3339 (default today)")
+	format := flags.String("format", "csv", "output format: csv or json")
+	daily := flags.Bool("daily", false, "one row per day instead of totals for the whole range")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown -format %q", *format)
+	}
+
+	now := time.Now().UTC()
+	from, err := model.ParseTimeBound(*since, now)
+	if err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	to, err := model.ParseTimeBound(*until, now)
+	if err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() || from.After(to) {
+		return fmt.Errorf("-since must be before -until")
+	}
+
+	ops, err := newDynamoDBOperations()
+	if err != nil {
+		return err
+	}
+
+	totals, err := ops.QueryDailyUsage(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	summaries := ledger.Summarize(totals, *daily)
+	if *format == "json" {
+		return printJSON(summaries)
+	}
+	return ledger.WriteCSV(os.Stdout, summaries)
+}
@@ -0,0 +1,320 @@
+// Package api exposes transcription jobs over an API Gateway HTTP API (payload v2).
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/jobs"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+const (
+	// defaultInlineLimit is the largest transcript body returned inline; larger ones are redirected
+	defaultInlineLimit = 1 << 20
+
+	// presignExpirationSeconds is how long download redirects stay valid
+	presignExpirationSeconds = 900
+
+	// defaultPageSize is used when GET /jobs has no limit parameter
+	defaultPageSize = 50
+)
+
+// JobStore reads and records transcription job state
+type JobStore interface {
+	GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error)
+	QueryTranscriptionItems(ctx context.Context, query model.TranscriptionQuery) (*model.TranscriptionPage, error)
+	CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error
+	CancelTranscriptionItem(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error
+	RequeueTranscriptionItem(ctx context.Context, fileIdentifier string, batch model.BatchRef, record model.AttemptRecord) error
+	HydrateTranscript(ctx context.Context, item *model.TranscriptionItem) error
+}
+
+// ObjectStore reads and writes transcript objects
+type ObjectStore interface {
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+	ObjectSize(ctx context.Context, bucket, key string) (int64, error)
+	GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error)
+	UploadText(ctx context.Context, bucket, key, content string) error
+}
+
+// SubmitRequest is the body of POST /jobs
+type SubmitRequest struct {
+	// URI is the s3://bucket/key location of the audio file
+	URI string `json:"uri"`
+
+	// Options are the per-file job options
+	Options model.JobOptions `json:"options,omitempty"`
+}
+
+// Handler serves the jobs HTTP API
+type Handler struct {
+	jobs        JobStore
+	objects     ObjectStore
+	service     *jobs.Service
+	inlineLimit int
+	encrypter   *envelope.Encrypter
+	tenants     *tenant.Registry
+}
+
+// NewHandler creates a new API handler. Submitted jobs are written as manifests to
+// submissionBucket, or to the audio file's own bucket when it is empty.
+func NewHandler(store JobStore, objects ObjectStore, submissionBucket string) *Handler {
+	return &Handler{
+		jobs:        store,
+		objects:     objects,
+		service:     jobs.NewService(store, objects, submissionBucket),
+		inlineLimit: defaultInlineLimit,
+	}
+}
+
+// SetEncryption decrypts envelope-encrypted transcript objects before returning them. Encrypted
+// transcripts are only returned inline, never by redirect to S3.
+func (h *Handler) SetEncryption(encrypter *envelope.Encrypter) {
+	h.encrypter = encrypter
+}
+
+// HandleRequest routes an HTTP API request
+func (h *Handler) HandleRequest(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	// Every line logged for this request carries the Lambda request ID
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		ctx = logging.With(ctx, logging.KeyRequestID, lc.AwsRequestID)
+	}
+	logging.FromContext(ctx).Info("API request", "route", req.RouteKey)
+
+	if h.tenants != nil {
+		caller, ok := h.callerTenant(req)
+		if !ok {
+			return errorResponse(http.StatusForbidden, "request is not authorized for a tenant"), nil
+		}
+		ctx = tenant.NewContext(ctx, caller)
+		ctx = logging.With(ctx, logging.KeyTenant, caller.ID)
+	}
+
+	switch req.RouteKey {
+	case "GET /jobs":
+		return h.listJobs(ctx, req)
+	case "POST /jobs":
+		return h.submitJob(ctx, req)
+	case "GET /jobs/{id}":
+		return h.getJob(ctx, req)
+	case "GET /jobs/{id}/transcript":
+		return h.getTranscript(ctx, req)
+	case "POST /jobs/{id}/reprocess":
+		return h.reprocessJob(ctx, req)
+	case "POST /jobs/{id}/cancel":
+		return h.cancelJob(ctx, req)
+	default:
+		return errorResponse(http.StatusNotFound, "route not found"), nil
+	}
+}
+
+// getJob handles GET /jobs/{id}
+func (h *Handler) getJob(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	item, resp, ok := h.loadJob(ctx, req)
+	if !ok {
+		return resp, nil
+	}
+
+	return jsonResponse(ctx, http.StatusOK, item), nil
+}
+
+// listJobs handles GET /jobs?status=&bucket=&since=&until=&limit=&cursor=
+func (h *Handler) listJobs(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	params := req.QueryStringParameters
+
+	query := model.TranscriptionQuery{
+		Status:       model.TranscriptionStatus(params["status"]),
+		SourceBucket: params["bucket"],
+		Cursor:       params["cursor"],
+		Limit:        defaultPageSize,
+	}
+	if caller, ok := tenant.FromContext(ctx); ok {
+		query.TenantID = caller.ID
+	}
+
+	if query.Status == "" && query.SourceBucket == "" {
+		return errorResponse(http.StatusBadRequest, "status or bucket query parameter is required"), nil
+	}
+	if query.Status != "" && !query.Status.Valid() {
+		return errorResponse(http.StatusBadRequest, "unknown status "+string(query.Status)), nil
+	}
+
+	if limit := params["limit"]; limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return errorResponse(http.StatusBadRequest, "limit must be a positive integer"), nil
+		}
+		query.Limit = int32(n)
+	}
+
+	var err error
+	now := time.Now()
+	if query.Since, err = model.ParseTimeBound(params["since"], now); err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid since parameter"), nil
+	}
+	if query.Until, err = model.ParseTimeBound(params["until"], now); err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid until parameter"), nil
+	}
+
+	page, err := h.jobs.QueryTranscriptionItems(ctx, query)
+	if errors.Is(err, awsclient.ErrInvalidCursor) {
+		return errorResponse(http.StatusBadRequest, "invalid cursor"), nil
+	}
+	if err != nil {
+		return internalError(ctx, err), nil
+	}
+
+	return jsonResponse(ctx, http.StatusOK, page), nil
+}
+
+// submitJob handles POST /jobs
+func (h *Handler) submitJob(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	var submit SubmitRequest
+	if err := json.Unmarshal([]byte(req.Body), &submit); err != nil {
+		return errorResponse(http.StatusBadRequest, "request body must be JSON"), nil
+	}
+
+	bucket, key, err := awsclient.ParseS3URI(submit.URI)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, err.Error()), nil
+	}
+
+	if caller, ok := tenant.FromContext(ctx); ok {
+		if owner, ok := h.tenants.Resolve(bucket, key); !ok || owner.ID != caller.ID {
+			return errorResponse(http.StatusForbidden, "audio file does not belong to the tenant"), nil
+		}
+	}
+
+	item, accepted, err := h.service.Submit(ctx, bucket, key, submit.Options)
+	if err != nil {
+		return serviceError(ctx, err), nil
+	}
+
+	// Finished and running jobs are returned as-is rather than resubmitted
+	if !accepted {
+		return jsonResponse(ctx, http.StatusOK, item), nil
+	}
+
+	resp := jsonResponse(ctx, http.StatusAccepted, item)
+	resp.Headers["Location"] = "/jobs/" + url.PathEscape(key)
+	return resp, nil
+}
+
+// reprocessJob handles POST /jobs/{id}/reprocess with optional new job options as the body
+func (h *Handler) reprocessJob(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	id, resp, ok := jobID(req)
+	if !ok {
+		return resp, nil
+	}
+
+	var opts model.JobOptions
+	if req.Body != "" {
+		if err := json.Unmarshal([]byte(req.Body), &opts); err != nil {
+			return errorResponse(http.StatusBadRequest, "request body must be JSON job options"), nil
+		}
+	}
+
+	item, err := h.service.Reprocess(ctx, scopedID(ctx, id), opts)
+	if err != nil {
+		return serviceError(ctx, err), nil
+	}
+
+	return jsonResponse(ctx, http.StatusAccepted, item), nil
+}
+
+// cancelJob handles POST /jobs/{id}/cancel
+func (h *Handler) cancelJob(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	id, resp, ok := jobID(req)
+	if !ok {
+		return resp, nil
+	}
+
+	item, err := h.service.Cancel(ctx, scopedID(ctx, id))
+	if err != nil {
+		return serviceError(ctx, err), nil
+	}
+
+	return jsonResponse(ctx, http.StatusOK, item), nil
+}
+
+// loadJob fetches the job named by the {id} path parameter, or returns the error response to send
+func (h *Handler) loadJob(ctx context.Context, req events.APIGatewayV2HTTPRequest) (*model.TranscriptionItem, events.APIGatewayV2HTTPResponse, bool) {
+	id, resp, ok := jobID(req)
+	if !ok {
+		return nil, resp, false
+	}
+
+	item, err := h.jobs.GetTranscriptionItem(ctx, scopedID(ctx, id))
+	if err != nil {
+		return nil, internalError(ctx, err), false
+	}
+	if item == nil {
+		return nil, errorResponse(http.StatusNotFound, "job not found"), false
+	}
+
+	return item, events.APIGatewayV2HTTPResponse{}, true
+}
+
+// jobID extracts the {id} path parameter, or returns the error response to send
+func jobID(req events.APIGatewayV2HTTPRequest) (string, events.APIGatewayV2HTTPResponse, bool) {
+	// Job IDs are S3 keys, so clients percent-encode the slashes
+	id, err := url.PathUnescape(req.PathParameters["id"])
+	if err != nil || id == "" {
+		return "", errorResponse(http.StatusBadRequest, "invalid job id"), false
+	}
+	return id, events.APIGatewayV2HTTPResponse{}, true
+}
+
+// serviceError maps job service errors to responses
+func serviceError(ctx context.Context, err error) events.APIGatewayV2HTTPResponse {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return errorResponse(http.StatusNotFound, "job not found")
+	case errors.Is(err, jobs.ErrConflict):
+		return errorResponse(http.StatusConflict, err.Error())
+	default:
+		return internalError(ctx, err)
+	}
+}
+
+// jsonResponse builds a JSON response
+func jsonResponse(ctx context.Context, status int, body interface{}) events.APIGatewayV2HTTPResponse {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(data),
+	}
+}
+
+// errorResponse builds a JSON error response
+func errorResponse(status int, message string) events.APIGatewayV2HTTPResponse {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(data),
+	}
+}
+
+// internalError logs the cause and returns a generic 500 response
+func internalError(ctx context.Context, err error) events.APIGatewayV2HTTPResponse {
+	logging.FromContext(ctx).Error("Failed to handle API request", logging.KeyError, err)
+	return errorResponse(http.StatusInternalServerError, "internal error")
+}
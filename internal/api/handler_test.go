@@ -0,0 +1,374 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// MockJobStore is a mock implementation of JobStore
+type MockJobStore struct {
+	mock.Mock
+}
+
+func (m *MockJobStore) GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error) {
+	args := m.Called(ctx, fileIdentifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TranscriptionItem), args.Error(1)
+}
+
+func (m *MockJobStore) QueryTranscriptionItems(ctx context.Context, query model.TranscriptionQuery) (*model.TranscriptionPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TranscriptionPage), args.Error(1)
+}
+
+func (m *MockJobStore) CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockJobStore) CancelTranscriptionItem(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	args := m.Called(ctx, fileIdentifier, record)
+	return args.Error(0)
+}
+
+func (m *MockJobStore) RequeueTranscriptionItem(ctx context.Context, fileIdentifier string, batch model.BatchRef, record model.AttemptRecord) error {
+	args := m.Called(ctx, fileIdentifier, batch, record)
+	return args.Error(0)
+}
+
+func (m *MockJobStore) HydrateTranscript(ctx context.Context, item *model.TranscriptionItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+// MockObjectStore is a mock implementation of ObjectStore
+type MockObjectStore struct {
+	mock.Mock
+}
+
+func (m *MockObjectStore) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	args := m.Called(ctx, bucket, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockObjectStore) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	args := m.Called(ctx, bucket, key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockObjectStore) GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error) {
+	args := m.Called(ctx, bucket, key, expirationSeconds)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockObjectStore) UploadText(ctx context.Context, bucket, key, content string) error {
+	args := m.Called(ctx, bucket, key, content)
+	return args.Error(0)
+}
+
+func jobRequest(routeKey, id string, query map[string]string) events.APIGatewayV2HTTPRequest {
+	return events.APIGatewayV2HTTPRequest{
+		RouteKey:              routeKey,
+		PathParameters:        map[string]string{"id": id},
+		QueryStringParameters: query,
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	jobs := new(MockJobStore)
+	handler := NewHandler(jobs, new(MockObjectStore), "")
+	ctx := context.Background()
+
+	jobs.On("GetTranscriptionItem", ctx, "audio/call.aac").Return(&model.TranscriptionItem{
+		FileIdentifier: "audio/call.aac",
+		Status:         model.StatusCompleted,
+	}, nil)
+	jobs.On("GetTranscriptionItem", ctx, "audio/missing.aac").Return(nil, nil)
+
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}", "audio%2Fcall.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var item model.TranscriptionItem
+	assert.NoError(t, json.Unmarshal([]byte(resp.Body), &item))
+	assert.Equal(t, model.StatusCompleted, item.Status)
+
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}", "audio%2Fmissing.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestListJobs(t *testing.T) {
+	jobs := new(MockJobStore)
+	handler := NewHandler(jobs, new(MockObjectStore), "")
+	ctx := context.Background()
+
+	jobs.On("QueryTranscriptionItems", ctx, mock.MatchedBy(func(q model.TranscriptionQuery) bool {
+		return q.Status == model.StatusFailed && !q.Since.IsZero() && q.Limit == 5
+	})).Return(&model.TranscriptionPage{NextCursor: "next"}, nil)
+
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs", "", map[string]string{
+		"status": "FAILED",
+		"since":  "24h",
+		"limit":  "5",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Body, `"nextCursor":"next"`)
+
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs", "", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs", "", map[string]string{"status": "FAILED", "since": "yesterday"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSubmitJob(t *testing.T) {
+	jobs := new(MockJobStore)
+	objects := new(MockObjectStore)
+	handler := NewHandler(jobs, objects, "submissions")
+	ctx := context.Background()
+
+	jobs.On("GetTranscriptionItem", ctx, "audio/call.aac").Return(nil, nil)
+	jobs.On("CreateTranscriptionItem", ctx, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.Status == model.StatusPending && item.SourceBucket == "input" && item.LanguageHint == "de"
+	})).Return(nil)
+	objects.On("UploadText", ctx, "submissions", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "jobs/dispatch/job-")
+	}), mock.MatchedBy(func(content string) bool {
+		var manifest model.JobManifest
+		return json.Unmarshal([]byte(content), &manifest) == nil &&
+			manifest.Files[0].Bucket == "input" &&
+			manifest.Files[0].Key == "audio/call.aac" &&
+			manifest.Files[0].Options.Language == "de"
+	})).Return(nil)
+
+	resp, err := handler.HandleRequest(ctx, events.APIGatewayV2HTTPRequest{
+		RouteKey: "POST /jobs",
+		Body:     `{"uri":"s3://input/audio/call.aac","options":{"language":"de"}}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "/jobs/audio%2Fcall.aac", resp.Headers["Location"])
+	jobs.AssertExpectations(t)
+	objects.AssertExpectations(t)
+
+	resp, err = handler.HandleRequest(ctx, events.APIGatewayV2HTTPRequest{
+		RouteKey: "POST /jobs",
+		Body:     `{"uri":"https://example.com/a.mp3"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetTranscript(t *testing.T) {
+	jobs := new(MockJobStore)
+	objects := new(MockObjectStore)
+	handler := NewHandler(jobs, objects, "")
+	ctx := context.Background()
+
+	jobs.On("GetTranscriptionItem", ctx, "audio/call.aac").Return(&model.TranscriptionItem{
+		FileIdentifier: "audio/call.aac",
+		Status:         model.StatusCompleted,
+		TranscriptText: "Hello world.",
+		OutputLocation: "s3://output/transcripts/call.txt",
+	}, nil)
+	jobs.On("GetTranscriptionItem", ctx, "audio/running.aac").Return(&model.TranscriptionItem{
+		Status: model.StatusTranscribing,
+	}, nil)
+
+	structured, _ := json.Marshal(model.Transcript{
+		Text: "Hello world.",
+		Words: []model.Word{
+			{Text: "Hello", Start: 0, End: 0.4, Type: "word"},
+			{Text: " ", Start: 0.4, End: 0.5, Type: "spacing"},
+			{Text: "world.", Start: 0.5, End: 1, Type: "word"},
+		},
+	})
+	objects.On("ReadObject", ctx, "output", "transcripts/call.json").Return(structured, nil)
+
+	// Plain text is served inline from the item
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Hello world.", resp.Body)
+
+	// Subtitles are rendered from word timings
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "vtt"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/vtt", resp.Headers["Content-Type"])
+	assert.Equal(t, "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\nHello world.\n\n", resp.Body)
+
+	// Large outputs are redirected to a presigned URL
+	handler.inlineLimit = 5
+	objects.On("GeneratePresignedURL", ctx, "output", "transcripts/call.txt", presignExpirationSeconds).Return("https://presigned", nil)
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "txt"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://presigned", resp.Headers["Location"])
+
+	// Large subtitles are refused rather than stored where retention and purges can't reach them
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "srt"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	objects.AssertNotCalled(t, "UploadText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Unfinished jobs have no transcript yet
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Frunning.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "docx"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetTranscript_External(t *testing.T) {
+	jobs := new(MockJobStore)
+	objects := new(MockObjectStore)
+	handler := NewHandler(jobs, objects, "")
+	ctx := context.Background()
+
+	ref := model.NewTranscriptRef("s3://audio/artifacts/transcripts/audio/long.aac.txt", "A very long transcript.")
+	for i := 0; i < 2; i++ {
+		jobs.On("GetTranscriptionItem", ctx, "audio/long.aac").Return(&model.TranscriptionItem{
+			FileIdentifier: "audio/long.aac",
+			Status:         model.StatusCompleted,
+			TranscriptRef:  ref,
+		}, nil).Once()
+	}
+	jobs.On("HydrateTranscript", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.TranscriptionItem).TranscriptText = "A very long transcript."
+	}).Return(nil).Once()
+
+	// Transcripts stored outside the item are loaded for inline responses
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Flong.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "A very long transcript.", resp.Body)
+
+	// and redirected to when they're too large to return
+	handler.inlineLimit = 5
+	objects.On("GeneratePresignedURL", ctx, "audio", "artifacts/transcripts/audio/long.aac.txt", presignExpirationSeconds).Return("https://presigned", nil)
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Flong.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://presigned", resp.Headers["Location"])
+	jobs.AssertExpectations(t)
+}
+
+func TestGetTranscript_Encrypted(t *testing.T) {
+	jobs := new(MockJobStore)
+	objects := new(MockObjectStore)
+	handler := NewHandler(jobs, objects, "")
+	ctx := context.Background()
+
+	keys, err := envelope.NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := envelope.NewEncrypter(keys, "alias/transcripts")
+	handler.SetEncryption(enc)
+
+	// The store has already decrypted the item's text
+	jobs.On("GetTranscriptionItem", ctx, "audio/call.aac").Return(&model.TranscriptionItem{
+		FileIdentifier: "audio/call.aac",
+		Status:         model.StatusCompleted,
+		TranscriptText: "Hello world.",
+		OutputLocation: "s3://output/transcripts/call.txt",
+	}, nil)
+
+	structured, _ := json.Marshal(model.Transcript{
+		Text: "Hello world.",
+		Words: []model.Word{
+			{Text: "Hello", Start: 0, End: 0.4, Type: "word"},
+			{Text: " ", Start: 0.4, End: 0.5, Type: "spacing"},
+			{Text: "world.", Start: 0.5, End: 1, Type: "word"},
+		},
+	})
+	sealed, err := enc.SealObject(ctx, structured, map[string]string{"FileIdentifier": "audio/call.aac"})
+	assert.NoError(t, err)
+	objects.On("ObjectSize", ctx, "output", "transcripts/call.json").Return(int64(len(sealed)), nil)
+	objects.On("ReadObject", ctx, "output", "transcripts/call.json").Return(sealed, nil)
+
+	// Stored outputs are decrypted before they're returned
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "json"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, string(structured), resp.Body)
+
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", map[string]string{"format": "srt"}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Body, "Hello world.")
+
+	// Encrypted objects are never redirected to
+	handler.inlineLimit = 5
+	resp, err = handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}/transcript", "audio%2Fcall.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	objects.AssertNotCalled(t, "GeneratePresignedURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReprocessAndCancelJob(t *testing.T) {
+	store := new(MockJobStore)
+	objects := new(MockObjectStore)
+	handler := NewHandler(store, objects, "")
+	ctx := context.Background()
+
+	store.On("GetTranscriptionItem", ctx, "audio/done.aac").Return(&model.TranscriptionItem{
+		FileIdentifier: "audio/done.aac",
+		Status:         model.StatusCompleted,
+		SourceBucket:   "input",
+		SourceKey:      "audio/done.aac",
+	}, nil)
+	store.On("RequeueTranscriptionItem", ctx, "audio/done.aac", mock.MatchedBy(func(batch model.BatchRef) bool {
+		return batch.TenantID == "" && strings.HasPrefix(batch.BatchID, "job-")
+	}), mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Provider == "whisper" && strings.Contains(record.Note, "reprocess")
+	})).Return(nil)
+	objects.On("UploadText", ctx, "input", mock.Anything, mock.MatchedBy(func(content string) bool {
+		return strings.Contains(content, `"provider":"whisper"`)
+	})).Return(nil)
+
+	resp, err := handler.HandleRequest(ctx, events.APIGatewayV2HTTPRequest{
+		RouteKey:       "POST /jobs/{id}/reprocess",
+		PathParameters: map[string]string{"id": "audio%2Fdone.aac"},
+		Body:           `{"provider":"whisper"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Contains(t, resp.Body, `"status":"PENDING"`)
+
+	// Completed jobs can't be cancelled
+	store.On("CancelTranscriptionItem", ctx, "audio/done.aac", mock.Anything).Return(awsclient.ErrStatusConflict)
+	resp, err = handler.HandleRequest(ctx, jobRequest("POST /jobs/{id}/cancel", "audio%2Fdone.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	store.On("GetTranscriptionItem", ctx, "audio/missing.aac").Return(nil, nil)
+	resp, err = handler.HandleRequest(ctx, jobRequest("POST /jobs/{id}/cancel", "audio%2Fmissing.aac", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	store.AssertExpectations(t)
+	objects.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// TenantClaim is the JWT claim, or Lambda authorizer context key, naming the caller's tenant
+const TenantClaim = "tenant_id"
+
+// SetTenants restricts every request to the caller's tenant, named by the authorizer. Job IDs in
+// paths are the tenant's own input keys, listings only return the tenant's jobs and submissions must
+// be for audio the tenant owns.
+func (h *Handler) SetTenants(registry *tenant.Registry) {
+	h.tenants = registry
+	h.service.SetTenants(registry)
+}
+
+// callerTenant returns the tenant named by the request's JWT or Lambda authorizer
+func (h *Handler) callerTenant(req events.APIGatewayV2HTTPRequest) (*tenant.Tenant, bool) {
+	authorizer := req.RequestContext.Authorizer
+	if authorizer == nil {
+		return nil, false
+	}
+
+	var id string
+	if authorizer.JWT != nil {
+		id = authorizer.JWT.Claims[TenantClaim]
+	}
+	if id == "" {
+		id, _ = authorizer.Lambda[TenantClaim].(string)
+	}
+	if id == "" {
+		return nil, false
+	}
+	return h.tenants.Get(id)
+}
+
+// scopedID returns the stored identifier of a job ID given in a request. The caller's tenant can
+// only name jobs in its own partition.
+func scopedID(ctx context.Context, id string) string {
+	t, ok := tenant.FromContext(ctx)
+	if !ok {
+		return id
+	}
+
+	partition := model.TenantJobID(t.ID, "")
+	if strings.HasPrefix(id, partition) {
+		return id
+	}
+	return partition + id
+}
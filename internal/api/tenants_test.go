@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// asTenant adds a JWT authorizer naming the caller's tenant to a request
+func asTenant(req events.APIGatewayV2HTTPRequest, tenantID string) events.APIGatewayV2HTTPRequest {
+	req.RequestContext.Authorizer = &events.APIGatewayV2HTTPRequestContextAuthorizerDescription{
+		JWT: &events.APIGatewayV2HTTPRequestContextAuthorizerJWTDescription{
+			Claims: map[string]string{TenantClaim: tenantID},
+		},
+	}
+	return req
+}
+
+func TestTenantIsolation(t *testing.T) {
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "support", "bucket": "audio", "prefix": "support/"},
+		{"id": "sales", "bucket": "audio", "prefix": "sales/"}
+	]}`))
+	assert.NoError(t, err)
+
+	jobs := new(MockJobStore)
+	handler := NewHandler(jobs, new(MockObjectStore), "")
+	handler.SetTenants(registry)
+	ctx := context.Background()
+
+	// Requests without a known tenant are refused
+	resp, err := handler.HandleRequest(ctx, jobRequest("GET /jobs/{id}", "support%2Fcall.mp3", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = handler.HandleRequest(ctx, asTenant(jobRequest("GET /jobs/{id}", "support%2Fcall.mp3", nil), "marketing"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Job IDs only reach the caller's partition
+	jobs.On("GetTranscriptionItem", mock.Anything, "tenant#support#support/call.mp3").Return(&model.TranscriptionItem{
+		FileIdentifier: "tenant#support#support/call.mp3",
+		TenantID:       "support",
+		Status:         model.StatusCompleted,
+	}, nil)
+	jobs.On("GetTranscriptionItem", mock.Anything, "tenant#sales#support/call.mp3").Return(nil, nil)
+
+	resp, err = handler.HandleRequest(ctx, asTenant(jobRequest("GET /jobs/{id}", "support%2Fcall.mp3", nil), "support"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = handler.HandleRequest(ctx, asTenant(jobRequest("GET /jobs/{id}", "tenant%23support%23support%2Fcall.mp3", nil), "support"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = handler.HandleRequest(ctx, asTenant(jobRequest("GET /jobs/{id}", "support%2Fcall.mp3", nil), "sales"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Listings are filtered to the caller's jobs
+	jobs.On("QueryTranscriptionItems", mock.Anything, mock.MatchedBy(func(q model.TranscriptionQuery) bool {
+		return q.TenantID == "sales" && q.SourceBucket == "audio"
+	})).Return(&model.TranscriptionPage{}, nil)
+
+	resp, err = handler.HandleRequest(ctx, asTenant(jobRequest("GET /jobs", "", map[string]string{"bucket": "audio"}), "sales"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Another tenant's audio can't be submitted
+	submit := asTenant(jobRequest("POST /jobs", "", nil), "sales")
+	submit.Body = `{"uri": "s3://audio/support/call.mp3"}`
+	resp, err = handler.HandleRequest(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	jobs.AssertExpectations(t)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/transcript"
+)
+
+// contentTypes maps transcript formats to their response content type
+var contentTypes = map[string]string{
+	"txt":  "text/plain; charset=utf-8",
+	"json": "application/json",
+	"srt":  "application/x-subrip",
+	"vtt":  "text/vtt",
+}
+
+// getTranscript handles GET /jobs/{id}/transcript?format=txt|json|srt|vtt
+func (h *Handler) getTranscript(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	format := req.QueryStringParameters["format"]
+	if format == "" {
+		format = "txt"
+	}
+	if _, ok := contentTypes[format]; !ok {
+		return errorResponse(http.StatusBadRequest, "format must be one of txt, json, srt, vtt"), nil
+	}
+
+	item, resp, ok := h.loadJob(ctx, req)
+	if !ok {
+		return resp, nil
+	}
+
+	if item.Status != model.StatusCompleted {
+		return errorResponse(http.StatusConflict, fmt.Sprintf("transcript not available, job status is %s", item.Status)), nil
+	}
+
+	// Transcripts too large for the item are kept in S3; load them unless they're too large to return anyway
+	if item.TranscriptRef != nil && item.TranscriptRef.Size <= h.inlineLimit {
+		if err := h.jobs.HydrateTranscript(ctx, item); err != nil {
+			return internalError(ctx, err), nil
+		}
+	}
+
+	switch format {
+	case "txt":
+		return h.textTranscript(ctx, item)
+	case "json":
+		return h.jsonTranscript(ctx, item)
+	default:
+		return h.subtitleTranscript(ctx, item, format)
+	}
+}
+
+// textTranscript returns the plain text inline, or redirects to the S3 copy when it's large
+func (h *Handler) textTranscript(ctx context.Context, item *model.TranscriptionItem) (events.APIGatewayV2HTTPResponse, error) {
+	if item.TranscriptText != "" && (len(item.TranscriptText) <= h.inlineLimit || item.OutputLocation == "") {
+		return textResponse("txt", item.TranscriptText), nil
+	}
+
+	location := item.OutputLocation
+	if location == "" && item.TranscriptRef != nil {
+		location = item.TranscriptRef.Location
+	}
+	if location == "" {
+		return errorResponse(http.StatusNotFound, "transcript is empty"), nil
+	}
+
+	// Encrypted objects are useless to the client, so they are never handed out
+	if h.encrypter != nil {
+		return tooLarge(), nil
+	}
+
+	bucket, key, err := awsclient.ParseS3URI(location)
+	if err != nil {
+		return internalError(ctx, err), nil
+	}
+	return h.redirect(ctx, bucket, key)
+}
+
+// jsonTranscript returns the structured transcript, falling back to the item fields when none was stored
+func (h *Handler) jsonTranscript(ctx context.Context, item *model.TranscriptionItem) (events.APIGatewayV2HTTPResponse, error) {
+	if item.OutputLocation != "" {
+		bucket, textKey, err := awsclient.ParseS3URI(item.OutputLocation)
+		if err != nil {
+			return internalError(ctx, err), nil
+		}
+		key := model.StructuredOutputKey(textKey)
+
+		// A missing structured object just means the provider returned no word timings
+		if size, err := h.objects.ObjectSize(ctx, bucket, key); err == nil {
+			if size > int64(h.inlineLimit) {
+				if h.encrypter != nil {
+					return tooLarge(), nil
+				}
+				return h.redirect(ctx, bucket, key)
+			}
+
+			data, err := h.readObject(ctx, item.FileIdentifier, bucket, key)
+			if err != nil {
+				return internalError(ctx, err), nil
+			}
+			return textResponse("json", string(data)), nil
+		}
+	}
+
+	return jsonResponse(ctx, http.StatusOK, model.Transcript{
+		FileIdentifier:      item.FileIdentifier,
+		Text:                item.TranscriptText,
+		LanguageCode:        item.DetectedLanguage,
+		LanguageProbability: item.LanguageProbability,
+	}), nil
+}
+
+// subtitleTranscript renders SRT or WebVTT from the stored word timings on every request
+func (h *Handler) subtitleTranscript(ctx context.Context, item *model.TranscriptionItem, format string) (events.APIGatewayV2HTTPResponse, error) {
+	if item.OutputLocation == "" {
+		return errorResponse(http.StatusNotFound, "word timings are not available for this job"), nil
+	}
+
+	bucket, textKey, err := awsclient.ParseS3URI(item.OutputLocation)
+	if err != nil {
+		return internalError(ctx, err), nil
+	}
+
+	data, err := h.objects.ReadObject(ctx, bucket, model.StructuredOutputKey(textKey))
+	if err != nil {
+		return errorResponse(http.StatusNotFound, "word timings are not available for this job"), nil
+	}
+	if h.encrypter != nil {
+		if data, err = h.encrypter.OpenObject(ctx, data, envelope.JobContext(item.FileIdentifier)); err != nil {
+			return internalError(ctx, err), nil
+		}
+	}
+
+	var structured model.Transcript
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return internalError(ctx, fmt.Errorf("failed to unmarshal structured transcript: %w", err)), nil
+	}
+	if len(structured.Words) == 0 {
+		return errorResponse(http.StatusNotFound, "word timings are not available for this job"), nil
+	}
+
+	cues := transcript.BuildCues(structured.Words)
+	var body string
+	if format == "srt" {
+		body = transcript.FormatSRT(cues)
+	} else {
+		body = transcript.FormatVTT(cues)
+	}
+
+	// Renderings are never stored, so they can't outlive the transcript's retention or a purge
+	if len(body) > h.inlineLimit {
+		return errorResponse(http.StatusRequestEntityTooLarge, "subtitles are too large to return, download the json transcript instead"), nil
+	}
+	return textResponse(format, body), nil
+}
+
+// readObject reads one of a job's S3 objects, decrypting it when encryption is enabled
+func (h *Handler) readObject(ctx context.Context, fileID, bucket, key string) ([]byte, error) {
+	data, err := h.objects.ReadObject(ctx, bucket, key)
+	if err != nil || h.encrypter == nil {
+		return data, err
+	}
+	return h.encrypter.OpenObject(ctx, data, envelope.JobContext(fileID))
+}
+
+// tooLarge answers for encrypted transcripts that can't be returned inline or by redirect
+func tooLarge() events.APIGatewayV2HTTPResponse {
+	return errorResponse(http.StatusRequestEntityTooLarge, "transcript is encrypted and too large to return")
+}
+
+// redirect answers with a 302 to a presigned download URL
+func (h *Handler) redirect(ctx context.Context, bucket, key string) (events.APIGatewayV2HTTPResponse, error) {
+	location, err := h.objects.GeneratePresignedURL(ctx, bucket, key, presignExpirationSeconds)
+	if err != nil {
+		return internalError(ctx, err), nil
+	}
+
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusFound,
+		Headers:    map[string]string{"Location": location},
+	}, nil
+}
+
+// textResponse returns a transcript body with the content type for its format
+func textResponse(format, body string) events.APIGatewayV2HTTPResponse {
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"Content-Type": contentTypes[format]},
+		Body:       body,
+	}
+}
@@ -5,15 +5,24 @@ import (
 	
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/yourusername/transcription-service/internal/tracing"
 )
 
 // Clients holds all AWS service clients
 type Clients struct {
-	s3Client         *s3.Client
-	dynamoDBClient   *dynamodb.Client
-	secretsClient    *secretsmanager.Client
+	s3Client          *s3.Client
+	dynamoDBClient    *dynamodb.Client
+	secretsClient     *secretsmanager.Client
+	snsClient         *sns.Client
+	eventBridgeClient *eventbridge.Client
+	kmsClient         *kms.Client
+	ssmClient         *ssm.Client
 }
 
 // NewClients initializes all AWS service clients
@@ -25,16 +34,25 @@ func NewClients(region string) (*Clients, error) {
 	if err != nil {
 		return nil, err
 	}
+	tracing.InstrumentAWS(&cfg)
 	
 	// Create service clients
 	s3Client := s3.NewFromConfig(cfg)
 	dynamoDBClient := dynamodb.NewFromConfig(cfg)
 	secretsClient := secretsmanager.NewFromConfig(cfg)
+	snsClient := sns.NewFromConfig(cfg)
+	eventBridgeClient := eventbridge.NewFromConfig(cfg)
+	kmsClient := kms.NewFromConfig(cfg)
+	ssmClient := ssm.NewFromConfig(cfg)
 	
 	return &Clients{
-		s3Client:         s3Client,
-		dynamoDBClient:   dynamoDBClient,
-		secretsClient:    secretsClient,
+		s3Client:          s3Client,
+		dynamoDBClient:    dynamoDBClient,
+		secretsClient:     secretsClient,
+		snsClient:         snsClient,
+		eventBridgeClient: eventBridgeClient,
+		kmsClient:         kmsClient,
+		ssmClient:         ssmClient,
 	}, nil
 }
 
@@ -53,6 +71,26 @@ func (c *Clients) GetSecretsManager() *secretsmanager.Client {
 	return c.secretsClient
 }
 
+// GetSNS returns the SNS client
+func (c *Clients) GetSNS() *sns.Client {
+	return c.snsClient
+}
+
+// GetEventBridge returns the EventBridge client
+func (c *Clients) GetEventBridge() *eventbridge.Client {
+	return c.eventBridgeClient
+}
+
+// GetKMS returns the KMS client
+func (c *Clients) GetKMS() *kms.Client {
+	return c.kmsClient
+}
+
+// GetSSM returns the SSM client
+func (c *Clients) GetSSM() *ssm.Client {
+	return c.ssmClient
+}
+
 // GetClients is a utility function to create clients directly
 // Useful for testing and mock replacement
 func GetClients(region string) (*s3.Client, *dynamodb.Client, *secretsmanager.Client) {
@@ -62,6 +100,7 @@ func GetClients(region string) (*s3.Client, *dynamodb.Client, *secretsmanager.Cl
 	if err != nil {
 		return nil, nil, nil
 	}
+	tracing.InstrumentAWS(&cfg)
 	
 	s3Client := s3.NewFromConfig(cfg)
 	dynamoDBClient := dynamodb.NewFromConfig(cfg)
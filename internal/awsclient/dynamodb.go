@@ -2,25 +2,45 @@ package awsclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/logging"
 	"github.com/yourusername/transcription-service/internal/model"
 )
 
+// ErrBatchExists is returned when a batch with the same ID has already been recorded
+var ErrBatchExists = errors.New("batch already exists")
+
+// ErrStatusConflict is returned when a conditional status change doesn't apply to the item's current state.
+// Rejected state machine transitions are reported as *TransitionError, which matches it.
+var ErrStatusConflict = errors.New("item status does not allow this operation")
+
+// DynamoDBClient is the subset of the DynamoDB API used by DynamoDBOperations
+type DynamoDBClient interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
 // DynamoDBOperations provides operations for working with DynamoDB
 type DynamoDBOperations struct {
-	client    *dynamodb.Client
-	tableName string
+	client      DynamoDBClient
+	tableName   string
+	transcripts TranscriptReader
+	encrypter   *envelope.Encrypter
 }
 
 // NewDynamoDBOperations creates a new DynamoDBOperations instance
-func NewDynamoDBOperations(client *dynamodb.Client, tableName string) *DynamoDBOperations {
+func NewDynamoDBOperations(client DynamoDBClient, tableName string) *DynamoDBOperations {
 	return &DynamoDBOperations{
 		client:    client,
 		tableName: tableName,
@@ -29,10 +49,16 @@ func NewDynamoDBOperations(client *dynamodb.Client, tableName string) *DynamoDBO
 
 // CreateTranscriptionItem creates a new transcription item in DynamoDB
 func (d *DynamoDBOperations) CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error {
-	// Set timestamps
-	now := time.Now()
+	// Set timestamps (UTC, whole seconds, so index range keys sort chronologically)
+	now := time.Now().UTC().Truncate(time.Second)
 	item.CreatedAt = now
 	item.UpdatedAt = now
+	item.Version = 1
+	
+	// New jobs may only start in an initial state
+	if err := model.ValidateTransition("", item.Status); err != nil {
+		return err
+	}
 	
 	// Marshal item to DynamoDB attribute values
 	av, err := attributevalue.MarshalMap(item)
@@ -40,82 +66,49 @@ func (d *DynamoDBOperations) CreateTranscriptionItem(ctx context.Context, item *
 		return fmt.Errorf("failed to marshal item: %w", err)
 	}
 	
-	// Put item in DynamoDB
+	// Put item in DynamoDB, refusing to overwrite an existing job
 	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(d.tableName),
-		Item:      av,
+		TableName:                           aws.String(d.tableName),
+		Item:                                av,
+		ConditionExpression:                 aws.String("attribute_not_exists(FileIdentifier)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	})
 	
 	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return transitionFailure(item.FileIdentifier, item.Status, condErr.Item)
+		}
 		return fmt.Errorf("failed to put item in DynamoDB: %w", err)
 	}
 	
-	log.Printf("Created DynamoDB item for file: %s", item.FileIdentifier)
+	logging.FromContext(ctx).Info("Created DynamoDB item", logging.KeyFile, item.FileIdentifier, "status", item.Status)
 	return nil
 }
 
-// UpdateTranscriptionItemStatus updates the status of a transcription item
-func (d *DynamoDBOperations) UpdateTranscriptionItemStatus(
-	ctx context.Context, 
-	fileIdentifier string, 
-	status model.TranscriptionStatus,
-	transcriptText string,
-	outputLocation string,
-	errorMessage string,
-	processingTime float64,
+// UpdateTranscriptionLanguage records the detected language and the provider/model that produced the transcript
+func (d *DynamoDBOperations) UpdateTranscriptionLanguage(
+	ctx context.Context,
+	fileIdentifier string,
+	detectedLanguage string,
+	probability float64,
+	route model.LanguageRoute,
 ) error {
-	// Build update expression
-	updateExpression := "SET #status = :status, #updatedAt = :updatedAt"
-	expressionAttributeNames := map[string]string{
-		"#status":    "Status",
-		"#updatedAt": "UpdatedAt",
+	update := NewItemUpdate(fileIdentifier)
+	if detectedLanguage != "" {
+		update.Set(AttrDetectedLanguage, detectedLanguage).Set(AttrLanguageProbability, probability)
 	}
-	expressionAttributeValues := map[string]types.AttributeValue{
-		":status":    &types.AttributeValueMemberS{Value: string(status)},
-		":updatedAt": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+	if route.Provider != "" {
+		update.Set(AttrProvider, route.Provider)
 	}
-	
-	// Add optional attributes if provided
-	if transcriptText != "" {
-		updateExpression += ", #transcriptText = :transcriptText"
-		expressionAttributeNames["#transcriptText"] = "TranscriptText"
-		expressionAttributeValues[":transcriptText"] = &types.AttributeValueMemberS{Value: transcriptText}
-	}
-	
-	if outputLocation != "" {
-		updateExpression += ", #outputLocation = :outputLocation"
-		expressionAttributeNames["#outputLocation"] = "OutputLocation"
-		expressionAttributeValues[":outputLocation"] = &types.AttributeValueMemberS{Value: outputLocation}
+	if route.ModelID != "" {
+		update.Set(AttrModelID, route.ModelID)
 	}
 	
-	if errorMessage != "" {
-		updateExpression += ", #errorMessage = :errorMessage"
-		expressionAttributeNames["#errorMessage"] = "ErrorMessage"
-		expressionAttributeValues[":errorMessage"] = &types.AttributeValueMemberS{Value: errorMessage}
+	if err := d.UpdateTranscriptionItem(ctx, update); err != nil {
+		return fmt.Errorf("failed to update language: %w", err)
 	}
 	
-	if processingTime > 0 {
-		updateExpression += ", #processingTime = :processingTime"
-		expressionAttributeNames["#processingTime"] = "ProcessingTime"
-		expressionAttributeValues[":processingTime"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%f", processingTime)}
-	}
-	
-	// Update item in DynamoDB
-	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
-		TableName: aws.String(d.tableName),
-		Key: map[string]types.AttributeValue{
-			"FileIdentifier": &types.AttributeValueMemberS{Value: fileIdentifier},
-		},
-		UpdateExpression:          aws.String(updateExpression),
-		ExpressionAttributeNames:  expressionAttributeNames,
-		ExpressionAttributeValues: expressionAttributeValues,
-	})
-	
-	if err != nil {
-		return fmt.Errorf("failed to update item in DynamoDB: %w", err)
-	}
-	
-	log.Printf("Updated DynamoDB item status to %s for file: %s", status, fileIdentifier)
 	return nil
 }
 
@@ -144,5 +137,219 @@ func (d *DynamoDBOperations) GetTranscriptionItem(ctx context.Context, fileIdent
 		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
 	}
 	
+	// Encrypted transcripts are returned as plain text when the operations can decrypt them
+	if err := d.decryptTranscript(ctx, &item); err != nil {
+		return nil, err
+	}
+	
 	return &item, nil
-}
\ No newline at end of file
+}
+
+// CreateBatchItem records a new manifest batch; it returns ErrBatchExists if the batch ID was already used
+func (d *DynamoDBOperations) CreateBatchItem(ctx context.Context, batch *model.BatchItem) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	batch.FileIdentifier = model.BatchKey(batch.TenantID, batch.BatchID)
+	batch.CreatedAt = now
+	batch.UpdatedAt = now
+	
+	av, err := attributevalue.MarshalMap(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+	
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(FileIdentifier)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrBatchExists
+		}
+		return fmt.Errorf("failed to put batch in DynamoDB: %w", err)
+	}
+	
+	logging.FromContext(ctx).Info("Created DynamoDB batch item", "batch", batch.BatchID, "files", batch.TotalFiles)
+	return nil
+}
+
+// GetBatchItem returns a manifest batch, or nil if it doesn't exist
+func (d *DynamoDBOperations) GetBatchItem(ctx context.Context, tenantID, batchID string) (*model.BatchItem, error) {
+	result, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.BatchKey(tenantID, batchID)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch from DynamoDB: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	batch := &model.BatchItem{}
+	if err := attributevalue.UnmarshalMap(result.Item, batch); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch: %w", err)
+	}
+	return batch, nil
+}
+
+// RecordBatchProgress atomically counts a job's outcome against a batch and returns the updated batch.
+// Each job is counted once; a job that was already counted leaves the batch unchanged. When the last
+// file finishes the batch status is moved to its terminal value; finalized is true only for the single
+// call that performed that transition.
+func (d *DynamoDBOperations) RecordBatchProgress(ctx context.Context, tenantID, batchID, fileID string, succeeded bool) (batch *model.BatchItem, finalized bool, err error) {
+	counter := "FailedFiles"
+	if succeeded {
+		counter = "CompletedFiles"
+	}
+	
+	key := map[string]types.AttributeValue{
+		"FileIdentifier": &types.AttributeValueMemberS{Value: model.BatchKey(tenantID, batchID)},
+	}
+	
+	result, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(d.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("ADD #counter :one, #counted :files SET #updatedAt = :updatedAt"),
+		ConditionExpression: aws.String("attribute_exists(FileIdentifier) AND NOT contains(#counted, :file)"),
+		ExpressionAttributeNames: map[string]string{
+			"#counter":   counter,
+			"#counted":   "CountedFiles",
+			"#updatedAt": "UpdatedAt",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":       &types.AttributeValueMemberN{Value: "1"},
+			":files":     &types.AttributeValueMemberSS{Value: []string{fileID}},
+			":file":      &types.AttributeValueMemberS{Value: fileID},
+			":updatedAt": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if !errors.As(err, &condErr) {
+			return nil, false, fmt.Errorf("failed to update batch progress in DynamoDB: %w", err)
+		}
+		if condErr.Item == nil {
+			return nil, false, fmt.Errorf("%w: %s", ErrItemNotFound, model.BatchKey(tenantID, batchID))
+		}
+
+		// The job was counted before
+		batch = &model.BatchItem{}
+		if err := attributevalue.UnmarshalMap(condErr.Item, batch); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal batch: %w", err)
+		}
+		return batch, false, nil
+	}
+	
+	batch = &model.BatchItem{}
+	if err := attributevalue.UnmarshalMap(result.Attributes, batch); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal batch: %w", err)
+	}
+	
+	if !batch.Done() || batch.BatchStatus != model.BatchStatusInProgress {
+		return batch, false, nil
+	}
+	
+	finalStatus := model.BatchStatusCompleted
+	if batch.FailedFiles > 0 {
+		finalStatus = model.BatchStatusCompletedWithErrors
+	}
+	
+	// Only the writer that observes the batch as done and still in progress flips the status
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(d.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET #batchStatus = :final"),
+		ConditionExpression: aws.String("#batchStatus = :inProgress"),
+		ExpressionAttributeNames: map[string]string{
+			"#batchStatus": "BatchStatus",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":final":      &types.AttributeValueMemberS{Value: string(finalStatus)},
+			":inProgress": &types.AttributeValueMemberS{Value: string(model.BatchStatusInProgress)},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			// Another invocation already finalized the batch
+			return batch, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to finalize batch in DynamoDB: %w", err)
+	}
+	
+	batch.BatchStatus = finalStatus
+	logging.FromContext(ctx).Info("Batch finished", "batch", batchID, "status", finalStatus)
+	return batch, true, nil
+}
+
+// AppendAttempt appends a record to the item's append-only History list
+func (d *DynamoDBOperations) AppendAttempt(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	if err := d.UpdateTranscriptionItem(ctx, NewItemUpdate(fileIdentifier).Append(AttrHistory, record)); err != nil {
+		return fmt.Errorf("failed to append attempt: %w", err)
+	}
+	
+	return nil
+}
+
+// RecordNotifications replaces the item's notification delivery status with the latest deliveries. Delivery
+// isn't a change to the job, so UpdatedAt and Version are left alone and concurrent job updates don't conflict.
+func (d *DynamoDBOperations) RecordNotifications(ctx context.Context, fileIdentifier string, deliveries []model.NotificationDelivery) error {
+	av, err := attributevalue.Marshal(deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification deliveries: %w", err)
+	}
+
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: fileIdentifier},
+		},
+		UpdateExpression:         aws.String("SET #notifications = :deliveries"),
+		ConditionExpression:      aws.String("attribute_exists(FileIdentifier)"),
+		ExpressionAttributeNames: map[string]string{"#notifications": AttrNotifications},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":deliveries": av,
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("failed to record notification deliveries: %w: %s", ErrItemNotFound, fileIdentifier)
+		}
+		return fmt.Errorf("failed to record notification deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// CancelTranscriptionItem moves a pending or active item to CANCELLED and records the action in its history.
+// It returns a *TransitionError if the item doesn't exist or has already finished.
+func (d *DynamoDBOperations) CancelTranscriptionItem(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	return d.UpdateTranscriptionItem(ctx, NewItemUpdate(fileIdentifier).
+		Status(model.StatusCancelled).
+		Append(AttrHistory, record))
+}
+
+// RequeueTranscriptionItem moves a finished item back to PENDING for reprocessing under a new batch, clearing the
+// previous error. The batches of earlier attempts have already counted the job, so they stop waiting on it.
+// It returns a *TransitionError if the item doesn't exist or hasn't finished.
+func (d *DynamoDBOperations) RequeueTranscriptionItem(ctx context.Context, fileIdentifier string, batch model.BatchRef, record model.AttemptRecord) error {
+	update := NewItemUpdate(fileIdentifier).
+		Status(model.StatusPending).
+		Set(AttrBatchID, batch.BatchID).
+		Remove(AttrErrorMessage).
+		Remove(AttrWaitingBatches).
+		Append(AttrHistory, record)
+	if batch.TenantID != "" {
+		update.Set(AttrBatchTenantID, batch.TenantID)
+	} else {
+		update.Remove(AttrBatchTenantID)
+	}
+	return d.UpdateTranscriptionItem(ctx, update)
+}
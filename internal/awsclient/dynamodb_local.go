@@ -0,0 +1,537 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// LocalStore keeps the job table in a JSON file, for runs without AWS. Items are stored in DynamoDB JSON
+// and changed with the same conditions as DynamoDBOperations, so jobs move through the same states and
+// fail with the same errors. Every write rewrites the file; the store is meant for one process at a time.
+type LocalStore struct {
+	path string
+
+	mu    sync.Mutex
+	items map[string]map[string]types.AttributeValue
+}
+
+// OpenLocalStore opens the store in a file, which is created on the first write if it doesn't exist
+func OpenLocalStore(path string) (*LocalStore, error) {
+	store := &LocalStore{path: path, items: map[string]map[string]types.AttributeValue{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local store: %w", err)
+	}
+
+	var stored map[string]map[string]localAttribute
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("invalid local store %s: %w", path, err)
+	}
+	for id, attrs := range stored {
+		item := make(map[string]types.AttributeValue, len(attrs))
+		for name, attr := range attrs {
+			if item[name], err = attr.decode(); err != nil {
+				return nil, fmt.Errorf("invalid local store %s: %s.%s: %w", path, id, name, err)
+			}
+		}
+		store.items[id] = item
+	}
+	return store, nil
+}
+
+// CreateTranscriptionItem stores a new job, refusing to overwrite an existing one
+func (l *LocalStore) CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	item.Version = 1
+
+	if err := model.ValidateTransition("", item.Status); err != nil {
+		return err
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if existing, ok := l.items[item.FileIdentifier]; ok {
+		return transitionFailure(item.FileIdentifier, item.Status, existing)
+	}
+	if err := l.write(map[string]map[string]types.AttributeValue{item.FileIdentifier: av}); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Created local item", logging.KeyFile, item.FileIdentifier, "status", item.Status)
+	return nil
+}
+
+// GetTranscriptionItem gets a job, or nil if it doesn't exist
+func (l *LocalStore) GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error) {
+	l.mu.Lock()
+	av, ok := l.items[fileIdentifier]
+	l.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	var item model.TranscriptionItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+	return &item, nil
+}
+
+// UpdateTranscriptionItem applies an update to an existing job, failing as DynamoDBOperations does
+func (l *LocalStore) UpdateTranscriptionItem(ctx context.Context, update *ItemUpdate) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	item, err := update.apply(l.items[update.fileIdentifier], time.Now())
+	if err != nil {
+		return err
+	}
+	if err := l.write(map[string]map[string]types.AttributeValue{update.fileIdentifier: item}); err != nil {
+		return err
+	}
+
+	if update.status != "" {
+		logging.FromContext(ctx).Info("Updated local item status", "status", update.status, logging.KeyFile, update.fileIdentifier)
+	}
+	return nil
+}
+
+// UpdateTranscriptionItemWithEvent applies an update and records its event in the outbox together
+func (l *LocalStore) UpdateTranscriptionItemWithEvent(ctx context.Context, update *ItemUpdate, event *model.JobEvent) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	outbox, err := outboxItem(ctx, update, event, now)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A retried event is applied once, as with the transaction's client request token
+	key := model.OutboxKey(event.ID)
+	if _, ok := l.items[key]; ok {
+		return nil
+	}
+
+	item, err := update.apply(l.items[update.fileIdentifier], now)
+	if err != nil {
+		return err
+	}
+	if err := l.write(map[string]map[string]types.AttributeValue{update.fileIdentifier: item, key: outbox}); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Updated local item status", "status", update.status, logging.KeyFile, update.fileIdentifier, "event", event.ID)
+	return nil
+}
+
+// UpdateTranscriptionLanguage records the detected language and the provider/model that produced the transcript
+func (l *LocalStore) UpdateTranscriptionLanguage(
+	ctx context.Context,
+	fileIdentifier string,
+	detectedLanguage string,
+	probability float64,
+	route model.LanguageRoute,
+) error {
+	update := NewItemUpdate(fileIdentifier)
+	if detectedLanguage != "" {
+		update.Set(AttrDetectedLanguage, detectedLanguage).Set(AttrLanguageProbability, probability)
+	}
+	if route.Provider != "" {
+		update.Set(AttrProvider, route.Provider)
+	}
+	if route.ModelID != "" {
+		update.Set(AttrModelID, route.ModelID)
+	}
+
+	if err := l.UpdateTranscriptionItem(ctx, update); err != nil {
+		return fmt.Errorf("failed to update language: %w", err)
+	}
+	return nil
+}
+
+// AppendAttempt adds an attempt record to the end of an item's history
+func (l *LocalStore) AppendAttempt(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	if err := l.UpdateTranscriptionItem(ctx, NewItemUpdate(fileIdentifier).Append(AttrHistory, record)); err != nil {
+		return fmt.Errorf("failed to append attempt: %w", err)
+	}
+	return nil
+}
+
+// CreateBatchItem records a new manifest batch; it returns ErrBatchExists if the batch ID was already used
+func (l *LocalStore) CreateBatchItem(ctx context.Context, batch *model.BatchItem) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	batch.FileIdentifier = model.BatchKey(batch.TenantID, batch.BatchID)
+	batch.CreatedAt = now
+	batch.UpdatedAt = now
+
+	av, err := attributevalue.MarshalMap(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.items[batch.FileIdentifier]; ok {
+		return ErrBatchExists
+	}
+	return l.write(map[string]map[string]types.AttributeValue{batch.FileIdentifier: av})
+}
+
+// GetBatchItem returns a manifest batch, or nil if it doesn't exist
+func (l *LocalStore) GetBatchItem(ctx context.Context, tenantID, batchID string) (*model.BatchItem, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	av, ok := l.items[model.BatchKey(tenantID, batchID)]
+	if !ok {
+		return nil, nil
+	}
+
+	batch := &model.BatchItem{}
+	if err := attributevalue.UnmarshalMap(av, batch); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch: %w", err)
+	}
+	return batch, nil
+}
+
+// RecordBatchProgress counts a job's outcome against a batch and returns the updated batch. Each job is
+// counted once. finalized is true for the call that moved the batch to its terminal status.
+func (l *LocalStore) RecordBatchProgress(ctx context.Context, tenantID, batchID, fileID string, succeeded bool) (*model.BatchItem, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := model.BatchKey(tenantID, batchID)
+	av, ok := l.items[key]
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+
+	batch := &model.BatchItem{}
+	if err := attributevalue.UnmarshalMap(av, batch); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal batch: %w", err)
+	}
+	for _, counted := range batch.CountedFiles {
+		if counted == fileID {
+			return batch, false, nil
+		}
+	}
+
+	batch.CountedFiles = append(batch.CountedFiles, fileID)
+	if succeeded {
+		batch.CompletedFiles++
+	} else {
+		batch.FailedFiles++
+	}
+	batch.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	finalized := batch.Done() && batch.BatchStatus == model.BatchStatusInProgress
+	if finalized {
+		batch.BatchStatus = model.BatchStatusCompleted
+		if batch.FailedFiles > 0 {
+			batch.BatchStatus = model.BatchStatusCompletedWithErrors
+		}
+	}
+
+	updated, err := attributevalue.MarshalMap(batch)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal batch: %w", err)
+	}
+	if err := l.write(map[string]map[string]types.AttributeValue{key: updated}); err != nil {
+		return nil, false, err
+	}
+
+	if finalized {
+		logging.FromContext(ctx).Info("Batch finished", "batch", batchID, "status", batch.BatchStatus)
+	}
+	return batch, finalized, nil
+}
+
+// RecordUsage stores a provider call's usage entry and adds it to the daily total. An entry that was
+// already recorded is left alone and not counted again.
+func (l *LocalStore) RecordUsage(ctx context.Context, entry *model.UsageEntry) error {
+	entry.FileIdentifier = model.UsageKey(entry.JobHash, entry.Attempt, entry.Call)
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.items[entry.FileIdentifier]; ok {
+		return nil
+	}
+
+	dailyKey := model.UsageDailyKey(entry)
+	daily := model.UsageDaily{}
+	if existing, ok := l.items[dailyKey]; ok {
+		if err := attributevalue.UnmarshalMap(existing, &daily); err != nil {
+			return fmt.Errorf("failed to unmarshal usage total: %w", err)
+		}
+	}
+	daily.FileIdentifier = dailyKey
+	daily.UsageDay = entry.Day
+	daily.TenantID = entry.TenantID
+	daily.Prefix = entry.Prefix
+	daily.Provider = entry.Provider
+	daily.ModelID = entry.ModelID
+	daily.Jobs += entry.Jobs()
+	daily.AudioSeconds += entry.AudioSeconds
+	daily.Characters += entry.Characters
+	daily.EstimatedCost += entry.EstimatedCost
+
+	total, err := attributevalue.MarshalMap(&daily)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage total: %w", err)
+	}
+	return l.write(map[string]map[string]types.AttributeValue{entry.FileIdentifier: item, dailyKey: total})
+}
+
+// write stores items and saves the file, leaving the store unchanged if the file can't be written.
+// The caller holds l.mu.
+func (l *LocalStore) write(items map[string]map[string]types.AttributeValue) error {
+	stored := make(map[string]map[string]localAttribute, len(l.items)+len(items))
+	encode := func(id string, item map[string]types.AttributeValue) error {
+		attrs := make(map[string]localAttribute, len(item))
+		for name, av := range item {
+			attr, err := encodeAttribute(av)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s.%s: %w", id, name, err)
+			}
+			attrs[name] = attr
+		}
+		stored[id] = attrs
+		return nil
+	}
+	for id, item := range l.items {
+		if _, replaced := items[id]; !replaced {
+			if err := encode(id, item); err != nil {
+				return err
+			}
+		}
+	}
+	for id, item := range items {
+		if err := encode(id, item); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode local store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create local store directory: %w", err)
+	}
+
+	// The file is replaced in one rename so an interrupted run never leaves it half written
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write local store: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write local store: %w", err)
+	}
+
+	for id, item := range items {
+		l.items[id] = item
+	}
+	return nil
+}
+
+// apply returns item with the update's changes, checking the conditions build puts on the DynamoDB update.
+// item is nil if it doesn't exist.
+func (u *ItemUpdate) apply(item map[string]types.AttributeValue, now time.Time) (map[string]types.AttributeValue, error) {
+	// Building the expression reports the same invalid updates as DynamoDBOperations does
+	if _, err := u.build(now); err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, u.failure(nil)
+	}
+
+	var current struct {
+		Status  model.TranscriptionStatus `dynamodbav:"Status"`
+		Version int64                     `dynamodbav:"Version"`
+	}
+	if err := attributevalue.UnmarshalMap(item, &current); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+	_, versioned := item["Version"]
+
+	if u.status != "" && !model.CanTransition(current.Status, u.status) {
+		return nil, u.failure(item)
+	}
+	if u.expectedVersion != nil && (current.Version != *u.expectedVersion || *u.expectedVersion == 0 && versioned) {
+		return nil, u.failure(item)
+	}
+
+	updated := make(map[string]types.AttributeValue, len(item)+len(u.actions)+2)
+	for name, av := range item {
+		updated[name] = av
+	}
+	if u.status != "" {
+		updated["Status"] = &types.AttributeValueMemberS{Value: string(u.status)}
+	}
+	updated["UpdatedAt"] = &types.AttributeValueMemberS{Value: now.UTC().Format(time.RFC3339)}
+	updated["Version"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(current.Version+1, 10)}
+
+	for _, action := range u.actions {
+		switch action.kind {
+		case updateSet:
+			av, err := attributevalue.Marshal(action.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", action.attr, err)
+			}
+			updated[action.attr] = av
+		case updateRemove:
+			delete(updated, action.attr)
+		case updateIncrement:
+			var n int64
+			if existing, ok := updated[action.attr].(*types.AttributeValueMemberN); ok {
+				var err error
+				if n, err = strconv.ParseInt(existing.Value, 10, 64); err != nil {
+					return nil, fmt.Errorf("can't increment %s: %w", action.attr, err)
+				}
+			}
+			updated[action.attr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(n+action.value.(int64), 10)}
+		case updateAppend:
+			av, err := attributevalue.Marshal(action.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", action.attr, err)
+			}
+			var list []types.AttributeValue
+			if existing, ok := updated[action.attr].(*types.AttributeValueMemberL); ok {
+				list = append(list, existing.Value...)
+			}
+			list = append(list, av.(*types.AttributeValueMemberL).Value...)
+			updated[action.attr] = &types.AttributeValueMemberL{Value: list}
+		}
+	}
+
+	return updated, nil
+}
+
+// localAttribute is an attribute value in DynamoDB JSON, e.g. {"S": "text"} or {"N": "42"}
+type localAttribute struct {
+	S    *string                    `json:"S,omitempty"`
+	N    *string                    `json:"N,omitempty"`
+	B    *[]byte                    `json:"B,omitempty"`
+	BOOL *bool                      `json:"BOOL,omitempty"`
+	NULL bool                       `json:"NULL,omitempty"`
+	L    *[]localAttribute          `json:"L,omitempty"`
+	M    *map[string]localAttribute `json:"M,omitempty"`
+	SS   []string                   `json:"SS,omitempty"`
+	NS   []string                   `json:"NS,omitempty"`
+	BS   [][]byte                   `json:"BS,omitempty"`
+}
+
+func encodeAttribute(av types.AttributeValue) (localAttribute, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return localAttribute{S: &v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return localAttribute{N: &v.Value}, nil
+	case *types.AttributeValueMemberB:
+		return localAttribute{B: &v.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return localAttribute{BOOL: &v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return localAttribute{NULL: true}, nil
+	case *types.AttributeValueMemberSS:
+		return localAttribute{SS: v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return localAttribute{NS: v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return localAttribute{BS: v.Value}, nil
+	case *types.AttributeValueMemberL:
+		list := make([]localAttribute, len(v.Value))
+		for i, item := range v.Value {
+			var err error
+			if list[i], err = encodeAttribute(item); err != nil {
+				return localAttribute{}, err
+			}
+		}
+		return localAttribute{L: &list}, nil
+	case *types.AttributeValueMemberM:
+		m := make(map[string]localAttribute, len(v.Value))
+		for name, item := range v.Value {
+			attr, err := encodeAttribute(item)
+			if err != nil {
+				return localAttribute{}, err
+			}
+			m[name] = attr
+		}
+		return localAttribute{M: &m}, nil
+	default:
+		return localAttribute{}, fmt.Errorf("unsupported attribute value %T", av)
+	}
+}
+
+func (a localAttribute) decode() (types.AttributeValue, error) {
+	switch {
+	case a.S != nil:
+		return &types.AttributeValueMemberS{Value: *a.S}, nil
+	case a.N != nil:
+		return &types.AttributeValueMemberN{Value: *a.N}, nil
+	case a.B != nil:
+		return &types.AttributeValueMemberB{Value: *a.B}, nil
+	case a.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *a.BOOL}, nil
+	case a.NULL:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case a.SS != nil:
+		return &types.AttributeValueMemberSS{Value: a.SS}, nil
+	case a.NS != nil:
+		return &types.AttributeValueMemberNS{Value: a.NS}, nil
+	case a.BS != nil:
+		return &types.AttributeValueMemberBS{Value: a.BS}, nil
+	case a.L != nil:
+		list := make([]types.AttributeValue, len(*a.L))
+		for i, item := range *a.L {
+			var err error
+			if list[i], err = item.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case a.M != nil:
+		m := make(map[string]types.AttributeValue, len(*a.M))
+		for name, item := range *a.M {
+			av, err := item.decode()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			m[name] = av
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	default:
+		return nil, errors.New("attribute has no value")
+	}
+}
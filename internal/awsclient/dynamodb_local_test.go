@@ -0,0 +1,101 @@
+package awsclient
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestLocalStore_Items(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "jobs.json")
+	store, err := OpenLocalStore(path)
+	assert.NoError(t, err)
+
+	item := &model.TranscriptionItem{FileIdentifier: "audio/call.mp3", Status: model.StatusClaimed}
+	assert.NoError(t, store.CreateTranscriptionItem(ctx, item))
+
+	// Jobs can't be created twice
+	err = store.CreateTranscriptionItem(ctx, &model.TranscriptionItem{FileIdentifier: "audio/call.mp3", Status: model.StatusClaimed})
+	assert.ErrorIs(t, err, ErrStatusConflict)
+
+	// Updates follow the job state machine and bump the version
+	assert.NoError(t, store.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").
+		Status(model.StatusTranscribing).
+		Set(AttrProvider, "elevenlabs").
+		Increment("RetryCount", 2).
+		ExpectVersion(1)))
+	assert.NoError(t, store.AppendAttempt(ctx, "audio/call.mp3", model.AttemptRecord{Status: model.StatusFailed, Error: "boom"}))
+	assert.NoError(t, store.AppendAttempt(ctx, "audio/call.mp3", model.AttemptRecord{Status: model.StatusClaimed}))
+
+	err = store.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").Status(model.StatusCompleted))
+	var transition *TransitionError
+	assert.ErrorAs(t, err, &transition)
+	assert.Equal(t, model.StatusTranscribing, transition.From)
+
+	err = store.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").Set(AttrProvider, "x").ExpectVersion(1))
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	err = store.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/missing.mp3").Set(AttrProvider, "x"))
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	// The file holds everything written, and a reopened store reads it back
+	store, err = OpenLocalStore(path)
+	assert.NoError(t, err)
+
+	got, err := store.GetTranscriptionItem(ctx, "audio/call.mp3")
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusTranscribing, got.Status)
+	assert.Equal(t, "elevenlabs", got.Provider)
+	assert.Equal(t, int64(4), got.Version)
+	assert.Len(t, got.History, 2)
+	assert.Equal(t, "boom", got.History[0].Error)
+	assert.Equal(t, item.CreatedAt, got.CreatedAt)
+
+	missing, err := store.GetTranscriptionItem(ctx, "audio/missing.mp3")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestLocalStore_Batches(t *testing.T) {
+	ctx := context.Background()
+	store, err := OpenLocalStore(filepath.Join(t.TempDir(), "jobs.json"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.CreateBatchItem(ctx, &model.BatchItem{BatchID: "b1", TotalFiles: 2, BatchStatus: model.BatchStatusInProgress}))
+	assert.ErrorIs(t, store.CreateBatchItem(ctx, &model.BatchItem{BatchID: "b1"}), ErrBatchExists)
+
+	batch, finalized, err := store.RecordBatchProgress(ctx, "", "b1", "a.mp3", true)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+	assert.Equal(t, 1, batch.CompletedFiles)
+
+	// A job is only counted once
+	batch, finalized, err = store.RecordBatchProgress(ctx, "", "b1", "a.mp3", false)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+	assert.Equal(t, 0, batch.FailedFiles)
+
+	batch, finalized, err = store.RecordBatchProgress(ctx, "", "b1", "b.mp3", false)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+	assert.Equal(t, model.BatchStatusCompletedWithErrors, batch.BatchStatus)
+
+	batch, err = store.GetBatchItem(ctx, "", "b1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.mp3", "b.mp3"}, batch.CountedFiles)
+
+	batch, err = store.GetBatchItem(ctx, "", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, batch)
+
+	// Tenants have their own batch IDs
+	assert.NoError(t, store.CreateBatchItem(ctx, &model.BatchItem{BatchID: "b1", TenantID: "support", TotalFiles: 1, BatchStatus: model.BatchStatusInProgress}))
+	batch, err = store.GetBatchItem(ctx, "support", "b1")
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant#support#batch#b1", batch.FileIdentifier)
+	assert.Equal(t, model.BatchStatusInProgress, batch.BatchStatus)
+}
@@ -0,0 +1,52 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSClient is the subset of the KMS API used by KMSOperations
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// KMSOperations issues and decrypts envelope encryption data keys; it implements envelope.KeyService
+type KMSOperations struct {
+	client KMSClient
+}
+
+// NewKMSOperations creates a new KMSOperations instance
+func NewKMSOperations(client KMSClient) *KMSOperations {
+	return &KMSOperations{client: client}
+}
+
+// GenerateDataKey returns a new AES-256 data key in plaintext and encrypted under keyID
+func (k *KMSOperations) GenerateDataKey(ctx context.Context, keyID string, encryptionContext map[string]string) ([]byte, []byte, error) {
+	result, err := k.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             aws.String(keyID),
+		KeySpec:           kmstypes.DataKeySpecAes256,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key with %s: %w", keyID, err)
+	}
+	return result.Plaintext, result.CiphertextBlob, nil
+}
+
+// Decrypt returns the plaintext of a data key encrypted under keyID
+func (k *KMSOperations) Decrypt(ctx context.Context, keyID string, encrypted []byte, encryptionContext map[string]string) ([]byte, error) {
+	result, err := k.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(keyID),
+		CiphertextBlob:    encrypted,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key with %s: %w", keyID, err)
+	}
+	return result.Plaintext, nil
+}
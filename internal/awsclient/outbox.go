@@ -0,0 +1,181 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+// OutboxRetention is how long delivered outbox records are kept before DynamoDB TTL removes them
+const OutboxRetention = 7 * 24 * time.Hour
+
+// UpdateTranscriptionItemWithEvent applies an update and records the resulting event in the outbox in a
+// single transaction, so the event is stored if and only if the update is. Errors are reported as by
+// UpdateTranscriptionItem.
+func (d *DynamoDBOperations) UpdateTranscriptionItemWithEvent(ctx context.Context, update *ItemUpdate, event *model.JobEvent) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	outbox, err := outboxItem(ctx, update, event, now)
+	if err != nil {
+		return err
+	}
+
+	expr, err := update.build(now)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		// Retried transactions with the same token are applied once
+		ClientRequestToken: aws.String(event.ID),
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					TableName: aws.String(d.tableName),
+					Key: map[string]types.AttributeValue{
+						"FileIdentifier": &types.AttributeValueMemberS{Value: update.fileIdentifier},
+					},
+					UpdateExpression:                    aws.String(expr.update),
+					ConditionExpression:                 aws.String(expr.condition),
+					ExpressionAttributeNames:            expr.names,
+					ExpressionAttributeValues:           expr.values,
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName:           aws.String(d.tableName),
+					Item:                outbox,
+					ConditionExpression: aws.String("attribute_not_exists(FileIdentifier)"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		// The item update is first in the transaction; a failed condition there is reported as for UpdateItem
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 &&
+			aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return update.failure(canceled.CancellationReasons[0].Item)
+		}
+		return fmt.Errorf("failed to update item with outbox event in DynamoDB: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Updated DynamoDB item status", "status", update.status, logging.KeyFile, update.fileIdentifier, "event", event.ID)
+	return nil
+}
+
+// outboxItem builds the outbox item carrying the event of an update
+func outboxItem(ctx context.Context, update *ItemUpdate, event *model.JobEvent, now time.Time) (map[string]types.AttributeValue, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job event: %w", err)
+	}
+
+	outbox, err := attributevalue.MarshalMap(&model.OutboxItem{
+		FileIdentifier: model.OutboxKey(event.ID),
+		EventID:        event.ID,
+		JobID:          update.fileIdentifier,
+		EventType:      event.Type,
+		Payload:        string(payload),
+		TraceContext:   tracing.Inject(ctx),
+		Deliveries:     map[string]model.NotificationDelivery{},
+		Claims:         map[string]int64{},
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(OutboxRetention).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox item: %w", err)
+	}
+	return outbox, nil
+}
+
+// ClaimOutboxDelivery claims delivery of an outbox event to one sink. It returns false without error when
+// the sink already has the event or another dispatcher holds an unexpired claim. The returned item
+// carries the previous delivery record for the sink, if any.
+func (d *DynamoDBOperations) ClaimOutboxDelivery(ctx context.Context, eventID, sink string, lease time.Duration) (*model.OutboxItem, bool, error) {
+	now := time.Now().UTC()
+
+	result, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.OutboxKey(eventID)},
+		},
+		UpdateExpression: aws.String("SET #claims.#sink = :now"),
+		ConditionExpression: aws.String("attribute_exists(FileIdentifier) AND " +
+			"(attribute_not_exists(#deliveries.#sink) OR #deliveries.#sink.#status <> :delivered) AND " +
+			"(attribute_not_exists(#claims.#sink) OR #claims.#sink < :expired)"),
+		ExpressionAttributeNames: map[string]string{
+			"#claims":     "Claims",
+			"#deliveries": "Deliveries",
+			"#sink":       sink,
+			"#status":     "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":       &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":expired":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(-lease).Unix(), 10)},
+			":delivered": &types.AttributeValueMemberS{Value: string(model.DeliveryDelivered)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to claim outbox delivery in DynamoDB: %w", err)
+	}
+
+	var item model.OutboxItem
+	if err := attributevalue.UnmarshalMap(result.Attributes, &item); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal outbox item: %w", err)
+	}
+
+	return &item, true, nil
+}
+
+// CompleteOutboxDelivery records the outcome of a claimed delivery and releases the claim.
+// It returns the outbox item with every sink's latest delivery.
+func (d *DynamoDBOperations) CompleteOutboxDelivery(ctx context.Context, eventID string, delivery model.NotificationDelivery) (*model.OutboxItem, error) {
+	av, err := attributevalue.Marshal(delivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification delivery: %w", err)
+	}
+
+	result, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.OutboxKey(eventID)},
+		},
+		UpdateExpression: aws.String("SET #deliveries.#sink = :delivery REMOVE #claims.#sink"),
+		ExpressionAttributeNames: map[string]string{
+			"#claims":     "Claims",
+			"#deliveries": "Deliveries",
+			"#sink":       delivery.Sink,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":delivery": av,
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record outbox delivery in DynamoDB: %w", err)
+	}
+
+	var item model.OutboxItem
+	if err := attributevalue.UnmarshalMap(result.Attributes, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outbox item: %w", err)
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,113 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestUpdateTranscriptionItemWithEvent(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	event := &model.JobEvent{
+		Version:        model.JobEventVersion,
+		ID:             "evt-1",
+		Type:           model.EventTypeCompleted,
+		FileIdentifier: "audio/call.mp3",
+		Status:         model.StatusCompleted,
+	}
+
+	client.On("TransactWriteItems", ctx, mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		if len(input.TransactItems) != 2 || aws.ToString(input.ClientRequestToken) != "evt-1" {
+			return false
+		}
+
+		update := input.TransactItems[0].Update
+		put := input.TransactItems[1].Put
+		key := update.Key["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		status := update.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS).Value
+		outboxKey := put.Item["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		jobID := put.Item["JobID"].(*types.AttributeValueMemberS).Value
+		_, hasDeliveries := put.Item["Deliveries"].(*types.AttributeValueMemberM)
+
+		return key == "audio/call.mp3" &&
+			status == "COMPLETED" &&
+			outboxKey == "outbox#evt-1" &&
+			jobID == "audio/call.mp3" &&
+			hasDeliveries &&
+			aws.ToString(put.ConditionExpression) == "attribute_not_exists(FileIdentifier)"
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+
+	err := ops.UpdateTranscriptionItemWithEvent(ctx, NewItemUpdate("audio/call.mp3").
+		Status(model.StatusCompleted).
+		Set(AttrTranscriptText, "hello").
+		Set(AttrOutputLocation, "s3://out/transcripts/call.txt").
+		Set(AttrProcessingTime, 1.5), event)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestClaimOutboxDelivery(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return input.ExpressionAttributeNames["#sink"] == "sns:topic"
+	})).Return(&dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"EventID": &types.AttributeValueMemberS{Value: "evt-1"},
+			"Deliveries": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"sns:topic": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"Sink":     &types.AttributeValueMemberS{Value: "sns:topic"},
+					"Status":   &types.AttributeValueMemberS{Value: "FAILED"},
+					"Attempts": &types.AttributeValueMemberN{Value: "1"},
+				}},
+			}},
+		},
+	}, nil).Once()
+
+	item, claimed, err := ops.ClaimOutboxDelivery(ctx, "evt-1", "sns:topic", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, 1, item.Deliveries["sns:topic"].Attempts)
+
+	// Already delivered or claimed elsewhere
+	client.On("UpdateItem", ctx, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+	item, claimed, err = ops.ClaimOutboxDelivery(ctx, "evt-1", "sns:topic", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Nil(t, item)
+}
+
+func TestRecordNotifications(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	// Only the deliveries are written; the job's UpdatedAt and Version stay as they were
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		_, ok := input.ExpressionAttributeValues[":deliveries"].(*types.AttributeValueMemberL)
+		return ok && *input.UpdateExpression == "SET #notifications = :deliveries" && len(input.ExpressionAttributeNames) == 1
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	err := ops.RecordNotifications(ctx, "audio/call.mp3", []model.NotificationDelivery{{Sink: "sns:topic", Status: model.DeliveryDelivered}})
+	assert.NoError(t, err)
+
+	client.On("UpdateItem", ctx, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+	err = ops.RecordNotifications(ctx, "audio/missing.mp3", nil)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	client.AssertExpectations(t)
+}
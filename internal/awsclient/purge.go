@@ -0,0 +1,72 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// PurgeTranscriptionItem deletes a transcription item and stores its purge audit record in a single
+// transaction. The item is only deleted if it is still at the given version, so a job that changed
+// after the caller inspected it is reported as ErrVersionConflict and left alone.
+func (d *DynamoDBOperations) PurgeTranscriptionItem(ctx context.Context, fileIdentifier string, version int64, record *model.PurgeRecord) error {
+	audit, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal purge record: %w", err)
+	}
+
+	condition := "attribute_exists(FileIdentifier) AND attribute_not_exists(#version)"
+	values := map[string]types.AttributeValue(nil)
+	if version != 0 {
+		condition = "attribute_exists(FileIdentifier) AND #version = :version"
+		values = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)},
+		}
+	}
+
+	_, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName: aws.String(d.tableName),
+					Key: map[string]types.AttributeValue{
+						"FileIdentifier": &types.AttributeValueMemberS{Value: fileIdentifier},
+					},
+					ConditionExpression:                 aws.String(condition),
+					ExpressionAttributeNames:            map[string]string{"#version": "Version"},
+					ExpressionAttributeValues:           values,
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName:           aws.String(d.tableName),
+					Item:                audit,
+					ConditionExpression: aws.String("attribute_not_exists(FileIdentifier)"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 &&
+			aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			if len(canceled.CancellationReasons[0].Item) == 0 {
+				return fmt.Errorf("%w: %s", ErrItemNotFound, fileIdentifier)
+			}
+			return fmt.Errorf("%w: %s changed since version %d", ErrVersionConflict, fileIdentifier, version)
+		}
+		return fmt.Errorf("failed to purge item from DynamoDB: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Purged DynamoDB item", "purgeRecord", record.FileIdentifier)
+	return nil
+}
@@ -0,0 +1,67 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestPurgeTranscriptionItem(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	record := &model.PurgeRecord{
+		FileIdentifier: model.PurgeKey("p-1"),
+		SubjectHash:    "abc123",
+		RequestedBy:    "ops@example.com",
+		PurgedAt:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	client.On("TransactWriteItems", ctx, mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		if len(input.TransactItems) != 2 {
+			return false
+		}
+
+		del := input.TransactItems[0].Delete
+		put := input.TransactItems[1].Put
+		key := del.Key["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		version := del.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN).Value
+		auditKey := put.Item["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		_, hasJobID := put.Item["JobID"]
+
+		return key == "audio/call.mp3" &&
+			version == "4" &&
+			auditKey == "purge#p-1" &&
+			!hasJobID
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+
+	assert.NoError(t, ops.PurgeTranscriptionItem(ctx, "audio/call.mp3", 4, record))
+
+	// A job that moved on since it was read is left in place
+	client.On("TransactWriteItems", ctx, mock.Anything).Return(nil, &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{
+				Code: aws.String("ConditionalCheckFailed"),
+				Item: map[string]types.AttributeValue{
+					"FileIdentifier": &types.AttributeValueMemberS{Value: "audio/call.mp3"},
+					"Version":        &types.AttributeValueMemberN{Value: "5"},
+				},
+			},
+			{Code: aws.String("None")},
+		},
+	}).Once()
+
+	err := ops.PurgeTranscriptionItem(ctx, "audio/call.mp3", 4, record)
+
+	assert.True(t, errors.Is(err, ErrVersionConflict))
+	client.AssertExpectations(t)
+}
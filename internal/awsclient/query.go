@@ -0,0 +1,211 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+const (
+	// StatusIndexName is the GSI keyed on Status (hash) and UpdatedAt (range)
+	StatusIndexName = "StatusUpdatedAtIndex"
+
+	// SourceBucketIndexName is the GSI keyed on SourceBucket (hash) and CreatedAt (range)
+	SourceBucketIndexName = "SourceBucketCreatedAtIndex"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// QueryTranscriptionItems returns one page of items matching the query.
+// A status is required for the status index; otherwise a source bucket is required.
+// A tenant's pages only start and end on the tenant's own jobs, so the cursor never holds another
+// tenant's job identifier and a cursor naming one is rejected.
+func (d *DynamoDBOperations) QueryTranscriptionItems(ctx context.Context, query model.TranscriptionQuery) (*model.TranscriptionPage, error) {
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(d.tableName),
+		ExpressionAttributeNames:  map[string]string{},
+		ExpressionAttributeValues: map[string]types.AttributeValue{},
+		ScanIndexForward:          aws.Bool(query.OldestFirst), // newest first by default
+	}
+
+	var hashName, hashValue, rangeName string
+	var filters []string
+	switch {
+	case query.Status != "":
+		input.IndexName = aws.String(StatusIndexName)
+		hashName, hashValue, rangeName = "Status", string(query.Status), "UpdatedAt"
+
+		// The bucket is not part of the status index, so narrow with a filter
+		if query.SourceBucket != "" {
+			filters = append(filters, "#sourceBucket = :sourceBucket")
+			input.ExpressionAttributeNames["#sourceBucket"] = "SourceBucket"
+			input.ExpressionAttributeValues[":sourceBucket"] = &types.AttributeValueMemberS{Value: query.SourceBucket}
+		}
+	case query.SourceBucket != "":
+		input.IndexName = aws.String(SourceBucketIndexName)
+		hashName, hashValue, rangeName = "SourceBucket", query.SourceBucket, "CreatedAt"
+	default:
+		return nil, errors.New("query requires a status or a source bucket")
+	}
+
+	// Tenants share the indexes, so other tenants' jobs are filtered out
+	if query.TenantID != "" {
+		filters = append(filters, "#tenant = :tenant")
+		input.ExpressionAttributeNames["#tenant"] = "TenantID"
+		input.ExpressionAttributeValues[":tenant"] = &types.AttributeValueMemberS{Value: query.TenantID}
+	}
+	if len(filters) > 0 {
+		input.FilterExpression = aws.String(strings.Join(filters, " AND "))
+	}
+
+	keyCondition := "#hash = :hash"
+	input.ExpressionAttributeNames["#hash"] = hashName
+	input.ExpressionAttributeValues[":hash"] = &types.AttributeValueMemberS{Value: hashValue}
+
+	if rangeCondition := timeRangeCondition(query.Since, query.Until, input.ExpressionAttributeValues); rangeCondition != "" {
+		keyCondition += " AND " + rangeCondition
+		input.ExpressionAttributeNames["#range"] = rangeName
+	}
+	input.KeyConditionExpression = aws.String(keyCondition)
+
+	if query.Limit > 0 {
+		input.Limit = aws.Int32(query.Limit)
+	}
+
+	if query.Cursor != "" {
+		startKey, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if query.TenantID != "" && !ownedBy(startKey, query.TenantID) {
+			return nil, ErrInvalidCursor
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
+	var result *dynamodb.QueryOutput
+	for {
+		var err error
+		result, err = d.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query DynamoDB: %w", err)
+		}
+		// A page that held only other tenants' jobs has no job of the tenant's to continue from
+		if query.TenantID == "" || len(result.Items) > 0 || len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	page := &model.TranscriptionPage{
+		Items: []model.TranscriptionItem{},
+	}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &page.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	if lastKey := result.LastEvaluatedKey; len(lastKey) > 0 {
+		if query.TenantID != "" && !ownedBy(lastKey, query.TenantID) {
+			// Continue after the tenant's last job instead; the other tenants' jobs after it are read again
+			lastKey = indexKey(result.Items[len(result.Items)-1], hashName, rangeName)
+		}
+
+		var err error
+		page.NextCursor, err = encodeCursor(lastKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// ownedBy reports whether a table or index key is one of the tenant's jobs
+func ownedBy(key map[string]types.AttributeValue, tenantID string) bool {
+	id, ok := key["FileIdentifier"].(*types.AttributeValueMemberS)
+	return ok && strings.HasPrefix(id.Value, model.TenantJobID(tenantID, ""))
+}
+
+// indexKey returns an item's key in an index: the index's hash and range attributes and the table key
+func indexKey(item map[string]types.AttributeValue, hashName, rangeName string) map[string]types.AttributeValue {
+	key := map[string]types.AttributeValue{}
+	for _, name := range []string{"FileIdentifier", hashName, rangeName} {
+		if value, ok := item[name]; ok {
+			key[name] = value
+		}
+	}
+	return key
+}
+
+// timeRangeCondition builds the range key condition for the optional time bounds
+func timeRangeCondition(since, until time.Time, values map[string]types.AttributeValue) string {
+	format := func(t time.Time) types.AttributeValue {
+		return &types.AttributeValueMemberS{Value: t.UTC().Format(time.RFC3339)}
+	}
+
+	switch {
+	case !since.IsZero() && !until.IsZero():
+		values[":since"] = format(since)
+		values[":until"] = format(until)
+		return "#range BETWEEN :since AND :until"
+	case !since.IsZero():
+		values[":since"] = format(since)
+		return "#range >= :since"
+	case !until.IsZero():
+		values[":until"] = format(until)
+		return "#range <= :until"
+	default:
+		return ""
+	}
+}
+
+// encodeCursor turns a LastEvaluatedKey into an opaque URL-safe token.
+// All table and index key attributes are strings.
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	plain := make(map[string]string, len(key))
+	for name, value := range key {
+		s, ok := value.(*types.AttributeValueMemberS)
+		if !ok {
+			return "", fmt.Errorf("unsupported key attribute type for %s", name)
+		}
+		plain[name] = s.Value
+	}
+
+	data, err := json.Marshal(plain)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reverses encodeCursor
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var plain map[string]string
+	if err := json.Unmarshal(data, &plain); err != nil || len(plain) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	key := make(map[string]types.AttributeValue, len(plain))
+	for name, value := range plain {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+
+	return key, nil
+}
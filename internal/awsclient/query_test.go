@@ -0,0 +1,217 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// MockDynamoDBClient is a mock implementation of the DynamoDB API
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.PutItemOutput), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.GetItemOutput), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.UpdateItemOutput), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.QueryOutput), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.TransactWriteItemsOutput), args.Error(1)
+}
+
+func TestQueryTranscriptionItems_ByStatus(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+
+	lastKey := map[string]types.AttributeValue{
+		"FileIdentifier": &types.AttributeValueMemberS{Value: "audio/b.aac"},
+		"Status":         &types.AttributeValueMemberS{Value: "FAILED"},
+		"UpdatedAt":      &types.AttributeValueMemberS{Value: "2024-05-01T12:00:00Z"},
+	}
+
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == StatusIndexName &&
+			*in.KeyConditionExpression == "#hash = :hash AND #range BETWEEN :since AND :until" &&
+			in.ExpressionAttributeNames["#range"] == "UpdatedAt" &&
+			in.ExpressionAttributeValues[":since"].(*types.AttributeValueMemberS).Value == "2024-05-01T00:00:00Z" &&
+			*in.FilterExpression == "#sourceBucket = :sourceBucket" &&
+			*in.Limit == 10 &&
+			in.ExclusiveStartKey == nil
+	})).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{
+			{
+				"FileIdentifier": &types.AttributeValueMemberS{Value: "audio/a.aac"},
+				"Status":         &types.AttributeValueMemberS{Value: "FAILED"},
+			},
+		},
+		LastEvaluatedKey: lastKey,
+	}, nil).Once()
+
+	page, err := ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{
+		Status:       model.StatusFailed,
+		SourceBucket: "input",
+		Since:        since,
+		Until:        until,
+		Limit:        10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "audio/a.aac", page.Items[0].FileIdentifier)
+	assert.NotEmpty(t, page.NextCursor)
+
+	// The cursor resumes from the last evaluated key
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return assert.ObjectsAreEqual(lastKey, in.ExclusiveStartKey)
+	})).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+	page, err = ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{
+		Status: model.StatusFailed,
+		Cursor: page.NextCursor,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+	client.AssertExpectations(t)
+}
+
+func TestQueryTranscriptionItems_BySourceBucket(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == SourceBucketIndexName &&
+			*in.KeyConditionExpression == "#hash = :hash AND #range >= :since" &&
+			in.ExpressionAttributeNames["#range"] == "CreatedAt" &&
+			in.FilterExpression == nil
+	})).Return(&dynamodb.QueryOutput{}, nil)
+
+	_, err := ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{
+		SourceBucket: "input",
+		Since:        time.Now().Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestQueryTranscriptionItems_Tenant(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == StatusIndexName &&
+			*in.FilterExpression == "#sourceBucket = :sourceBucket AND #tenant = :tenant" &&
+			in.ExpressionAttributeNames["#tenant"] == "TenantID" &&
+			in.ExpressionAttributeValues[":tenant"].(*types.AttributeValueMemberS).Value == "support"
+	})).Return(&dynamodb.QueryOutput{}, nil)
+
+	_, err := ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{
+		Status:       model.StatusFailed,
+		SourceBucket: "input",
+		TenantID:     "support",
+		Since:        time.Now().Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestQueryTranscriptionItems_TenantCursor(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	key := func(id, updatedAt string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: id},
+			"Status":         &types.AttributeValueMemberS{Value: "FAILED"},
+			"UpdatedAt":      &types.AttributeValueMemberS{Value: updatedAt},
+		}
+	}
+	own := key("tenant#support#support/a.mp3", "2024-05-01T12:00:00Z")
+	own["TenantID"] = &types.AttributeValueMemberS{Value: "support"}
+
+	// The first page only evaluated another tenant's jobs, so the query goes on without returning its key
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey == nil
+	})).Return(&dynamodb.QueryOutput{
+		LastEvaluatedKey: key("tenant#sales#sales/x.mp3", "2024-05-01T13:00:00Z"),
+	}, nil).Once()
+	client.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey != nil
+	})).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{own},
+		LastEvaluatedKey: key("tenant#sales#sales/y.mp3", "2024-05-01T11:00:00Z"),
+	}, nil).Once()
+
+	page, err := ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{Status: model.StatusFailed, TenantID: "support", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	client.AssertExpectations(t)
+
+	// The cursor continues after the tenant's own last job
+	cursor, err := decodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, key("tenant#support#support/a.mp3", "2024-05-01T12:00:00Z"), cursor)
+
+	// A cursor naming another tenant's job is rejected
+	foreign, err := encodeCursor(key("tenant#sales#sales/y.mp3", "2024-05-01T11:00:00Z"))
+	assert.NoError(t, err)
+	_, err = ops.QueryTranscriptionItems(ctx, model.TranscriptionQuery{Status: model.StatusFailed, TenantID: "support", Cursor: foreign})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestQueryTranscriptionItems_Invalid(t *testing.T) {
+	ops := NewDynamoDBOperations(new(MockDynamoDBClient), "test-table")
+
+	_, err := ops.QueryTranscriptionItems(context.Background(), model.TranscriptionQuery{})
+	assert.Error(t, err)
+
+	_, err = ops.QueryTranscriptionItems(context.Background(), model.TranscriptionQuery{
+		Status: model.StatusFailed,
+		Cursor: "not-a-cursor!",
+	})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
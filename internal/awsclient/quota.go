@@ -0,0 +1,264 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// quotaDayRetention is how long daily minute counters are kept before the table's TTL removes them
+const quotaDayRetention = 7 * 24 * time.Hour
+
+// AcquireJobSlot atomically gives a tenant's job one of the tenant's running slots until expires, unless
+// limit jobs already hold one; acquired is false then. A limit of zero or less only records the slot.
+// Slots are leases kept in the tenant's quota item, so one whose job never released it, e.g. because its
+// invocation timed out, is freed by ExpireJobSlots.
+func (d *DynamoDBOperations) AcquireJobSlot(ctx context.Context, tenantID, jobID string, limit int, expires time.Time) (acquired bool, err error) {
+	names := map[string]string{
+		"#leases": "Leases",
+		"#job":    jobID,
+		"#tenant": "TenantID",
+	}
+	expiresAt := &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.Unix(), 10)}
+	tenant := &types.AttributeValueMemberS{Value: tenantID}
+
+	// The first slot creates the lease map; the lease can't be set inside a map that doesn't exist yet
+	for attempt := 0; attempt < 2; attempt++ {
+		input := &dynamodb.UpdateItemInput{
+			TableName: aws.String(d.tableName),
+			Key: map[string]types.AttributeValue{
+				"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaKey(tenantID)},
+			},
+			UpdateExpression:         aws.String("SET #leases.#job = :expiresAt, #tenant = :tenant"),
+			ConditionExpression:      aws.String("attribute_exists(#leases)"),
+			ExpressionAttributeNames: names,
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":expiresAt": expiresAt,
+				":tenant":    tenant,
+			},
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}
+		if limit > 0 {
+			input.ConditionExpression = aws.String("attribute_exists(#leases) AND (size(#leases) < :limit OR attribute_exists(#leases.#job))")
+			input.ExpressionAttributeValues[":limit"] = &types.AttributeValueMemberN{Value: strconv.Itoa(limit)}
+		}
+
+		_, err := d.client.UpdateItem(ctx, input)
+		if err == nil {
+			return true, nil
+		}
+		var condErr *types.ConditionalCheckFailedException
+		if !errors.As(err, &condErr) {
+			return false, fmt.Errorf("failed to acquire job slot in DynamoDB: %w", err)
+		}
+		if _, ok := condErr.Item["Leases"]; ok {
+			return false, nil
+		}
+
+		_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(d.tableName),
+			Key: map[string]types.AttributeValue{
+				"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaKey(tenantID)},
+			},
+			UpdateExpression:    aws.String("SET #leases = :leases, #tenant = :tenant"),
+			ConditionExpression: aws.String("attribute_not_exists(#leases)"),
+			ExpressionAttributeNames: map[string]string{
+				"#leases": "Leases",
+				"#tenant": "TenantID",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":leases": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{jobID: expiresAt}},
+				":tenant": tenant,
+			},
+		})
+		if err == nil {
+			return true, nil
+		}
+		if !errors.As(err, &condErr) {
+			return false, fmt.Errorf("failed to acquire job slot in DynamoDB: %w", err)
+		}
+		// Another job created the map first; take a slot in it
+	}
+
+	return false, fmt.Errorf("failed to acquire job slot in DynamoDB: quota item for tenant %s keeps changing", tenantID)
+}
+
+// ReleaseJobSlot gives back a job's running slot. Releasing a slot the job doesn't hold, e.g. because
+// it had expired, does nothing.
+func (d *DynamoDBOperations) ReleaseJobSlot(ctx context.Context, tenantID, jobID string) error {
+	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaKey(tenantID)},
+		},
+		UpdateExpression:    aws.String("REMOVE #leases.#job"),
+		ConditionExpression: aws.String("attribute_exists(#leases.#job)"),
+		ExpressionAttributeNames: map[string]string{
+			"#leases": "Leases",
+			"#job":    jobID,
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to release job slot in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// ExpireJobSlots frees a tenant's slots whose lease ran out before now and returns how many it freed. A
+// slot renewed in the meantime is kept.
+func (d *DynamoDBOperations) ExpireJobSlots(ctx context.Context, tenantID string, now time.Time) (int, error) {
+	leases, err := d.getLeases(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for jobID, expires := range leases {
+		if expires > now.Unix() {
+			continue
+		}
+		_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(d.tableName),
+			Key: map[string]types.AttributeValue{
+				"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaKey(tenantID)},
+			},
+			UpdateExpression:    aws.String("REMOVE #leases.#job"),
+			ConditionExpression: aws.String("#leases.#job = :expiresAt"),
+			ExpressionAttributeNames: map[string]string{
+				"#leases": "Leases",
+				"#job":    jobID,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(expires, 10)},
+			},
+		})
+		if err != nil {
+			var condErr *types.ConditionalCheckFailedException
+			if errors.As(err, &condErr) {
+				continue
+			}
+			return expired, fmt.Errorf("failed to expire job slot in DynamoDB: %w", err)
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// AddTenantMinutes atomically adds transcribed audio minutes to a tenant's counter for a UTC day.
+// Counters expire a week after the day.
+func (d *DynamoDBOperations) AddTenantMinutes(ctx context.Context, tenantID, day string, minutes float64) error {
+	expires := time.Now().Add(quotaDayRetention).Unix()
+	if date, err := time.Parse(model.UsageDayFormat, day); err == nil {
+		expires = date.Add(quotaDayRetention).Unix()
+	}
+
+	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaDayKey(tenantID, day)},
+		},
+		UpdateExpression: aws.String("ADD #minutes :minutes SET #tenant = :tenant, #expiresAt = :expiresAt"),
+		ExpressionAttributeNames: map[string]string{
+			"#minutes":   "Minutes",
+			"#tenant":    "TenantID",
+			"#expiresAt": "ExpiresAt",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minutes":   &types.AttributeValueMemberN{Value: strconv.FormatFloat(minutes, 'f', -1, 64)},
+			":tenant":    &types.AttributeValueMemberS{Value: tenantID},
+			":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(expires, 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add tenant minutes in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// GetTenantUsage reads how many of a tenant's jobs hold a running slot and its audio minutes for a UTC day. Counters
+// that don't exist yet read as zero.
+func (d *DynamoDBOperations) GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error) {
+	usage := &model.QuotaUsage{TenantID: tenantID, Day: day}
+
+	leases, err := d.getLeases(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	usage.Running = len(leases)
+
+	if usage.Minutes, err = d.getCounter(ctx, model.QuotaDayKey(tenantID, day), "Minutes"); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// getCounter reads one numeric attribute with a strongly consistent read
+func (d *DynamoDBOperations) getCounter(ctx context.Context, fileIdentifier, attr string) (float64, error) {
+	result, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: fileIdentifier},
+		},
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#counter"),
+		ExpressionAttributeNames: map[string]string{
+			"#counter": attr,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read quota counter from DynamoDB: %w", err)
+	}
+
+	n, ok := result.Item[attr].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(n.Value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quota counter %s on %s: %w", attr, fileIdentifier, err)
+	}
+	return value, nil
+}
+
+// getLeases reads a tenant's running slots, job ID to lease expiry in Unix seconds, with a strongly
+// consistent read
+func (d *DynamoDBOperations) getLeases(ctx context.Context, tenantID string) (map[string]int64, error) {
+	result, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: model.QuotaKey(tenantID)},
+		},
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#leases"),
+		ExpressionAttributeNames: map[string]string{
+			"#leases": "Leases",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job slots from DynamoDB: %w", err)
+	}
+
+	var leases map[string]int64
+	if av, ok := result.Item["Leases"]; ok {
+		if err := attributevalue.Unmarshal(av, &leases); err != nil {
+			return nil, fmt.Errorf("invalid job slots of tenant %s: %w", tenantID, err)
+		}
+	}
+	return leases, nil
+}
@@ -0,0 +1,130 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAcquireJobSlot(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+	expires := time.Unix(1709300000, 0)
+
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return input.Key["FileIdentifier"].(*types.AttributeValueMemberS).Value == "quota#support" &&
+			aws.ToString(input.UpdateExpression) == "SET #leases.#job = :expiresAt, #tenant = :tenant" &&
+			input.ExpressionAttributeNames["#job"] == "tenant#support#a.mp3" &&
+			input.ExpressionAttributeValues[":expiresAt"].(*types.AttributeValueMemberN).Value == "1709300000" &&
+			input.ExpressionAttributeValues[":limit"].(*types.AttributeValueMemberN).Value == "2"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	acquired, err := ops.AcquireJobSlot(ctx, "support", "tenant#support#a.mp3", 2, expires)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// At the limit the condition fails and no slot is taken
+	client.On("UpdateItem", ctx, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{
+		Item: map[string]types.AttributeValue{"Leases": &types.AttributeValueMemberM{}},
+	}).Once()
+
+	acquired, err = ops.AcquireJobSlot(ctx, "support", "tenant#support#b.mp3", 2, expires)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	client.AssertExpectations(t)
+}
+
+func TestAcquireJobSlot_FirstSlot(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	// Without a lease map the slot can't be set in it, so the map is created holding the slot
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.ToString(input.ConditionExpression) == "attribute_exists(#leases)"
+	})).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		leases, ok := input.ExpressionAttributeValues[":leases"].(*types.AttributeValueMemberM)
+		return ok && aws.ToString(input.ConditionExpression) == "attribute_not_exists(#leases)" &&
+			leases.Value["tenant#sales#a.mp3"].(*types.AttributeValueMemberN).Value == "1709300000"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	acquired, err := ops.AcquireJobSlot(ctx, "sales", "tenant#sales#a.mp3", 0, time.Unix(1709300000, 0))
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	client.AssertExpectations(t)
+}
+
+func TestReleaseJobSlot_NotHeld(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.ToString(input.UpdateExpression) == "REMOVE #leases.#job" &&
+			aws.ToString(input.ConditionExpression) == "attribute_exists(#leases.#job)" &&
+			input.ExpressionAttributeNames["#job"] == "tenant#support#a.mp3"
+	})).Return(nil, &types.ConditionalCheckFailedException{})
+
+	assert.NoError(t, ops.ReleaseJobSlot(ctx, "support", "tenant#support#a.mp3"))
+	client.AssertExpectations(t)
+}
+
+func TestExpireJobSlots(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("GetItem", ctx, mock.Anything).Return(&dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{"Leases": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"tenant#support#lost.mp3":    &types.AttributeValueMemberN{Value: "1000"},
+			"tenant#support#running.mp3": &types.AttributeValueMemberN{Value: "3000"},
+		}}},
+	}, nil)
+
+	// Only the lease that ran out is removed, and only if it wasn't renewed in the meantime
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return input.ExpressionAttributeNames["#job"] == "tenant#support#lost.mp3" &&
+			aws.ToString(input.ConditionExpression) == "#leases.#job = :expiresAt" &&
+			input.ExpressionAttributeValues[":expiresAt"].(*types.AttributeValueMemberN).Value == "1000"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	expired, err := ops.ExpireJobSlots(ctx, "support", time.Unix(2000, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	client.AssertExpectations(t)
+}
+
+func TestGetTenantUsage(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	forKey := func(key string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+			return input.Key["FileIdentifier"].(*types.AttributeValueMemberS).Value == key && aws.ToBool(input.ConsistentRead)
+		})
+	}
+	client.On("GetItem", ctx, forKey("quota#support")).Return(&dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{"Leases": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"tenant#support#a.mp3": &types.AttributeValueMemberN{Value: "1000"},
+			"tenant#support#b.mp3": &types.AttributeValueMemberN{Value: "1000"},
+			"tenant#support#c.mp3": &types.AttributeValueMemberN{Value: "1000"},
+		}}},
+	}, nil)
+	client.On("GetItem", ctx, forKey("quota#support#2024-03-01")).Return(&dynamodb.GetItemOutput{}, nil)
+
+	usage, err := ops.GetTenantUsage(ctx, "support", "2024-03-01")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, usage.Running)
+	assert.Equal(t, 0.0, usage.Minutes)
+	client.AssertExpectations(t)
+}
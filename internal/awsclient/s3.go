@@ -4,13 +4,30 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3")
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/transcription-service/internal/logging"
+)
+
+// ParseS3URI splits an s3://bucket/key URI into its bucket and key
+func ParseS3URI(uri string) (bucket, key string, err error) {
+	if !strings.HasPrefix(uri, "s3://") {
+		return "", "", fmt.Errorf("not an s3:// URI: %q", uri)
+	}
+	
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("S3 URI must include a bucket and key: %q", uri)
+	}
+	
+	return bucket, key, nil
+}
 
 // S3Operations provides operations for working with S3
 type S3Operations struct {
@@ -26,7 +43,7 @@ func NewS3Operations(client *s3.Client) *S3Operations {
 
 // DownloadFile downloads a file from S3 to a local temp file
 func (s *S3Operations) DownloadFile(ctx context.Context, bucket, key string) (string, error) {
-	log.Printf("Downloading file from s3://%s/%s", bucket, key)
+	logging.FromContext(ctx).Debug("Downloading file from S3", "location", fmt.Sprintf("s3://%s/%s", bucket, key))
 	
 	// Create a temporary file
 	tmpDir := os.TempDir()
@@ -62,5 +79,123 @@ func (s *S3Operations) DownloadFile(ctx context.Context, bucket, key string) (st
 		return "", fmt.Errorf("failed to copy S3 object to temp file: %w", err)
 	}
 	
-	log.Printf("Downloaded %d bytes to %s", written, tempFilePath)
-	return tempFilePath, nil
\ No newline at end of file
+	logging.FromContext(ctx).Debug("Downloaded file from S3", "bytes", written, "path", tempFilePath)
+	return tempFilePath, nil
+}
+
+// ReadObject reads the full contents of an S3 object into memory
+func (s *S3Operations) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from S3: %w", err)
+	}
+	defer resp.Body.Close()
+	
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 object body: %w", err)
+	}
+	
+	return data, nil
+}
+
+// ObjectSize returns the size in bytes of an S3 object
+func (s *S3Operations) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	resp, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to head S3 object: %w", err)
+	}
+	
+	return resp.ContentLength, nil
+}
+
+// GeneratePresignedURL creates a pre-signed GET URL for an S3 object
+func (s *S3Operations) GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+	
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(time.Duration(expirationSeconds)*time.Second))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 object: %w", err)
+	}
+	
+	return req.URL, nil
+}
+
+// UploadText uploads a text document to S3
+func (s *S3Operations) UploadText(ctx context.Context, bucket, key, content string) error {
+	logging.FromContext(ctx).Debug("Uploading text to S3", "location", fmt.Sprintf("s3://%s/%s", bucket, key))
+	
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        strings.NewReader(content),
+		ContentType: aws.String("text/plain; charset=utf-8"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object in S3: %w", err)
+	}
+	
+	return nil
+}
+
+// UploadEncryptedText uploads a text document to S3 encrypted with the given KMS key
+func (s *S3Operations) UploadEncryptedText(ctx context.Context, bucket, key, content, kmsKeyID string) error {
+	logging.FromContext(ctx).Debug("Uploading encrypted text to S3", "location", fmt.Sprintf("s3://%s/%s", bucket, key))
+	
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		Body:                 strings.NewReader(content),
+		ContentType:          aws.String("text/plain; charset=utf-8"),
+		ServerSideEncryption: types.ServerSideEncryptionAwsKms,
+		SSEKMSKeyId:          aws.String(kmsKeyID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put encrypted object in S3: %w", err)
+	}
+	
+	return nil
+}
+
+// TagObject replaces the tag set of an S3 object
+func (s *S3Operations) TagObject(ctx context.Context, bucket, key string, tags map[string]string) error {
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	
+	_, err := s.client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag S3 object: %w", err)
+	}
+	
+	return nil
+}
+
+// DeleteObject deletes an S3 object. Deleting an object that doesn't exist is not an error.
+func (s *S3Operations) DeleteObject(ctx context.Context, bucket, key string) error {
+	logging.FromContext(ctx).Info("Deleting S3 object", "location", fmt.Sprintf("s3://%s/%s", bucket, key))
+	
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete S3 object: %w", err)
+	}
+	
+	return nil
+}
@@ -0,0 +1,161 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+)
+
+// LocalS3 stores objects as files, for runs without AWS. Object key k in bucket b is the file b/k under
+// the root directory, unless the bucket was mounted on a directory of its own.
+type LocalS3 struct {
+	root   string
+	mounts map[string]string
+}
+
+// NewLocalS3 creates local object storage under a root directory
+func NewLocalS3(root string) *LocalS3 {
+	return &LocalS3{root: root, mounts: map[string]string{}}
+}
+
+// Mount serves a bucket from a directory outside the root, e.g. a folder of recordings to process
+func (l *LocalS3) Mount(bucket, dir string) {
+	l.mounts[bucket] = dir
+}
+
+// Path returns the file holding an object. Keys that would leave the bucket's directory are rejected.
+func (l *LocalS3) Path(bucket, key string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || bucket == "." || bucket == ".." {
+		return "", fmt.Errorf("invalid local bucket %q", bucket)
+	}
+
+	dir, ok := l.mounts[bucket]
+	if !ok {
+		dir = filepath.Join(l.root, bucket)
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || rel == ".." {
+		return "", fmt.Errorf("invalid local object key %q", key)
+	}
+	return path, nil
+}
+
+// DownloadFile copies an object to a local temp file
+func (l *LocalS3) DownloadFile(ctx context.Context, bucket, key string) (string, error) {
+	path, err := l.Path(bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open local object: %w", err)
+	}
+	defer src.Close()
+
+	tempFile, err := os.CreateTemp("", "download-*-"+filepath.Base(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tempFile.Close()
+
+	if _, err := io.Copy(tempFile, src); err != nil {
+		os.Remove(tempFile.Name())
+		return "", fmt.Errorf("failed to copy local object to temp file: %w", err)
+	}
+	return tempFile.Name(), nil
+}
+
+// ReadObject reads the full contents of an object
+func (l *LocalS3) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	path, err := l.Path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local object: %w", err)
+	}
+	return data, nil
+}
+
+// ObjectSize returns the size in bytes of an object
+func (l *LocalS3) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	path, err := l.Path(bucket, key)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat local object: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// GeneratePresignedURL returns a file:// URL of the object; only a local transcription server can read it
+func (l *LocalS3) GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error) {
+	path, err := l.Path(bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve local object path: %w", err)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+// UploadText writes a text document, creating the directories above it
+func (l *LocalS3) UploadText(ctx context.Context, bucket, key, content string) error {
+	return l.writeObject(ctx, bucket, key, content, 0o644)
+}
+
+// UploadEncryptedText writes a text document. There is no KMS locally, so it is stored unencrypted, but
+// only the owner can read it.
+func (l *LocalS3) UploadEncryptedText(ctx context.Context, bucket, key, content, kmsKeyID string) error {
+	logging.FromContext(ctx).Warn("Local objects aren't encrypted", "location", fmt.Sprintf("s3://%s/%s", bucket, key))
+	return l.writeObject(ctx, bucket, key, content, 0o600)
+}
+
+// writeObject writes an object's file with the given permissions, also when it replaces an existing file
+func (l *LocalS3) writeObject(ctx context.Context, bucket, key, content string, perm os.FileMode) error {
+	path, err := l.Path(bucket, key)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Debug("Writing local object", "path", path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create local object directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to write local object: %w", err)
+	}
+	defer file.Close()
+
+	// The file's permissions are set before the content is written, in case it already existed
+	if err := file.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to write local object: %w", err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write local object: %w", err)
+	}
+	return file.Close()
+}
+
+// TagObject does nothing; local objects have no tags or lifecycle rules to act on them
+func (l *LocalS3) TagObject(ctx context.Context, bucket, key string, tags map[string]string) error {
+	logging.FromContext(ctx).Debug("Skipping tags of local object", "location", fmt.Sprintf("s3://%s/%s", bucket, key), "tags", len(tags))
+	return nil
+}
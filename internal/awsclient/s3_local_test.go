@@ -0,0 +1,59 @@
+package awsclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalS3(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	objects := NewLocalS3(root)
+
+	// Objects are files under the bucket's directory
+	assert.NoError(t, objects.UploadText(ctx, "output", "calls/a.txt", "hello"))
+	data, err := os.ReadFile(filepath.Join(root, "output", "calls", "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	data, err = objects.ReadObject(ctx, "output", "calls/a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	size, err := objects.ObjectSize(ctx, "output", "calls/a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), size)
+
+	// Documents meant to be encrypted can only be read by their owner
+	assert.NoError(t, objects.UploadText(ctx, "unredacted", "calls/a.txt", "hello"))
+	assert.NoError(t, objects.UploadEncryptedText(ctx, "unredacted", "calls/a.txt", "secret", "alias/unredacted"))
+	info, err := os.Stat(filepath.Join(root, "unredacted", "calls", "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A mounted bucket is served from its own directory
+	recordings := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(recordings, "call.mp3"), []byte("audio"), 0o644))
+	objects.Mount("recordings", recordings)
+
+	url, err := objects.GeneratePresignedURL(ctx, "recordings", "call.mp3", 3600)
+	assert.NoError(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(recordings, "call.mp3")), url)
+
+	path, err := objects.DownloadFile(ctx, "recordings", "call.mp3")
+	assert.NoError(t, err)
+	defer os.Remove(path)
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "audio", string(data))
+
+	// Keys can't leave the bucket
+	_, err = objects.ReadObject(ctx, "output", "../recordings/call.mp3")
+	assert.ErrorContains(t, err, "invalid local object key")
+	_, err = objects.ReadObject(ctx, "../output", "a.txt")
+	assert.ErrorContains(t, err, "invalid local bucket")
+}
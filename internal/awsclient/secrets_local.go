@@ -0,0 +1,62 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalSecrets reads secrets from the environment or from files, for tests and runs without AWS. The
+// secret "ElevenLabsApiKey" is read from SECRET_ELEVENLABSAPIKEY, or else from the file ElevenLabsApiKey
+// in the directory. Local secrets have no versions, so only AWSCURRENT exists.
+type LocalSecrets struct {
+	dir string
+}
+
+// NewLocalSecrets creates a local secret source; dir may be empty to use the environment only
+func NewLocalSecrets(dir string) *LocalSecrets {
+	return &LocalSecrets{dir: dir}
+}
+
+// SecretEnvVar returns the environment variable LocalSecrets reads a secret from
+func SecretEnvVar(name string) string {
+	return "SECRET_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// GetSecretStage reads a secret; stages other than AWSCURRENT don't exist
+func (l *LocalSecrets) GetSecretStage(ctx context.Context, name, stage string) (*Secret, error) {
+	if stage != StageCurrent {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrSecretNotFound, name, stage)
+	}
+
+	if value := os.Getenv(SecretEnvVar(name)); value != "" {
+		return &Secret{Name: name, Stage: stage, String: value}, nil
+	}
+
+	if l.dir != "" {
+		data, err := os.ReadFile(filepath.Join(l.dir, filepath.Base(name)))
+		if err == nil {
+			return &Secret{Name: name, Stage: stage, String: strings.TrimRight(string(data), "\r\n"), Binary: data}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s (set %s)", ErrSecretNotFound, name, SecretEnvVar(name))
+}
+
+// Invalidate does nothing; local secrets are read on every call
+func (l *LocalSecrets) Invalidate(name string) {}
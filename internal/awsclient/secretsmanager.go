@@ -3,40 +3,172 @@ package awsclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/yourusername/transcription-service/internal/logging"
 )
 
-// SecretsManagerOperations provides operations for working with AWS Secrets Manager
+// Secret version stages maintained by Secrets Manager rotation
+const (
+	StageCurrent  = "AWSCURRENT"
+	StagePending  = "AWSPENDING"
+	StagePrevious = "AWSPREVIOUS"
+)
+
+// DefaultSecretTTL is how long secrets are cached before they are read again
+const DefaultSecretTTL = 5 * time.Minute
+
+// ErrSecretNotFound is returned for secrets, or version stages of a secret, that don't exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Secret is one version of a secret
+type Secret struct {
+	Name      string
+	VersionID string
+	Stage     string
+
+	// String or Binary holds the value, depending on how the secret was stored
+	String string
+	Binary []byte
+}
+
+// Bytes returns the secret's value, whether it was stored as a string or as binary
+func (s *Secret) Bytes() []byte {
+	if s.Binary != nil {
+		return s.Binary
+	}
+	return []byte(s.String)
+}
+
+// SecretSource reads secrets. SecretsManagerOperations reads them from AWS; LocalSecrets reads them from
+// the environment or files for tests and local runs.
+type SecretSource interface {
+	// GetSecretStage reads the version of a secret with a version stage
+	GetSecretStage(ctx context.Context, name, stage string) (*Secret, error)
+
+	// Invalidate drops any cached versions of a secret, e.g. after its value was rejected
+	Invalidate(name string)
+}
+
+// SecretsManagerAPI is the subset of the Secrets Manager client used by SecretsManagerOperations
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SecretsManagerOperations provides operations for working with AWS Secrets Manager. Secrets are
+// cached by name and version stage, so warm invocations don't read them again until the TTL passes.
 type SecretsManagerOperations struct {
-	client *secretsmanager.Client
+	client SecretsManagerAPI
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[secretKey]cachedSecret
+}
+
+type secretKey struct {
+	name, stage string
+}
+
+type cachedSecret struct {
+	secret    *Secret
+	fetchedAt time.Time
 }
 
 // NewSecretsManagerOperations creates a new SecretsManagerOperations instance
-func NewSecretsManagerOperations(client *secretsmanager.Client) *SecretsManagerOperations {
+func NewSecretsManagerOperations(client SecretsManagerAPI) *SecretsManagerOperations {
 	return &SecretsManagerOperations{
 		client: client,
+		ttl:    DefaultSecretTTL,
+		now:    time.Now,
+		cache:  make(map[secretKey]cachedSecret),
 	}
 }
 
-// GetSecretString retrieves a plain string secret
-func (s *SecretsManagerOperations) GetSecretString(ctx context.Context, secretName string) (string, error) {
-	input := &secretsmanager.GetSecretValueInput{
-		SecretId: aws.String(secretName),
+// SetCacheTTL sets how long secrets are cached; zero reads them on every call
+func (s *SecretsManagerOperations) SetCacheTTL(ttl time.Duration) {
+	s.ttl = ttl
+}
+
+// GetSecretStage reads the version of a secret with a version stage. When a cached secret has expired
+// and can't be read again, e.g. because Secrets Manager is throttling, the expired value is returned.
+func (s *SecretsManagerOperations) GetSecretStage(ctx context.Context, name, stage string) (*Secret, error) {
+	key := secretKey{name: name, stage: stage}
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < s.ttl {
+		return cached.secret, nil
+	}
+
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)}
+	if stage != StageCurrent {
+		// AWSCURRENT is what Secrets Manager returns without a stage
+		input.VersionStage = aws.String(stage)
 	}
-	
+
 	result, err := s.client.GetSecretValue(ctx, input)
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrSecretNotFound, name, stage)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to get secret from Secrets Manager: %w", err)
+		if ok {
+			logging.FromContext(ctx).Warn("Using expired cached secret", "secret", name, "stage", stage, logging.KeyError, err)
+			return cached.secret, nil
+		}
+		return nil, fmt.Errorf("failed to get secret from Secrets Manager: %w", err)
+	}
+
+	secret := &Secret{
+		Name:      name,
+		VersionID: aws.ToString(result.VersionId),
+		Stage:     stage,
+		Binary:    result.SecretBinary,
 	}
-	
-	if result.SecretString == nil {
-		return "", fmt.Errorf("secret %s not found or has no string value", secretName)
+	if result.SecretString != nil {
+		secret.String = *result.SecretString
 	}
-	
-	return *result.SecretString, nil
+
+	s.mu.Lock()
+	s.cache[key] = cachedSecret{secret: secret, fetchedAt: now}
+	s.mu.Unlock()
+
+	return secret, nil
+}
+
+// Invalidate drops the cached versions of a secret
+func (s *SecretsManagerOperations) Invalidate(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.cache {
+		if key.name == name {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// GetSecretString retrieves a plain string secret
+func (s *SecretsManagerOperations) GetSecretString(ctx context.Context, secretName string) (string, error) {
+	return GetSecretString(ctx, s, secretName)
+}
+
+// GetSecretBinary retrieves a secret's value, whether it was stored as a string or as binary
+func (s *SecretsManagerOperations) GetSecretBinary(ctx context.Context, secretName string) ([]byte, error) {
+	secret, err := s.GetSecretStage(ctx, secretName, StageCurrent)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Bytes(), nil
 }
 
 // GetSecretJSON retrieves a JSON secret and unmarshals it into the provided target
@@ -45,11 +177,57 @@ func (s *SecretsManagerOperations) GetSecretJSON(ctx context.Context, secretName
 	if err != nil {
 		return err
 	}
-	
+
 	err = json.Unmarshal([]byte(secretString), target)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal secret JSON: %w", err)
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
+
+// GetSecretString reads the current version of a string secret from any source
+func GetSecretString(ctx context.Context, source SecretSource, name string) (string, error) {
+	secret, err := source.GetSecretStage(ctx, name, StageCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	if secret.String == "" {
+		return "", fmt.Errorf("secret %s not found or has no string value", name)
+	}
+
+	return secret.String, nil
+}
+
+// RotationCandidates returns the versions of a secret to try, in order, when its value may be
+// mid-rotation: AWSCURRENT, then AWSPENDING and AWSPREVIOUS when they exist and hold other versions.
+// A credential that was rejected is retried with the next candidate.
+func RotationCandidates(ctx context.Context, source SecretSource, name string) ([]*Secret, error) {
+	current, err := source.GetSecretStage(ctx, name, StageCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*Secret{current}
+	for _, stage := range []string{StagePending, StagePrevious} {
+		secret, err := source.GetSecretStage(ctx, name, stage)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		duplicate := false
+		for _, c := range candidates {
+			if c.VersionID != "" && c.VersionID == secret.VersionID {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			candidates = append(candidates, secret)
+		}
+	}
+	return candidates, nil
+}
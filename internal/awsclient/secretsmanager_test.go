@@ -0,0 +1,145 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSecretsManagerClient is a mock implementation of SecretsManagerAPI
+type MockSecretsManagerClient struct {
+	mock.Mock
+}
+
+func (m *MockSecretsManagerClient) GetSecretValue(ctx context.Context, input *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*secretsmanager.GetSecretValueOutput), args.Error(1)
+}
+
+// stageInput matches a request for one version stage; AWSCURRENT is requested without a stage
+func stageInput(name, stage string) interface{} {
+	return mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+		if aws.ToString(input.SecretId) != name {
+			return false
+		}
+		if stage == StageCurrent {
+			return input.VersionStage == nil
+		}
+		return aws.ToString(input.VersionStage) == stage
+	})
+}
+
+func TestGetSecretStage_Cache(t *testing.T) {
+	client := new(MockSecretsManagerClient)
+	ops := NewSecretsManagerOperations(client)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ops.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	client.On("GetSecretValue", ctx, stageInput("api-key", StageCurrent)).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("v1"),
+		VersionId:    aws.String("version-1"),
+	}, nil).Once()
+
+	value, err := ops.GetSecretString(ctx, "api-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// Within the TTL the cached value is used
+	now = now.Add(time.Minute)
+	value, err = ops.GetSecretString(ctx, "api-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+	client.AssertNumberOfCalls(t, "GetSecretValue", 1)
+
+	// An expired value is still served when the secret can't be read again
+	now = now.Add(DefaultSecretTTL)
+	client.On("GetSecretValue", ctx, stageInput("api-key", StageCurrent)).Return(nil, errors.New("throttled")).Once()
+	value, err = ops.GetSecretString(ctx, "api-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// Invalidating forces a new read
+	ops.Invalidate("api-key")
+	client.On("GetSecretValue", ctx, stageInput("api-key", StageCurrent)).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("v2"),
+		VersionId:    aws.String("version-2"),
+	}, nil).Once()
+	value, err = ops.GetSecretString(ctx, "api-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", value)
+
+	// Binary secrets have no string value
+	client.On("GetSecretValue", ctx, stageInput("signing-key", StageCurrent)).Return(&secretsmanager.GetSecretValueOutput{
+		SecretBinary: []byte{0x01, 0x02},
+	}, nil)
+	binary, err := ops.GetSecretBinary(ctx, "signing-key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, binary)
+	_, err = ops.GetSecretString(ctx, "signing-key")
+	assert.EqualError(t, err, "secret signing-key not found or has no string value")
+
+	client.AssertExpectations(t)
+}
+
+func TestRotationCandidates(t *testing.T) {
+	client := new(MockSecretsManagerClient)
+	ops := NewSecretsManagerOperations(client)
+	ctx := context.Background()
+
+	client.On("GetSecretValue", ctx, stageInput("api-key", StageCurrent)).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("new"), VersionId: aws.String("version-2"),
+	}, nil)
+	client.On("GetSecretValue", ctx, stageInput("api-key", StagePending)).Return(nil, &types.ResourceNotFoundException{})
+	client.On("GetSecretValue", ctx, stageInput("api-key", StagePrevious)).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("old"), VersionId: aws.String("version-1"),
+	}, nil)
+
+	candidates, err := RotationCandidates(ctx, ops, "api-key")
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, "new", candidates[0].String)
+	assert.Equal(t, StagePrevious, candidates[1].Stage)
+	assert.Equal(t, "old", candidates[1].String)
+
+	// A secret that doesn't exist at all is an error
+	client.On("GetSecretValue", ctx, stageInput("missing", StageCurrent)).Return(nil, &types.ResourceNotFoundException{})
+	_, err = RotationCandidates(ctx, ops, "missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestLocalSecrets(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "webhook-key"), []byte("from-file\n"), 0o600))
+	t.Setenv("SECRET_ELEVENLABSAPIKEY", "from-env")
+	secrets := NewLocalSecrets(dir)
+	ctx := context.Background()
+
+	value, err := GetSecretString(ctx, secrets, "ElevenLabsApiKey")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", value)
+
+	value, err = GetSecretString(ctx, secrets, "webhook-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", value)
+
+	// Local secrets have no other versions
+	candidates, err := RotationCandidates(ctx, secrets, "webhook-key")
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 1)
+
+	_, err = GetSecretString(ctx, secrets, "transcription/missing")
+	assert.EqualError(t, err, "secret not found: transcription/missing (set SECRET_TRANSCRIPTION_MISSING)")
+}
@@ -0,0 +1,38 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SSMOperations provides operations for working with SSM Parameter Store
+type SSMOperations struct {
+	client *ssm.Client
+}
+
+// NewSSMOperations creates a new SSMOperations instance
+func NewSSMOperations(client *ssm.Client) *SSMOperations {
+	return &SSMOperations{
+		client: client,
+	}
+}
+
+// GetParameter returns a parameter's value, decrypting SecureString parameters
+func (s *SSMOperations) GetParameter(ctx context.Context, name string) (string, error) {
+	result, err := s.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get parameter %s: %w", name, err)
+	}
+
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return "", fmt.Errorf("parameter %s has no value", name)
+	}
+
+	return *result.Parameter.Value, nil
+}
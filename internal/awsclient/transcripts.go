@@ -0,0 +1,93 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// TranscriptReader reads transcripts stored in S3
+type TranscriptReader interface {
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// SetTranscriptReader lets the operations load transcripts that were too large to keep on the item
+func (d *DynamoDBOperations) SetTranscriptReader(reader TranscriptReader) {
+	d.transcripts = reader
+}
+
+// SetEncryption lets the operations decrypt envelope-encrypted transcripts, both on the item and in S3.
+// Without it, GetTranscriptionItem leaves EncryptedTranscript in place and TranscriptText empty.
+func (d *DynamoDBOperations) SetEncryption(encrypter *envelope.Encrypter) {
+	d.encrypter = encrypter
+}
+
+// decryptTranscript replaces an item's encrypted transcript with its plaintext
+func (d *DynamoDBOperations) decryptTranscript(ctx context.Context, item *model.TranscriptionItem) error {
+	if item.EncryptedTranscript == nil || d.encrypter == nil {
+		return nil
+	}
+
+	text, err := d.encrypter.Open(ctx, item.EncryptedTranscript, envelope.JobContext(item.FileIdentifier))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt transcript for %s: %w", item.FileIdentifier, err)
+	}
+
+	item.TranscriptText = string(text)
+	item.EncryptedTranscript = nil
+	return nil
+}
+
+// GetTranscriptionItemWithTranscript gets a transcription item with its full TranscriptText, loading it from
+// S3 when only a TranscriptRef is stored. GetTranscriptionItem returns just the reference and its preview.
+func (d *DynamoDBOperations) GetTranscriptionItemWithTranscript(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error) {
+	item, err := d.GetTranscriptionItem(ctx, fileIdentifier)
+	if err != nil || item == nil {
+		return item, err
+	}
+
+	if err := d.HydrateTranscript(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// HydrateTranscript loads an externally stored transcript into item.TranscriptText, checking it against the
+// recorded hash. Items with an inline transcript are left unchanged.
+func (d *DynamoDBOperations) HydrateTranscript(ctx context.Context, item *model.TranscriptionItem) error {
+	if item.TranscriptRef == nil || item.TranscriptText != "" {
+		return nil
+	}
+	if d.transcripts == nil {
+		return errors.New("transcript reader is not configured")
+	}
+
+	bucket, key, err := ParseS3URI(item.TranscriptRef.Location)
+	if err != nil {
+		return err
+	}
+
+	data, err := d.transcripts.ReadObject(ctx, bucket, key)
+	if err != nil {
+		return fmt.Errorf("failed to load transcript for %s: %w", item.FileIdentifier, err)
+	}
+
+	if d.encrypter != nil {
+		if data, err = d.encrypter.OpenObject(ctx, data, envelope.JobContext(item.FileIdentifier)); err != nil {
+			return fmt.Errorf("failed to decrypt transcript for %s: %w", item.FileIdentifier, err)
+		}
+	} else if envelope.IsSealedObject(data) {
+		return fmt.Errorf("transcript for %s is encrypted and no key service is configured", item.FileIdentifier)
+	}
+
+	text := string(data)
+	if err := item.TranscriptRef.Verify(text); err != nil {
+		return err
+	}
+
+	item.TranscriptText = text
+	return nil
+}
@@ -0,0 +1,136 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// MockTranscriptReader is a mock implementation of TranscriptReader
+type MockTranscriptReader struct {
+	mock.Mock
+}
+
+func (m *MockTranscriptReader) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	args := m.Called(ctx, bucket, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestHydrateTranscript(t *testing.T) {
+	reader := new(MockTranscriptReader)
+	ops := NewDynamoDBOperations(new(MockDynamoDBClient), "test-table")
+	ctx := context.Background()
+
+	item := &model.TranscriptionItem{
+		FileIdentifier: "audio/long.mp3",
+		TranscriptRef:  model.NewTranscriptRef("s3://artifacts/transcripts/long.txt", "the full text"),
+	}
+
+	// Loading needs a reader
+	assert.EqualError(t, ops.HydrateTranscript(ctx, item), "transcript reader is not configured")
+
+	ops.SetTranscriptReader(reader)
+	reader.On("ReadObject", ctx, "artifacts", "transcripts/long.txt").Return([]byte("the full text"), nil).Once()
+
+	assert.NoError(t, ops.HydrateTranscript(ctx, item))
+	assert.Equal(t, "the full text", item.TranscriptText)
+
+	// A changed object is refused
+	item.TranscriptText = ""
+	reader.On("ReadObject", ctx, "artifacts", "transcripts/long.txt").Return([]byte("edited text!!"), nil).Once()
+
+	err := ops.HydrateTranscript(ctx, item)
+	assert.EqualError(t, err, "transcript at s3://artifacts/transcripts/long.txt does not match its recorded hash")
+	assert.Empty(t, item.TranscriptText)
+}
+
+func TestGetTranscriptionItemDecryptsTranscript(t *testing.T) {
+	keys, err := envelope.NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := envelope.NewEncrypter(keys, "alias/transcripts")
+	ctx := context.Background()
+
+	sealed, err := enc.Seal(ctx, []byte("private words"), map[string]string{"FileIdentifier": "audio/call.mp3"})
+	assert.NoError(t, err)
+	av, err := attributevalue.MarshalMap(model.TranscriptionItem{
+		FileIdentifier:      "audio/call.mp3",
+		Status:              model.StatusCompleted,
+		EncryptedTranscript: sealed,
+	})
+	assert.NoError(t, err)
+
+	client := new(MockDynamoDBClient)
+	client.On("GetItem", ctx, mock.Anything).Return(&dynamodb.GetItemOutput{Item: av}, nil)
+	ops := NewDynamoDBOperations(client, "test-table")
+
+	// Without a key service the envelope is returned as stored
+	item, err := ops.GetTranscriptionItem(ctx, "audio/call.mp3")
+	assert.NoError(t, err)
+	assert.Empty(t, item.TranscriptText)
+	assert.NotNil(t, item.EncryptedTranscript)
+
+	ops.SetEncryption(enc)
+	item, err = ops.GetTranscriptionItem(ctx, "audio/call.mp3")
+	assert.NoError(t, err)
+	assert.Equal(t, "private words", item.TranscriptText)
+	assert.Nil(t, item.EncryptedTranscript)
+}
+
+func TestHydrateEncryptedTranscript(t *testing.T) {
+	keys, err := envelope.NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := envelope.NewEncrypter(keys, "alias/transcripts")
+	ctx := context.Background()
+
+	data, err := enc.SealObject(ctx, []byte("the full text"), map[string]string{"FileIdentifier": "audio/long.mp3"})
+	assert.NoError(t, err)
+
+	reader := new(MockTranscriptReader)
+	reader.On("ReadObject", ctx, "artifacts", "transcripts/long.txt").Return(data, nil)
+	ops := NewDynamoDBOperations(new(MockDynamoDBClient), "test-table")
+	ops.SetTranscriptReader(reader)
+
+	item := &model.TranscriptionItem{
+		FileIdentifier: "audio/long.mp3",
+		TranscriptRef:  model.NewTranscriptRef("s3://artifacts/transcripts/long.txt", "the full text"),
+	}
+	assert.EqualError(t, ops.HydrateTranscript(ctx, item), "transcript for audio/long.mp3 is encrypted and no key service is configured")
+
+	ops.SetEncryption(enc)
+	assert.NoError(t, ops.HydrateTranscript(ctx, item))
+	assert.Equal(t, "the full text", item.TranscriptText)
+}
+
+func TestGetTranscriptionItemRefusesMovedEnvelope(t *testing.T) {
+	keys, err := envelope.NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := envelope.NewEncrypter(keys, "alias/transcripts")
+	ctx := context.Background()
+
+	// Another job's envelope copied onto this item doesn't decrypt
+	sealed, err := enc.Seal(ctx, []byte("someone else's words"), envelope.JobContext("audio/other.mp3"))
+	assert.NoError(t, err)
+	av, err := attributevalue.MarshalMap(model.TranscriptionItem{
+		FileIdentifier:      "audio/call.mp3",
+		Status:              model.StatusCompleted,
+		EncryptedTranscript: sealed,
+	})
+	assert.NoError(t, err)
+
+	client := new(MockDynamoDBClient)
+	client.On("GetItem", ctx, mock.Anything).Return(&dynamodb.GetItemOutput{Item: av}, nil)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ops.SetEncryption(enc)
+
+	_, err = ops.GetTranscriptionItem(ctx, "audio/call.mp3")
+	assert.ErrorIs(t, err, envelope.ErrContextMismatch)
+}
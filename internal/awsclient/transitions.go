@@ -0,0 +1,74 @@
+package awsclient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// TransitionError reports a status change rejected by the job state machine. It matches both
+// model.ErrInvalidTransition and ErrStatusConflict with errors.Is.
+type TransitionError struct {
+	// FileIdentifier is the job whose status change was rejected
+	FileIdentifier string
+
+	// From is the job's status when the change was attempted; empty if the job doesn't exist
+	From model.TranscriptionStatus
+
+	// To is the requested status
+	To model.TranscriptionStatus
+
+	// Exists is false when the job doesn't exist
+	Exists bool
+}
+
+// Error describes the rejected transition
+func (e *TransitionError) Error() string {
+	if !e.Exists {
+		return fmt.Sprintf("%v for %s: job does not exist, cannot move to %s", model.ErrInvalidTransition, e.FileIdentifier, e.To)
+	}
+	return fmt.Sprintf("%v for %s: %s -> %s", model.ErrInvalidTransition, e.FileIdentifier, e.From, e.To)
+}
+
+// Is lets callers match the error against model.ErrInvalidTransition or ErrStatusConflict
+func (e *TransitionError) Is(target error) bool {
+	return target == model.ErrInvalidTransition || target == ErrStatusConflict
+}
+
+// transitionCondition returns a condition expression that only holds when the item's current status
+// may move to the given status, adding its placeholders to the expression maps. #status must name Status.
+func transitionCondition(to model.TranscriptionStatus, names map[string]string, values map[string]types.AttributeValue) (string, error) {
+	from := model.AllowedFrom(to)
+	if len(from) == 0 {
+		return "", fmt.Errorf("%w: no status may move to %q", model.ErrInvalidTransition, to)
+	}
+
+	names["#status"] = "Status"
+	placeholders := make([]string, len(from))
+	for i, status := range from {
+		placeholders[i] = fmt.Sprintf(":from%d", i)
+		values[placeholders[i]] = &types.AttributeValueMemberS{Value: string(status)}
+	}
+
+	return fmt.Sprintf("attribute_exists(FileIdentifier) AND #status IN (%s)", strings.Join(placeholders, ", ")), nil
+}
+
+// transitionFailure builds the error for a failed transition condition from the item returned with the failure
+func transitionFailure(fileIdentifier string, to model.TranscriptionStatus, item map[string]types.AttributeValue) error {
+	err := &TransitionError{FileIdentifier: fileIdentifier, To: to}
+	if len(item) == 0 {
+		return err
+	}
+
+	var current struct {
+		Status model.TranscriptionStatus `dynamodbav:"Status"`
+	}
+	_ = attributevalue.UnmarshalMap(item, &current)
+
+	err.Exists = true
+	err.From = current.Status
+	return err
+}
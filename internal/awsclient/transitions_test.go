@@ -0,0 +1,102 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestUpdateTranscriptionItem_Transition(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		condition := aws.ToString(input.ConditionExpression)
+		from, _ := input.ExpressionAttributeValues[":from0"].(*types.AttributeValueMemberS)
+		return strings.HasPrefix(condition, "attribute_exists(FileIdentifier) AND #status IN (") &&
+			input.ExpressionAttributeNames["#status"] == "Status" &&
+			from != nil && from.Value == string(model.StatusTranscribing) &&
+			input.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	err := ops.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").Status(model.StatusPostprocessing))
+	assert.NoError(t, err)
+
+	// The job was cancelled in the meantime
+	client.On("UpdateItem", ctx, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{
+		Item: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: "audio/call.mp3"},
+			"Status":         &types.AttributeValueMemberS{Value: "CANCELLED"},
+		},
+	}).Once()
+
+	err = ops.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").Status(model.StatusPostprocessing))
+
+	var transitionErr *TransitionError
+	assert.True(t, errors.As(err, &transitionErr))
+	assert.True(t, transitionErr.Exists)
+	assert.Equal(t, model.StatusCancelled, transitionErr.From)
+	assert.ErrorIs(t, err, model.ErrInvalidTransition)
+	assert.ErrorIs(t, err, ErrStatusConflict)
+	assert.Equal(t, "invalid status transition for audio/call.mp3: CANCELLED -> POSTPROCESSING", err.Error())
+	client.AssertExpectations(t)
+}
+
+func TestCreateTranscriptionItem_Transition(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	// New jobs can't start in a processing status
+	err := ops.CreateTranscriptionItem(ctx, &model.TranscriptionItem{
+		FileIdentifier: "audio/call.mp3",
+		Status:         model.StatusCompleted,
+	})
+	assert.ErrorIs(t, err, model.ErrInvalidTransition)
+	client.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything)
+
+	// Another processor created the job first
+	client.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return aws.ToString(input.ConditionExpression) == "attribute_not_exists(FileIdentifier)"
+	})).Return(nil, &types.ConditionalCheckFailedException{
+		Item: map[string]types.AttributeValue{
+			"Status": &types.AttributeValueMemberS{Value: "CLAIMED"},
+		},
+	})
+
+	err = ops.CreateTranscriptionItem(ctx, &model.TranscriptionItem{
+		FileIdentifier: "audio/call.mp3",
+		Status:         model.StatusClaimed,
+	})
+	assert.ErrorIs(t, err, model.ErrInvalidTransition)
+	assert.Contains(t, err.Error(), "CLAIMED -> CLAIMED")
+}
+
+func TestTransitionTable(t *testing.T) {
+	assert.True(t, model.CanTransition("", model.StatusClaimed))
+	assert.True(t, model.CanTransition(model.StatusFailed, model.StatusClaimed))
+	assert.False(t, model.CanTransition(model.StatusCompleted, model.StatusClaimed))
+	assert.False(t, model.CanTransition(model.StatusClaimed, model.StatusClaimed))
+	assert.False(t, model.CanTransition(model.StatusCancelled, model.StatusPostprocessing))
+	assert.False(t, model.CanTransition(model.StatusTranscribing, model.StatusCompleted))
+	assert.True(t, model.CanTransition(model.StatusInProgress, model.StatusCompleted))
+
+	// Nothing moves into the legacy status
+	assert.Empty(t, model.AllowedFrom(model.StatusInProgress))
+	assert.Equal(t, []model.TranscriptionStatus{model.StatusPending, model.StatusFailed}, model.AllowedFrom(model.StatusClaimed))
+
+	for _, status := range model.Statuses() {
+		assert.True(t, status.Valid(), status)
+		assert.False(t, status.Terminal() && status.Active(), status)
+	}
+	assert.False(t, model.TranscriptionStatus("DONE").Valid())
+}
@@ -0,0 +1,325 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Transcription item attributes that can be changed with ItemUpdate
+const (
+	AttrTranscriptText      = "TranscriptText"
+	AttrTranscriptRef       = "TranscriptRef"
+	AttrOutputLocation      = "OutputLocation"
+	AttrErrorMessage        = "ErrorMessage"
+	AttrProcessingTime      = "ProcessingTime"
+	AttrDetectedLanguage    = "DetectedLanguage"
+	AttrLanguageProbability = "LanguageProbability"
+	AttrProvider            = "Provider"
+	AttrModelID             = "ModelID"
+	AttrHistory             = "History"
+	AttrNotifications       = "Notifications"
+	AttrExpiresAt           = "ExpiresAt"
+	AttrRedactions          = "Redactions"
+	AttrUnredactedLocation  = "UnredactedLocation"
+	AttrVocabulary          = "Vocabulary"
+	AttrVocabularyVersion   = "VocabularyVersion"
+	AttrEncryptedTranscript = "EncryptedTranscript"
+	AttrDeferredUntil       = "DeferredUntil"
+	AttrDeferredReason      = "DeferredReason"
+	AttrDeferredOptions     = "DeferredOptions"
+	AttrConfigVersion       = "ConfigVersion"
+	AttrBatchID             = "BatchID"
+	AttrBatchTenantID       = "BatchTenantID"
+	AttrWaitingBatches      = "WaitingBatches"
+)
+
+// ErrVersionConflict is returned when an update's expected version doesn't match the item's current version
+var ErrVersionConflict = errors.New("item version has changed")
+
+// ErrItemNotFound is returned when an update targets an item that doesn't exist
+var ErrItemNotFound = errors.New("item does not exist")
+
+// managedAttributes are maintained by ItemUpdate itself and can't be changed directly
+var managedAttributes = map[string]bool{
+	"FileIdentifier": true,
+	"Status":         true,
+	"CreatedAt":      true,
+	"UpdatedAt":      true,
+	"Version":        true,
+}
+
+type updateKind int
+
+const (
+	updateSet updateKind = iota
+	updateRemove
+	updateIncrement
+	updateAppend
+)
+
+type updateAction struct {
+	kind  updateKind
+	attr  string
+	value interface{}
+}
+
+// ItemUpdate is a change to one existing transcription item, built with NewItemUpdate and applied with
+// DynamoDBOperations.UpdateTranscriptionItem. Only the attributes named in the update are touched.
+// Every update also refreshes UpdatedAt and increments Version by one, so a caller that read version v
+// can use ExpectVersion(v) to apply a change only if nobody else has written the item since.
+type ItemUpdate struct {
+	fileIdentifier  string
+	status          model.TranscriptionStatus
+	actions         []updateAction
+	touched         map[string]bool
+	expectedVersion *int64
+	err             error
+}
+
+// NewItemUpdate starts an update of the given item
+func NewItemUpdate(fileIdentifier string) *ItemUpdate {
+	return &ItemUpdate{
+		fileIdentifier: fileIdentifier,
+		touched:        map[string]bool{},
+	}
+}
+
+// Status moves the item to a new status. The update only applies if the job state machine allows the
+// move from the item's current status.
+func (u *ItemUpdate) Status(status model.TranscriptionStatus) *ItemUpdate {
+	u.status = status
+	return u
+}
+
+// Set sets an attribute to a value, which is marshalled with the attributevalue package
+func (u *ItemUpdate) Set(attr string, value interface{}) *ItemUpdate {
+	return u.add(updateSet, attr, value)
+}
+
+// Remove deletes attributes from the item
+func (u *ItemUpdate) Remove(attrs ...string) *ItemUpdate {
+	for _, attr := range attrs {
+		u.add(updateRemove, attr, nil)
+	}
+	return u
+}
+
+// Increment adds delta to a numeric attribute, treating a missing attribute as zero
+func (u *ItemUpdate) Increment(attr string, delta int64) *ItemUpdate {
+	return u.add(updateIncrement, attr, delta)
+}
+
+// Append adds values to the end of a list attribute, creating the list if it doesn't exist
+func (u *ItemUpdate) Append(attr string, values ...interface{}) *ItemUpdate {
+	return u.add(updateAppend, attr, values)
+}
+
+// ExpectVersion applies the update only if the item's Version is still the given version.
+// Items written before versioning have version 0.
+func (u *ItemUpdate) ExpectVersion(version int64) *ItemUpdate {
+	u.expectedVersion = &version
+	return u
+}
+
+// FileIdentifier returns the item the update applies to
+func (u *ItemUpdate) FileIdentifier() string {
+	return u.fileIdentifier
+}
+
+// NewStatus returns the status the update moves the item to, or "" if it leaves the status alone
+func (u *ItemUpdate) NewStatus() model.TranscriptionStatus {
+	return u.status
+}
+
+// Value returns the value an attribute is set to, the delta it is incremented by or the values appended to it
+func (u *ItemUpdate) Value(attr string) (interface{}, bool) {
+	for _, action := range u.actions {
+		if action.attr == attr && action.kind != updateRemove {
+			return action.value, true
+		}
+	}
+	return nil, false
+}
+
+// Removes reports whether the update deletes an attribute
+func (u *ItemUpdate) Removes(attr string) bool {
+	for _, action := range u.actions {
+		if action.attr == attr && action.kind == updateRemove {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *ItemUpdate) add(kind updateKind, attr string, value interface{}) *ItemUpdate {
+	switch {
+	case u.err != nil:
+	case managedAttributes[attr]:
+		u.err = fmt.Errorf("attribute %s can't be changed directly", attr)
+	case u.touched[attr]:
+		u.err = fmt.Errorf("attribute %s is changed more than once in the same update", attr)
+	default:
+		u.touched[attr] = true
+		u.actions = append(u.actions, updateAction{kind: kind, attr: attr, value: value})
+	}
+	return u
+}
+
+// itemExpression is a built update, ready for UpdateItem or a transactional Update
+type itemExpression struct {
+	update    string
+	condition string
+	names     map[string]string
+	values    map[string]types.AttributeValue
+}
+
+// build renders the update and condition expressions
+func (u *ItemUpdate) build(now time.Time) (*itemExpression, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+
+	expr := &itemExpression{
+		names: map[string]string{
+			"#updatedAt": "UpdatedAt",
+			"#version":   "Version",
+		},
+		values: map[string]types.AttributeValue{
+			":updatedAt": &types.AttributeValueMemberS{Value: now.UTC().Format(time.RFC3339)},
+			":zero":      &types.AttributeValueMemberN{Value: "0"},
+			":one":       &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	var sets, removes []string
+	if u.status != "" {
+		sets = append(sets, "#status = :status")
+		expr.values[":status"] = &types.AttributeValueMemberS{Value: string(u.status)}
+	}
+	sets = append(sets, "#updatedAt = :updatedAt", "#version = if_not_exists(#version, :zero) + :one")
+
+	for i, action := range u.actions {
+		name := fmt.Sprintf("#a%d", i)
+		value := fmt.Sprintf(":v%d", i)
+		expr.names[name] = action.attr
+
+		switch action.kind {
+		case updateSet:
+			av, err := attributevalue.Marshal(action.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", action.attr, err)
+			}
+			expr.values[value] = av
+			sets = append(sets, fmt.Sprintf("%s = %s", name, value))
+		case updateRemove:
+			removes = append(removes, name)
+		case updateIncrement:
+			expr.values[value] = &types.AttributeValueMemberN{Value: strconv.FormatInt(action.value.(int64), 10)}
+			sets = append(sets, fmt.Sprintf("%s = if_not_exists(%s, :zero) + %s", name, name, value))
+		case updateAppend:
+			av, err := attributevalue.Marshal(action.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", action.attr, err)
+			}
+			expr.values[value] = av
+			expr.values[":empty"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{}}
+			sets = append(sets, fmt.Sprintf("%s = list_append(if_not_exists(%s, :empty), %s)", name, name, value))
+		}
+	}
+
+	expr.update = "SET " + strings.Join(sets, ", ")
+	if len(removes) > 0 {
+		expr.update += " REMOVE " + strings.Join(removes, ", ")
+	}
+
+	// Updates never create items; a status change also has to be allowed by the state machine
+	expr.condition = "attribute_exists(FileIdentifier)"
+	if u.status != "" {
+		condition, err := transitionCondition(u.status, expr.names, expr.values)
+		if err != nil {
+			return nil, err
+		}
+		expr.condition = condition
+	}
+
+	if u.expectedVersion != nil {
+		if *u.expectedVersion == 0 {
+			expr.condition += " AND attribute_not_exists(#version)"
+		} else {
+			expr.condition += " AND #version = :expectedVersion"
+			expr.values[":expectedVersion"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(*u.expectedVersion, 10)}
+		}
+	}
+
+	return expr, nil
+}
+
+// failure explains a failed condition check from the item returned with the failure
+func (u *ItemUpdate) failure(item map[string]types.AttributeValue) error {
+	if len(item) == 0 {
+		if u.status != "" {
+			return transitionFailure(u.fileIdentifier, u.status, nil)
+		}
+		return fmt.Errorf("%w: %s", ErrItemNotFound, u.fileIdentifier)
+	}
+
+	var current struct {
+		Status  model.TranscriptionStatus `dynamodbav:"Status"`
+		Version int64                     `dynamodbav:"Version"`
+	}
+	_ = attributevalue.UnmarshalMap(item, &current)
+
+	if u.status != "" && !model.CanTransition(current.Status, u.status) {
+		return transitionFailure(u.fileIdentifier, u.status, item)
+	}
+	if u.expectedVersion != nil && current.Version != *u.expectedVersion {
+		return fmt.Errorf("%w: %s is at version %d, expected %d", ErrVersionConflict, u.fileIdentifier, current.Version, *u.expectedVersion)
+	}
+
+	return fmt.Errorf("%w: update condition failed for %s", ErrStatusConflict, u.fileIdentifier)
+}
+
+// UpdateTranscriptionItem applies an update to an existing transcription item. A rejected status change is
+// reported as *TransitionError, a stale expected version as ErrVersionConflict and a missing item as
+// ErrItemNotFound (or *TransitionError when the update changes the status).
+func (d *DynamoDBOperations) UpdateTranscriptionItem(ctx context.Context, update *ItemUpdate) error {
+	expr, err := update.build(time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"FileIdentifier": &types.AttributeValueMemberS{Value: update.fileIdentifier},
+		},
+		UpdateExpression:                    aws.String(expr.update),
+		ConditionExpression:                 aws.String(expr.condition),
+		ExpressionAttributeNames:            expr.names,
+		ExpressionAttributeValues:           expr.values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return update.failure(condErr.Item)
+		}
+		return fmt.Errorf("failed to update item in DynamoDB: %w", err)
+	}
+
+	if update.status != "" {
+		logging.FromContext(ctx).Info("Updated DynamoDB item status", "status", update.status, logging.KeyFile, update.fileIdentifier)
+	}
+	return nil
+}
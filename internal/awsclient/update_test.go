@@ -0,0 +1,88 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestItemUpdate_Build(t *testing.T) {
+	record := model.AttemptRecord{Status: model.StatusFailed, Error: "boom"}
+
+	expr, err := NewItemUpdate("audio/call.mp3").
+		Set(AttrProvider, "elevenlabs").
+		Remove(AttrErrorMessage, AttrOutputLocation).
+		Increment("RetryCount", 2).
+		Append(AttrHistory, record).
+		ExpectVersion(3).
+		build(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SET #updatedAt = :updatedAt, #version = if_not_exists(#version, :zero) + :one, "+
+		"#a0 = :v0, #a3 = if_not_exists(#a3, :zero) + :v3, #a4 = list_append(if_not_exists(#a4, :empty), :v4) "+
+		"REMOVE #a1, #a2", expr.update)
+	assert.Equal(t, "attribute_exists(FileIdentifier) AND #version = :expectedVersion", expr.condition)
+	assert.Equal(t, "Provider", expr.names["#a0"])
+	assert.Equal(t, "ErrorMessage", expr.names["#a1"])
+	assert.Equal(t, "RetryCount", expr.names["#a3"])
+	assert.Equal(t, "History", expr.names["#a4"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "elevenlabs"}, expr.values[":v0"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "2"}, expr.values[":v3"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "3"}, expr.values[":expectedVersion"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-05-01T12:00:00Z"}, expr.values[":updatedAt"])
+	assert.Len(t, expr.values[":v4"].(*types.AttributeValueMemberL).Value, 1)
+
+	// Unversioned items have version 0
+	expr, err = NewItemUpdate("audio/call.mp3").Set(AttrProvider, "x").ExpectVersion(0).build(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "attribute_exists(FileIdentifier) AND attribute_not_exists(#version)", expr.condition)
+}
+
+func TestItemUpdate_Invalid(t *testing.T) {
+	_, err := NewItemUpdate("audio/call.mp3").Set("Status", "COMPLETED").build(time.Now())
+	assert.EqualError(t, err, "attribute Status can't be changed directly")
+
+	_, err = NewItemUpdate("audio/call.mp3").Set(AttrErrorMessage, "x").Remove(AttrErrorMessage).build(time.Now())
+	assert.EqualError(t, err, "attribute ErrorMessage is changed more than once in the same update")
+
+	_, err = NewItemUpdate("audio/call.mp3").Status(model.StatusInProgress).build(time.Now())
+	assert.ErrorIs(t, err, model.ErrInvalidTransition)
+}
+
+func TestUpdateTranscriptionItem_Conflicts(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	client.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.ToString(input.ConditionExpression) != "" &&
+			input.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld
+	})).Return(nil, &types.ConditionalCheckFailedException{
+		Item: map[string]types.AttributeValue{
+			"Status":  &types.AttributeValueMemberS{Value: "FAILED"},
+			"Version": &types.AttributeValueMemberN{Value: "5"},
+		},
+	}).Once()
+
+	// Someone else wrote the item since it was read
+	err := ops.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/call.mp3").
+		Status(model.StatusClaimed).
+		ExpectVersion(4))
+	assert.True(t, errors.Is(err, ErrVersionConflict))
+	assert.EqualError(t, err, "item version has changed: audio/call.mp3 is at version 5, expected 4")
+
+	// Updates never create items
+	client.On("UpdateItem", ctx, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+	err = ops.UpdateTranscriptionItem(ctx, NewItemUpdate("audio/missing.mp3").Set(AttrProvider, "x"))
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	client.AssertExpectations(t)
+}
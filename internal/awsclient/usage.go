@@ -0,0 +1,119 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// UsageDayIndexName is the GSI keyed on UsageDay (hash). Only daily usage totals have the attribute.
+const UsageDayIndexName = "UsageDayIndex"
+
+// RecordUsage stores a provider call's usage entry and adds it to the daily total in a single transaction.
+// An entry that was already recorded, e.g. by a redelivered event, is left alone and not counted again.
+func (d *DynamoDBOperations) RecordUsage(ctx context.Context, entry *model.UsageEntry) error {
+	entry.FileIdentifier = model.UsageKey(entry.JobHash, entry.Attempt, entry.Call)
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage entry: %w", err)
+	}
+
+	number := func(v float64) types.AttributeValue {
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}
+	}
+	str := func(s string) types.AttributeValue {
+		return &types.AttributeValueMemberS{Value: s}
+	}
+
+	_, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(d.tableName),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(FileIdentifier)"),
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName: aws.String(d.tableName),
+					Key: map[string]types.AttributeValue{
+						"FileIdentifier": str(model.UsageDailyKey(entry)),
+					},
+					UpdateExpression: aws.String("SET #day = :day, #tenant = :tenant, #prefix = :prefix, #provider = :provider, #model = :model " +
+						"ADD #jobs :jobs, #seconds :seconds, #characters :characters, #cost :cost"),
+					ExpressionAttributeNames: map[string]string{
+						"#day":        "UsageDay",
+						"#tenant":     "TenantID",
+						"#prefix":     "Prefix",
+						"#provider":   "Provider",
+						"#model":      "ModelID",
+						"#jobs":       "Jobs",
+						"#seconds":    "AudioSeconds",
+						"#characters": "Characters",
+						"#cost":       "EstimatedCost",
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":day":        str(entry.Day),
+						":tenant":     str(entry.TenantID),
+						":prefix":     str(entry.Prefix),
+						":provider":   str(entry.Provider),
+						":model":      str(entry.ModelID),
+						":jobs":       number(float64(entry.Jobs())),
+						":seconds":    number(entry.AudioSeconds),
+						":characters": number(float64(entry.Characters)),
+						":cost":       number(entry.EstimatedCost),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 &&
+			aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return nil
+		}
+		return fmt.Errorf("failed to record usage in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDailyUsage returns the daily usage totals for every UTC day from since to until inclusive
+func (d *DynamoDBOperations) QueryDailyUsage(ctx context.Context, since, until time.Time) ([]model.UsageDaily, error) {
+	days := []model.UsageDaily{}
+	for day := since.UTC().Truncate(24 * time.Hour); !day.After(until.UTC()); day = day.AddDate(0, 0, 1) {
+		paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+			TableName:              aws.String(d.tableName),
+			IndexName:              aws.String(UsageDayIndexName),
+			KeyConditionExpression: aws.String("UsageDay = :day"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":day": &types.AttributeValueMemberS{Value: day.Format(model.UsageDayFormat)},
+			},
+		})
+
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query daily usage: %w", err)
+			}
+
+			var totals []model.UsageDaily
+			if err := attributevalue.UnmarshalListOfMaps(page.Items, &totals); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal daily usage: %w", err)
+			}
+			days = append(days, totals...)
+		}
+	}
+
+	return days, nil
+}
@@ -0,0 +1,104 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestRecordUsage(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	entry := &model.UsageEntry{
+		JobHash:       model.JobHash("calls/a.mp3"),
+		Attempt:       2,
+		Call:          1,
+		TenantID:      "support",
+		Prefix:        "calls/",
+		Provider:      "elevenlabs",
+		ModelID:       "scribe_v1",
+		AudioSeconds:  90,
+		Characters:    1200,
+		EstimatedCost: 0.01,
+		Day:           "2024-03-01",
+	}
+
+	client.On("TransactWriteItems", ctx, mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		if len(input.TransactItems) != 2 {
+			return false
+		}
+
+		put := input.TransactItems[0].Put
+		update := input.TransactItems[1].Update
+		entryKey := put.Item["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		dailyKey := update.Key["FileIdentifier"].(*types.AttributeValueMemberS).Value
+		seconds := update.ExpressionAttributeValues[":seconds"].(*types.AttributeValueMemberN).Value
+		jobs := update.ExpressionAttributeValues[":jobs"].(*types.AttributeValueMemberN).Value
+
+		// The ledger outlives purged jobs, so it only names them by hash
+		_, hasJobID := put.Item["JobID"]
+		return entryKey == "usage#"+model.JobHash("calls/a.mp3")+"#2#1" &&
+			!hasJobID &&
+			aws.ToString(put.ConditionExpression) == "attribute_not_exists(FileIdentifier)" &&
+			dailyKey == "usagedaily#2024-03-01#support#calls/#elevenlabs#scribe_v1" &&
+			seconds == "90" &&
+			jobs == "1"
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+
+	assert.NoError(t, ops.RecordUsage(ctx, entry))
+
+	// A redelivered job's entry already exists, so the daily total is left alone
+	client.On("TransactWriteItems", ctx, mock.Anything).Return(nil, &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")}},
+	}).Once()
+
+	assert.NoError(t, ops.RecordUsage(ctx, entry))
+	client.AssertExpectations(t)
+}
+
+func TestQueryDailyUsage(t *testing.T) {
+	client := new(MockDynamoDBClient)
+	ops := NewDynamoDBOperations(client, "test-table")
+	ctx := context.Background()
+
+	forDay := func(day string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return aws.ToString(input.IndexName) == UsageDayIndexName &&
+				input.ExpressionAttributeValues[":day"].(*types.AttributeValueMemberS).Value == day
+		})
+	}
+	total := func(day, prefix string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"UsageDay": &types.AttributeValueMemberS{Value: day},
+			"Prefix":   &types.AttributeValueMemberS{Value: prefix},
+			"Jobs":     &types.AttributeValueMemberN{Value: "3"},
+		}
+	}
+
+	client.On("Query", ctx, forDay("2024-03-01")).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{total("2024-03-01", "calls/"), total("2024-03-01", "/")},
+	}, nil)
+	client.On("Query", ctx, forDay("2024-03-02")).Return(&dynamodb.QueryOutput{}, nil)
+	client.On("Query", ctx, forDay("2024-03-03")).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{total("2024-03-03", "calls/")},
+	}, nil)
+
+	since := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)
+	totals, err := ops.QueryDailyUsage(ctx, since, until)
+
+	assert.NoError(t, err)
+	assert.Len(t, totals, 3)
+	assert.Equal(t, "2024-03-03", totals[2].UsageDay)
+	assert.Equal(t, 3, totals[2].Jobs)
+	client.AssertNumberOfCalls(t, "Query", 3)
+}
@@ -1,60 +1,536 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"os"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/settings"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"github.com/yourusername/transcription-service/internal/usage"
 )
 
 // Config holds the application configuration
 type Config struct {
 	// AWS region for all service clients
 	AWSRegion string
-	
+
 	// DynamoDB table name for state tracking
 	DynamoDBTableName string
-	
+
 	// Secrets Manager secret name containing the ElevenLabs API key
 	ElevenLabsSecretName string
-	
+
+	// Where secrets are read (secretsmanager or local), the local secret files and how long
+	// Secrets Manager secrets are cached
+	SecretSource   string
+	SecretsDir     string
+	SecretCacheTTL time.Duration
+
 	// Optional output S3 bucket (if storing full transcripts separately)
 	OutputS3Bucket string
-	
-	// ElevenLabs API base URL
+
+	// Optional bucket for transcripts too large to keep inline when there's no output bucket
+	// (defaults to the audio file's bucket)
+	ArtifactsS3Bucket string
+
+	// Largest transcript, in bytes, kept inline on the DynamoDB item
+	InlineTranscriptLimit int
+
+	// Retention periods by source key prefix; jobs matching none are kept indefinitely
+	RetentionPolicies []model.RetentionPolicy
+
+	// Custom vocabulary files by source key prefix
+	Vocabularies []model.VocabularySource
+
+	// Post-processing stages run on every transcript, in order
+	PostProcessStages []postprocess.Spec
+
+	// Find-replace dictionary for the "replace" stage, phrase to replacement
+	ReplaceDictionary map[string]string
+
+	// Built-in PII pattern sets to redact from transcripts (card, ssn, phone, email)
+	RedactPIITypes []string
+
+	// Additional redaction patterns, regular expressions keyed by name
+	RedactCustomPatterns map[string]string
+
+	// Optional bucket for the original text of redacted transcripts
+	UnredactedS3Bucket string
+
+	// KMS key encrypting unredacted transcripts (required with UnredactedS3Bucket)
+	UnredactedKMSKeyID string
+
+	// KMS key for envelope encryption of transcripts at rest; empty stores them in plaintext
+	EncryptionKMSKeyID string
+
+	// Minimum level of structured log lines
+	LogLevel logging.Level
+
+	// CloudWatch namespace of the pipeline's embedded metrics; empty disables them
+	MetricsNamespace string
+
+	// Trace exporter (none, otlp or stdout) and optional OTLP/HTTP collector endpoint
+	TraceExporter string
+	TraceEndpoint string
+
+	// Local mode keeps buckets and job state in files under LocalDataDir, reads secrets locally and,
+	// without an ElevenLabsBaseURL, transcribes with a fake API, so nothing calls AWS
+	LocalMode    bool
+	LocalDataDir string
+
+	// USD price per audio minute by provider or provider/model, used to estimate usage cost
+	UsagePrices usage.Prices
+
+	// ElevenLabs API base URL; empty in local mode unless set, for the fake API
 	ElevenLabsBaseURL string
+
+	// Time limit of one ElevenLabs request, and how often and after what initial wait failed
+	// requests are retried
+	ElevenLabsTimeout      time.Duration
+	ElevenLabsMaxRetries   int
+	ElevenLabsRetryBackoff time.Duration
+
+	// Languages the default provider model handles well (empty means all)
+	SupportedLanguages []string
+
+	// Provider/model routes for languages outside SupportedLanguages, keyed by language code
+	LanguageRoutes map[string]model.LanguageRoute
+
+	// Optional bucket where the HTTP API, the scheduler and batch manifests drop dispatch manifests
+	// (defaults to the audio file's or the batch manifest's bucket)
+	SubmissionBucket string
+
+	// Optional tenant registry, a local JSON file or s3://bucket/key; empty serves a single tenant
+	TenantRegistry string
+
+	// How long a job deferred by its tenant's concurrency limit waits before it is dispatched again
+	QuotaRetryDelay time.Duration
+
+	// Maximum number of deferred jobs the scheduler dispatches per run
+	DrainBatchSize int
+
+	// Optional runtime settings document and how long it is cached
+	RuntimeSettings    string
+	RuntimeSettingsTTL time.Duration
+
+	// Optional SNS topic ARN for completion events
+	NotifySNSTopicARN string
+
+	// Optional EventBridge bus name or ARN for completion events
+	NotifyEventBusName string
+
+	// Optional webhook URLs that receive signed completion events
+	NotifyWebhookURLs []string
+
+	// Secrets Manager secret holding the webhook HMAC signing key (required with webhooks and batch
+	// callbacks)
+	NotifyWebhookSecretName string
+
+	// Hosts that batch manifest callbacks may notify; manifests with other callbacks are rejected
+	BatchCallbackHosts []string
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from the defaults, the CONFIG_FILE and CONFIG_SOURCE documents, and
+// environment variables, in increasing precedence
 func LoadConfig() (*Config, error) {
-	region := os.Getenv("AWS_REGION")
-	if region == "" {
-		// Default to us-east-1 if not specified
-		region = "us-east-1"
-	}
-	
-	tableName := os.Getenv("DYNAMODB_TABLE_NAME")
-	if tableName == "" {
-		return nil, errors.New("DYNAMODB_TABLE_NAME environment variable is required")
-	}
-	
-	secretName := os.Getenv("ELEVENLABS_SECRET_NAME")
-	if secretName == "" {
-		return nil, errors.New("ELEVENLABS_SECRET_NAME environment variable is required")
-	}
-	
-	// Optional values with defaults
-	outputBucket := os.Getenv("OUTPUT_S3_BUCKET")
-	
-	// Default API URL
-	elevenLabsBaseURL := os.Getenv("ELEVENLABS_BASE_URL")
-	if elevenLabsBaseURL == "" {
-		elevenLabsBaseURL = "https://api.elevenlabs.io/v1"
-	}
-	
-	return &Config{
-		AWSRegion:           region,
-		DynamoDBTableName:   tableName,
-		ElevenLabsSecretName: secretName,
-		OutputS3Bucket:      outputBucket,
-		ElevenLabsBaseURL:   elevenLabsBaseURL,
-	}, nil
-}
\ No newline at end of file
+	return Load(context.Background(), LayersFromEnv(), nil)
+}
+
+// Build validates resolved values into a Config. Every problem is reported at once in a ValidationError.
+func Build(values Values) (*Config, error) {
+	var problems []string
+	problem := func(err error) {
+		problems = append(problems, err.Error())
+	}
+
+	// Required values
+	for _, setting := range Schema {
+		if setting.Required && values.get(setting.Env) == "" {
+			problems = append(problems, setting.Env+" is required")
+		}
+	}
+
+	// Local mode, e.g. LOCAL_MODE=true and LOCAL_DATA_DIR=/tmp/transcription; sam local turns it on
+	localModeValue := values.get("LOCAL_MODE")
+	if localModeValue == "" {
+		localModeValue = values.get("AWS_SAM_LOCAL")
+	}
+	localMode := false
+	if localModeValue != "" {
+		parsed, err := strconv.ParseBool(localModeValue)
+		if err != nil {
+			problem(fmt.Errorf("invalid LOCAL_MODE %q, expected true or false", localModeValue))
+		}
+		localMode = parsed
+	}
+	if localMode {
+		if values.get("ENCRYPTION_KMS_KEY_ID") != "" {
+			problem(errors.New("ENCRYPTION_KMS_KEY_ID can't be used in local mode, which has no KMS"))
+		}
+		if strings.HasPrefix(values.get("RUNTIME_SETTINGS"), "ssm:") {
+			problem(errors.New("RUNTIME_SETTINGS can't be read from SSM in local mode"))
+		}
+		if values.get("UNREDACTED_S3_BUCKET") != "" || values.get("UNREDACTED_KMS_KEY_ID") != "" {
+			problem(errors.New("UNREDACTED_S3_BUCKET and UNREDACTED_KMS_KEY_ID can't be used in local mode, which has no KMS"))
+		}
+	}
+
+	secretSource := values.get("SECRET_SOURCE")
+	if secretSource != SecretSourceSecretsManager && secretSource != SecretSourceLocal {
+		problem(fmt.Errorf("invalid SECRET_SOURCE %q, expected %s or %s", secretSource, SecretSourceSecretsManager, SecretSourceLocal))
+	}
+
+	secretCacheTTL, err := time.ParseDuration(values.get("SECRET_CACHE_TTL"))
+	if err != nil || secretCacheTTL < 0 {
+		problem(fmt.Errorf("invalid SECRET_CACHE_TTL %q, expected a duration such as 5m", values.get("SECRET_CACHE_TTL")))
+	}
+
+	logLevel, err := logging.ParseLevel(values.get("LOG_LEVEL"))
+	if err != nil {
+		problem(fmt.Errorf("invalid LOG_LEVEL: %w", err))
+	}
+
+	traceExporter, err := tracing.ParseExporter(values.get("TRACE_EXPORTER"))
+	if err != nil {
+		problem(fmt.Errorf("invalid TRACE_EXPORTER: %w", err))
+	}
+
+	// Usage prices, e.g. USAGE_PRICES={"elevenlabs":0.0067,"elevenlabs/scribe_v1":0.0067}
+	usagePrices, err := usage.ParsePrices(values.get("USAGE_PRICES"))
+	if err != nil {
+		problem(fmt.Errorf("invalid USAGE_PRICES: %w", err))
+	}
+
+	inlineLimit, err := parsePositiveInt(values.get("INLINE_TRANSCRIPT_MAX_BYTES"))
+	if err != nil {
+		problem(fmt.Errorf("invalid INLINE_TRANSCRIPT_MAX_BYTES %q, expected a positive number of bytes", values.get("INLINE_TRANSCRIPT_MAX_BYTES")))
+	}
+
+	// Tenant quotas, e.g. QUOTA_RETRY_DELAY=2m and DRAIN_BATCH_SIZE=50
+	quotaRetryDelay, err := time.ParseDuration(values.get("QUOTA_RETRY_DELAY"))
+	if err != nil || quotaRetryDelay <= 0 {
+		problem(fmt.Errorf("invalid QUOTA_RETRY_DELAY %q, expected a positive duration such as 1m", values.get("QUOTA_RETRY_DELAY")))
+	}
+
+	drainBatchSize, err := parsePositiveInt(values.get("DRAIN_BATCH_SIZE"))
+	if err != nil {
+		problem(fmt.Errorf("invalid DRAIN_BATCH_SIZE %q, expected a positive number of jobs", values.get("DRAIN_BATCH_SIZE")))
+	}
+
+	// Runtime settings, e.g. RUNTIME_SETTINGS=ssm:/transcription/runtime and RUNTIME_SETTINGS_TTL=30s
+	if location := values.get("RUNTIME_SETTINGS"); location != "" {
+		if _, err := settings.NewSource(location, nil, nil); err != nil {
+			problem(fmt.Errorf("invalid RUNTIME_SETTINGS: %w", err))
+		}
+	}
+
+	runtimeSettingsTTL, err := time.ParseDuration(values.get("RUNTIME_SETTINGS_TTL"))
+	if err != nil || runtimeSettingsTTL <= 0 {
+		problem(fmt.Errorf("invalid RUNTIME_SETTINGS_TTL %q, expected a positive duration such as 1m", values.get("RUNTIME_SETTINGS_TTL")))
+	}
+
+	// ElevenLabs requests, e.g. ELEVENLABS_TIMEOUT=2m, ELEVENLABS_MAX_RETRIES=3 and ELEVENLABS_RETRY_BACKOFF=500ms
+	elevenLabsTimeout, err := time.ParseDuration(values.get("ELEVENLABS_TIMEOUT"))
+	if err != nil || elevenLabsTimeout <= 0 {
+		problem(fmt.Errorf("invalid ELEVENLABS_TIMEOUT %q, expected a positive duration such as 30s", values.get("ELEVENLABS_TIMEOUT")))
+	}
+
+	elevenLabsMaxRetries, err := strconv.Atoi(values.get("ELEVENLABS_MAX_RETRIES"))
+	if err != nil || elevenLabsMaxRetries < 0 {
+		problem(fmt.Errorf("invalid ELEVENLABS_MAX_RETRIES %q, expected zero or more retries", values.get("ELEVENLABS_MAX_RETRIES")))
+	}
+
+	elevenLabsRetryBackoff, err := time.ParseDuration(values.get("ELEVENLABS_RETRY_BACKOFF"))
+	if err != nil || elevenLabsRetryBackoff < 0 {
+		problem(fmt.Errorf("invalid ELEVENLABS_RETRY_BACKOFF %q, expected a duration such as 1s", values.get("ELEVENLABS_RETRY_BACKOFF")))
+	}
+
+	// Language routing, e.g. SUPPORTED_LANGUAGES=en,es and LANGUAGE_ROUTES=ja=elevenlabs:scribe_v1_ja,zh=:scribe_v1_zh
+	languageRoutes, err := parseLanguageRoutes(values.get("LANGUAGE_ROUTES"))
+	if err != nil {
+		problem(err)
+	}
+
+	// Retention, e.g. RETENTION_POLICIES=audio/calls/=30d,tenant:legal=2555d,*=365d
+	retentionPolicies, err := parseRetentionPolicies(values.get("RETENTION_POLICIES"))
+	if err != nil {
+		problem(err)
+	}
+
+	// PII redaction, e.g. REDACT_PII_TYPES=card,phone and REDACT_CUSTOM_PATTERNS={"account":"ACC-\\d{6}"}
+	var customPatterns map[string]string
+	if value := values.get("REDACT_CUSTOM_PATTERNS"); value != "" {
+		if err := json.Unmarshal([]byte(value), &customPatterns); err != nil {
+			problem(fmt.Errorf("invalid REDACT_CUSTOM_PATTERNS, expected a JSON object of name to regular expression: %w", err))
+		}
+	}
+
+	var replaceDictionary map[string]string
+	if value := values.get("REPLACE_DICTIONARY"); value != "" {
+		if err := json.Unmarshal([]byte(value), &replaceDictionary); err != nil {
+			problem(fmt.Errorf("invalid REPLACE_DICTIONARY, expected a JSON object of phrase to replacement: %w", err))
+		}
+	}
+
+	if values.get("UNREDACTED_S3_BUCKET") != "" && values.get("UNREDACTED_KMS_KEY_ID") == "" {
+		problem(errors.New("UNREDACTED_KMS_KEY_ID is required when UNREDACTED_S3_BUCKET is set"))
+	}
+
+	// Custom vocabularies, e.g. VOCABULARIES=audio/calls/=s3://config/vocab/calls.json,*=s3://config/vocab/default.json
+	vocabularies, err := parseVocabularies(values.get("VOCABULARIES"))
+	if err != nil {
+		problem(err)
+	}
+
+	// Post-processing, e.g. POSTPROCESS_STAGES=normalize,replace:skip,redact; by default vocabulary
+	// corrections and redaction run when configured
+	stages, err := postprocess.ParseSpecs(values.get("POSTPROCESS_STAGES"))
+	if err != nil {
+		problem(fmt.Errorf("invalid POSTPROCESS_STAGES: %w", err))
+	}
+	redacting := len(splitList(values.get("REDACT_PII_TYPES"))) > 0 || len(customPatterns) > 0
+	redaction, ok := findStage(stages, "redact")
+	if len(stages) > 0 && redacting && !ok {
+		// Leaving redact out of the listed stages would store transcripts with the data it should mask
+		problem(errors.New("POSTPROCESS_STAGES must include redact when REDACT_PII_TYPES or REDACT_CUSTOM_PATTERNS is set"))
+	}
+	if ok && redaction.OnError != postprocess.FailJob {
+		// A skipped redaction would store the unredacted transcript as the redacted one
+		problem(errors.New("POSTPROCESS_STAGES can't skip redact when it fails"))
+	}
+
+	// Completion notifications
+	webhookURLs := splitList(values.get("NOTIFY_WEBHOOK_URLS"))
+	if len(webhookURLs) > 0 && values.get("NOTIFY_WEBHOOK_SECRET_NAME") == "" {
+		problem(errors.New("NOTIFY_WEBHOOK_SECRET_NAME is required when NOTIFY_WEBHOOK_URLS is set"))
+	}
+	callbackHosts := splitList(values.get("BATCH_CALLBACK_HOSTS"))
+	if len(callbackHosts) > 0 && values.get("NOTIFY_WEBHOOK_SECRET_NAME") == "" {
+		problem(errors.New("NOTIFY_WEBHOOK_SECRET_NAME is required when BATCH_CALLBACK_HOSTS is set"))
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	cfg := &Config{
+		AWSRegion:               values.get("AWS_REGION"),
+		DynamoDBTableName:       values.get("DYNAMODB_TABLE_NAME"),
+		ElevenLabsSecretName:    values.get("ELEVENLABS_SECRET_NAME"),
+		SecretSource:            secretSource,
+		SecretsDir:              values.get("SECRETS_DIR"),
+		SecretCacheTTL:          secretCacheTTL,
+		OutputS3Bucket:          values.get("OUTPUT_S3_BUCKET"),
+		ArtifactsS3Bucket:       values.get("ARTIFACTS_S3_BUCKET"),
+		InlineTranscriptLimit:   inlineLimit,
+		RetentionPolicies:       retentionPolicies,
+		Vocabularies:            vocabularies,
+		PostProcessStages:       stages,
+		ReplaceDictionary:       replaceDictionary,
+		RedactPIITypes:          splitList(values.get("REDACT_PII_TYPES")),
+		RedactCustomPatterns:    customPatterns,
+		UnredactedS3Bucket:      values.get("UNREDACTED_S3_BUCKET"),
+		UnredactedKMSKeyID:      values.get("UNREDACTED_KMS_KEY_ID"),
+		EncryptionKMSKeyID:      values.get("ENCRYPTION_KMS_KEY_ID"),
+		LogLevel:                logLevel,
+		MetricsNamespace:        values.get("METRICS_NAMESPACE"),
+		TraceExporter:           traceExporter,
+		TraceEndpoint:           values.get("TRACE_OTLP_ENDPOINT"),
+		LocalMode:               localMode,
+		LocalDataDir:            values.get("LOCAL_DATA_DIR"),
+		UsagePrices:             usagePrices,
+		ElevenLabsBaseURL:       values.get("ELEVENLABS_BASE_URL"),
+		ElevenLabsTimeout:       elevenLabsTimeout,
+		ElevenLabsMaxRetries:    elevenLabsMaxRetries,
+		ElevenLabsRetryBackoff:  elevenLabsRetryBackoff,
+		SupportedLanguages:      splitList(values.get("SUPPORTED_LANGUAGES")),
+		LanguageRoutes:          languageRoutes,
+		SubmissionBucket:        values.get("SUBMISSION_BUCKET"),
+		TenantRegistry:          values.get("TENANT_REGISTRY"),
+		QuotaRetryDelay:         quotaRetryDelay,
+		DrainBatchSize:          drainBatchSize,
+		RuntimeSettings:         values.get("RUNTIME_SETTINGS"),
+		RuntimeSettingsTTL:      runtimeSettingsTTL,
+		NotifySNSTopicARN:       values.get("NOTIFY_SNS_TOPIC_ARN"),
+		NotifyEventBusName:      values.get("NOTIFY_EVENT_BUS_NAME"),
+		NotifyWebhookURLs:       webhookURLs,
+		NotifyWebhookSecretName: values.get("NOTIFY_WEBHOOK_SECRET_NAME"),
+		BatchCallbackHosts:      callbackHosts,
+	}
+
+	// Local runs read secrets from the environment or SECRETS_DIR, and use the fake API unless a
+	// transcription server is configured
+	if cfg.LocalMode {
+		cfg.SecretSource = SecretSourceLocal
+		if values["ELEVENLABS_BASE_URL"].Source == SourceDefault {
+			cfg.ElevenLabsBaseURL = ""
+		}
+	}
+
+	if len(cfg.PostProcessStages) == 0 {
+		if len(cfg.Vocabularies) > 0 {
+			cfg.PostProcessStages = append(cfg.PostProcessStages, postprocess.Spec{Name: "vocabulary", OnError: postprocess.FailJob})
+		}
+		if cfg.RedactionEnabled() {
+			cfg.PostProcessStages = append(cfg.PostProcessStages, postprocess.Spec{Name: "redact", OnError: postprocess.FailJob})
+		}
+	}
+
+	return cfg, nil
+}
+
+// Secret sources
+const (
+	SecretSourceSecretsManager = "secretsmanager"
+	SecretSourceLocal          = "local"
+)
+
+// Secrets returns the configured secret source. Secrets Manager is read through client; local secrets
+// come from SECRET_<NAME> environment variables and files in SecretsDir.
+func (c *Config) Secrets(client awsclient.SecretsManagerAPI) awsclient.SecretSource {
+	if c.SecretSource == SecretSourceLocal {
+		return awsclient.NewLocalSecrets(c.SecretsDir)
+	}
+
+	secrets := awsclient.NewSecretsManagerOperations(client)
+	secrets.SetCacheTTL(c.SecretCacheTTL)
+	return secrets
+}
+
+// NotificationsEnabled reports whether any completion event sink is configured
+func (c *Config) NotificationsEnabled() bool {
+	return c.NotifySNSTopicARN != "" || c.NotifyEventBusName != "" || len(c.NotifyWebhookURLs) > 0
+}
+
+// RedactionEnabled reports whether any PII patterns are configured
+func (c *Config) RedactionEnabled() bool {
+	return len(c.RedactPIITypes) > 0 || len(c.RedactCustomPatterns) > 0
+}
+
+// parsePositiveInt parses a whole number greater than zero
+func parsePositiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%d is not positive", n)
+	}
+	return n, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// findStage returns the spec of the named post-processing stage, if it is listed
+func findStage(specs []postprocess.Spec, name string) (postprocess.Spec, bool) {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return postprocess.Spec{}, false
+}
+
+// parseLanguageRoutes parses "lang=provider[:model],..." into language routes. An empty provider is the
+// default one; any other must be one of model.Providers.
+func parseLanguageRoutes(value string) (map[string]model.LanguageRoute, error) {
+	routes := make(map[string]model.LanguageRoute)
+	for _, entry := range splitList(value) {
+		lang, target, ok := strings.Cut(entry, "=")
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if !ok || lang == "" || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("invalid LANGUAGE_ROUTES entry %q, expected lang=provider[:model]", entry)
+		}
+
+		provider, modelID, _ := strings.Cut(strings.TrimSpace(target), ":")
+		if provider != "" && !model.IsProvider(provider) {
+			return nil, fmt.Errorf("invalid LANGUAGE_ROUTES entry %q, provider %q is not available (expected one of %s)",
+				entry, provider, strings.Join(model.Providers, ", "))
+		}
+		routes[lang] = model.LanguageRoute{Provider: provider, ModelID: modelID}
+	}
+	return routes, nil
+}
+
+// parseRetentionPolicies parses "scope=period,..." into retention policies. Scopes are "bucket/prefix",
+// "tenant:<id>" or "*" for every job; periods are whole days ("30d") or Go durations ("720h").
+func parseRetentionPolicies(value string) ([]model.RetentionPolicy, error) {
+	var policies []model.RetentionPolicy
+	for _, entry := range splitList(value) {
+		scope, period, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RETENTION_POLICIES entry %q, expected scope=period", entry)
+		}
+		parsed, err := model.ParseScope(strings.TrimSpace(scope))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_POLICIES entry %q: %w", entry, err)
+		}
+
+		d, err := parseRetentionPeriod(strings.TrimSpace(period))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_POLICIES entry %q: %w", entry, err)
+		}
+		policies = append(policies, model.RetentionPolicy{Scope: parsed, Period: d})
+	}
+	return policies, nil
+}
+
+// parseVocabularies parses "scope=s3://bucket/key,..." into vocabulary sources. Scopes are
+// "bucket/prefix", "tenant:<id>" or "*" for every job.
+func parseVocabularies(value string) ([]model.VocabularySource, error) {
+	var sources []model.VocabularySource
+	for _, entry := range splitList(value) {
+		scope, location, ok := strings.Cut(entry, "=")
+		location = strings.TrimSpace(location)
+		if !ok || !strings.HasPrefix(location, "s3://") {
+			return nil, fmt.Errorf("invalid VOCABULARIES entry %q, expected scope=s3://bucket/key", entry)
+		}
+		parsed, err := model.ParseScope(strings.TrimSpace(scope))
+		if err != nil {
+			return nil, fmt.Errorf("invalid VOCABULARIES entry %q: %w", entry, err)
+		}
+		sources = append(sources, model.VocabularySource{Scope: parsed, Location: location})
+	}
+	return sources, nil
+}
+
+// parseRetentionPeriod parses a positive period given in days ("30d") or as a Go duration
+func parseRetentionPeriod(value string) (time.Duration, error) {
+	var d time.Duration
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("retention period must be positive, got %q", value)
+	}
+	return d, nil
+}
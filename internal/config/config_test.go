@@ -3,8 +3,15 @@ package config
 import (
 	"os"
 	"testing"
-	
+	"time"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"github.com/yourusername/transcription-service/internal/usage"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -56,4 +63,343 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "us-east-1", config.AWSRegion, "Expected default AWS region")
 	assert.Equal(t, "https://api.elevenlabs.io/v1", config.ElevenLabsBaseURL, "Expected default ElevenLabs API URL")
 	assert.Equal(t, "", config.OutputS3Bucket, "Expected empty output bucket")
-}
\ No newline at end of file
+}
+func TestParseLanguageRoutes(t *testing.T) {
+	routes, err := parseLanguageRoutes("ja=elevenlabs:scribe_v1_ja, FR=:scribe_fr")
+	assert.NoError(t, err)
+	assert.Equal(t, "elevenlabs", routes["ja"].Provider)
+	assert.Equal(t, "scribe_v1_ja", routes["ja"].ModelID)
+	assert.Equal(t, "", routes["fr"].Provider)
+	assert.Equal(t, "scribe_fr", routes["fr"].ModelID)
+	
+	_, err = parseLanguageRoutes("ja")
+	assert.Error(t, err)
+	
+	// Routes to providers that aren't registered would fail every job they match
+	_, err = parseLanguageRoutes("zh=whisper")
+	assert.EqualError(t, err, `invalid LANGUAGE_ROUTES entry "zh=whisper", provider "whisper" is not available (expected one of elevenlabs)`)
+	
+	routes, err = parseLanguageRoutes("")
+	assert.NoError(t, err)
+	assert.Empty(t, routes)
+}
+
+func TestLoadConfig_WebhooksRequireSecret(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("NOTIFY_WEBHOOK_URLS", "https://example.com/hooks, https://example.org/hooks")
+	t.Setenv("NOTIFY_WEBHOOK_SECRET_NAME", "")
+	
+	_, err := LoadConfig()
+	assert.EqualError(t, err, "NOTIFY_WEBHOOK_SECRET_NAME is required when NOTIFY_WEBHOOK_URLS is set")
+	
+	t.Setenv("NOTIFY_WEBHOOK_SECRET_NAME", "webhook-signing-key")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/hooks", "https://example.org/hooks"}, cfg.NotifyWebhookURLs)
+}
+
+func TestLoadConfig_BatchCallbacksRequireSecret(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("BATCH_CALLBACK_HOSTS", "hooks.example.com, example.org")
+	t.Setenv("NOTIFY_WEBHOOK_SECRET_NAME", "")
+	
+	_, err := LoadConfig()
+	assert.EqualError(t, err, "NOTIFY_WEBHOOK_SECRET_NAME is required when BATCH_CALLBACK_HOSTS is set")
+	
+	t.Setenv("NOTIFY_WEBHOOK_SECRET_NAME", "webhook-signing-key")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hooks.example.com", "example.org"}, cfg.BatchCallbackHosts)
+}
+
+func TestLoadConfig_InlineTranscriptLimit(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("INLINE_TRANSCRIPT_MAX_BYTES", "")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, model.DefaultInlineTranscriptLimit, cfg.InlineTranscriptLimit)
+	
+	t.Setenv("INLINE_TRANSCRIPT_MAX_BYTES", "65536")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 65536, cfg.InlineTranscriptLimit)
+	
+	t.Setenv("INLINE_TRANSCRIPT_MAX_BYTES", "lots")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid INLINE_TRANSCRIPT_MAX_BYTES "lots", expected a positive number of bytes`)
+}
+
+func TestLoadConfig_RetentionPolicies(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("RETENTION_POLICIES", "audio/calls/=30d, tenant:legal=2555d, archive=90d, *=8760h")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RetentionPolicy{
+		{Scope: model.Scope{Bucket: "audio", Prefix: "calls/"}, Period: 30 * 24 * time.Hour},
+		{Scope: model.Scope{TenantID: "legal"}, Period: 2555 * 24 * time.Hour},
+		{Scope: model.Scope{Bucket: "archive"}, Period: 90 * 24 * time.Hour},
+		{Period: 365 * 24 * time.Hour},
+	}, cfg.RetentionPolicies)
+	
+	t.Setenv("RETENTION_POLICIES", "audio/calls/=0d")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+	
+	t.Setenv("RETENTION_POLICIES", "audio/calls/")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+	
+	t.Setenv("RETENTION_POLICIES", "tenant:=30d")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Redaction(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("REDACT_PII_TYPES", "card, email")
+	t.Setenv("REDACT_CUSTOM_PATTERNS", `{"account": "ACC-\\d{6}"}`)
+	t.Setenv("UNREDACTED_S3_BUCKET", "")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.True(t, cfg.RedactionEnabled())
+	assert.Equal(t, []string{"card", "email"}, cfg.RedactPIITypes)
+	assert.Equal(t, map[string]string{"account": `ACC-\d{6}`}, cfg.RedactCustomPatterns)
+	
+	t.Setenv("UNREDACTED_S3_BUCKET", "vault")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "UNREDACTED_KMS_KEY_ID is required when UNREDACTED_S3_BUCKET is set")
+	
+	t.Setenv("REDACT_CUSTOM_PATTERNS", "account=ACC")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_PostProcessStages(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("REDACT_PII_TYPES", "card")
+	t.Setenv("POSTPROCESS_STAGES", "")
+	
+	// Redaction runs on its own unless stages are listed
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []postprocess.Spec{{Name: "redact", OnError: postprocess.FailJob}}, cfg.PostProcessStages)
+	
+	t.Setenv("POSTPROCESS_STAGES", "normalize:skip,redact")
+	t.Setenv("REPLACE_DICTIONARY", `{"eleven labs": "ElevenLabs"}`)
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []postprocess.Spec{
+		{Name: "normalize", OnError: postprocess.SkipStage},
+		{Name: "redact", OnError: postprocess.FailJob},
+	}, cfg.PostProcessStages)
+	assert.Equal(t, map[string]string{"eleven labs": "ElevenLabs"}, cfg.ReplaceDictionary)
+	
+	// Redaction can't be skipped when it fails
+	t.Setenv("POSTPROCESS_STAGES", "normalize,redact:skip")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "POSTPROCESS_STAGES can't skip redact when it fails")
+
+	// Listing stages without redact would turn redaction off
+	t.Setenv("POSTPROCESS_STAGES", "normalize")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "POSTPROCESS_STAGES must include redact when REDACT_PII_TYPES or REDACT_CUSTOM_PATTERNS is set")
+
+	t.Setenv("POSTPROCESS_STAGES", "normalize:maybe")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Vocabularies(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("REDACT_PII_TYPES", "")
+	t.Setenv("POSTPROCESS_STAGES", "")
+	t.Setenv("VOCABULARIES", "audio/calls/=s3://config/vocab/calls.json, tenant:support=s3://config/vocab/support.json, *=s3://config/vocab/default.json")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.VocabularySource{
+		{Scope: model.Scope{Bucket: "audio", Prefix: "calls/"}, Location: "s3://config/vocab/calls.json"},
+		{Scope: model.Scope{TenantID: "support"}, Location: "s3://config/vocab/support.json"},
+		{Location: "s3://config/vocab/default.json"},
+	}, cfg.Vocabularies)
+	assert.Equal(t, []postprocess.Spec{{Name: "vocabulary", OnError: postprocess.FailJob}}, cfg.PostProcessStages)
+	
+	t.Setenv("VOCABULARIES", "audio/calls/=vocab/calls.json")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid VOCABULARIES entry "audio/calls/=vocab/calls.json", expected scope=s3://bucket/key`)
+}
+
+func TestLoadConfig_Encryption(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("ENCRYPTION_KMS_KEY_ID", "alias/transcripts")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "alias/transcripts", cfg.EncryptionKMSKeyID)
+}
+
+func TestLoadConfig_LogLevel(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("LOG_LEVEL", "debug")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, logging.LevelDebug, cfg.LogLevel)
+	
+	t.Setenv("LOG_LEVEL", "loud")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid LOG_LEVEL: unknown log level "loud"`)
+}
+
+func TestLoadConfig_UsagePrices(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("USAGE_PRICES", `{"elevenlabs":0.006,"elevenlabs/scribe_v1":0.004}`)
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, usage.Prices{"elevenlabs": 0.006, "elevenlabs/scribe_v1": 0.004}, cfg.UsagePrices)
+	
+	t.Setenv("USAGE_PRICES", `{"elevenlabs":-1}`)
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "invalid USAGE_PRICES: negative price for elevenlabs")
+}
+
+func TestLoadConfig_Tracing(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, tracing.ExporterNone, cfg.TraceExporter)
+	
+	t.Setenv("TRACE_EXPORTER", "otlp")
+	t.Setenv("TRACE_OTLP_ENDPOINT", "localhost:4318")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, tracing.ExporterOTLP, cfg.TraceExporter)
+	assert.Equal(t, "localhost:4318", cfg.TraceEndpoint)
+	
+	t.Setenv("TRACE_EXPORTER", "jaeger")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid TRACE_EXPORTER: unknown trace exporter "jaeger"`)
+}
+
+func TestLoadConfig_Quotas(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.QuotaRetryDelay)
+	assert.Equal(t, 25, cfg.DrainBatchSize)
+	
+	t.Setenv("QUOTA_RETRY_DELAY", "90s")
+	t.Setenv("DRAIN_BATCH_SIZE", "100")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, cfg.QuotaRetryDelay)
+	assert.Equal(t, 100, cfg.DrainBatchSize)
+	
+	t.Setenv("DRAIN_BATCH_SIZE", "0")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid DRAIN_BATCH_SIZE "0", expected a positive number of jobs`)
+}
+
+func TestLoadConfig_Secrets(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, SecretSourceSecretsManager, cfg.SecretSource)
+	assert.Equal(t, 5*time.Minute, cfg.SecretCacheTTL)
+	assert.IsType(t, &awsclient.SecretsManagerOperations{}, cfg.Secrets(nil))
+	
+	t.Setenv("SECRET_SOURCE", "local")
+	t.Setenv("SECRETS_DIR", "/run/secrets")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, awsclient.NewLocalSecrets("/run/secrets"), cfg.Secrets(nil))
+	
+	t.Setenv("SECRET_SOURCE", "vault")
+	t.Setenv("SECRET_CACHE_TTL", "-1m")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid SECRET_SOURCE "vault", expected secretsmanager or local; `+
+		`invalid SECRET_CACHE_TTL "-1m", expected a duration such as 5m`)
+}
+
+func TestLoadConfig_RuntimeSettings(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("RUNTIME_SETTINGS", "appconfig:transcription/prod/runtime")
+	
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "appconfig:transcription/prod/runtime", cfg.RuntimeSettings)
+	assert.Equal(t, time.Minute, cfg.RuntimeSettingsTTL)
+	
+	t.Setenv("RUNTIME_SETTINGS", "https://example.com/settings.json")
+	t.Setenv("RUNTIME_SETTINGS_TTL", "soon")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid RUNTIME_SETTINGS: invalid settings location "https://example.com/settings.json", `+
+		`expected ssm:<parameter>, s3://bucket/key or appconfig:<application>/<environment>/<profile>; `+
+		`invalid RUNTIME_SETTINGS_TTL "soon", expected a positive duration such as 1m`)
+}
+
+func TestLoadConfig_LocalMode(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.False(t, cfg.LocalMode)
+	assert.Equal(t, "https://api.elevenlabs.io/v1", cfg.ElevenLabsBaseURL)
+
+	// sam local turns local mode on: secrets are read locally and the fake API is used
+	t.Setenv("AWS_SAM_LOCAL", "true")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.True(t, cfg.LocalMode)
+	assert.Equal(t, ".local", cfg.LocalDataDir)
+	assert.Equal(t, SecretSourceLocal, cfg.SecretSource)
+	assert.Equal(t, "", cfg.ElevenLabsBaseURL)
+
+	// LOCAL_MODE wins over AWS_SAM_LOCAL, and a configured server is kept
+	t.Setenv("LOCAL_MODE", "false")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.False(t, cfg.LocalMode)
+
+	t.Setenv("LOCAL_MODE", "true")
+	t.Setenv("ELEVENLABS_BASE_URL", "http://localhost:9000/v1")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000/v1", cfg.ElevenLabsBaseURL)
+
+	// AWS-only features are reported
+	t.Setenv("ENCRYPTION_KMS_KEY_ID", "alias/transcripts")
+	t.Setenv("RUNTIME_SETTINGS", "ssm:/transcription/runtime")
+	t.Setenv("UNREDACTED_S3_BUCKET", "unredacted")
+	t.Setenv("UNREDACTED_KMS_KEY_ID", "alias/unredacted")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "ENCRYPTION_KMS_KEY_ID can't be used in local mode, which has no KMS; "+
+		"RUNTIME_SETTINGS can't be read from SSM in local mode; "+
+		"UNREDACTED_S3_BUCKET and UNREDACTED_KMS_KEY_ID can't be used in local mode, which has no KMS")
+
+	t.Setenv("LOCAL_MODE", "maybe")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `invalid LOCAL_MODE "maybe", expected true or false`)
+}
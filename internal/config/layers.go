@@ -0,0 +1,291 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"gopkg.in/yaml.v3"
+)
+
+// Where a setting's value came from, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceRemote  = "remote"
+	SourceEnv     = "env"
+)
+
+// redactedValue replaces secret values in printed configuration
+const redactedValue = "********"
+
+// Layers locates the optional config documents merged between the defaults and the environment
+type Layers struct {
+	// File is a local JSON or YAML document
+	File string
+
+	// Remote is ssm:<parameter name> or s3://bucket/key, holding a JSON or YAML document
+	Remote string
+}
+
+// LayersFromEnv returns the layers named by CONFIG_FILE and CONFIG_SOURCE
+func LayersFromEnv() Layers {
+	return Layers{File: os.Getenv("CONFIG_FILE"), Remote: os.Getenv("CONFIG_SOURCE")}
+}
+
+// RemoteReader fetches remote config documents
+type RemoteReader interface {
+	GetParameter(ctx context.Context, name string) (string, error)
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// Value is a setting's resolved value
+type Value struct {
+	Setting
+	Value  string
+	Source string
+}
+
+// Values holds every setting in the Schema, by environment variable
+type Values map[string]Value
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Resolve merges the defaults, the file and remote documents, and the environment, each overriding
+// the ones before. Empty environment variables count as unset. The remote document is fetched through
+// reader, or through new AWS clients when it is nil. Unknown or malformed settings in a document are
+// skipped and returned as a ValidationError along with the merged values, so they can still be built
+// and every problem reported at once.
+func Resolve(ctx context.Context, layers Layers, reader RemoteReader) (Values, error) {
+	values := Values{}
+	for _, setting := range Schema {
+		values[setting.Env] = Value{Setting: setting, Value: setting.Default, Source: SourceDefault}
+	}
+
+	var problems []string
+	if layers.File != "" {
+		data, err := os.ReadFile(layers.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		problems = append(problems, values.merge(data, layers.File, SourceFile)...)
+	}
+
+	if layers.Remote != "" {
+		if reader == nil {
+			// The remote document may itself only be reachable in the region the file or environment names
+			region := values.get("AWS_REGION")
+			if env := os.Getenv("AWS_REGION"); env != "" {
+				region = env
+			}
+			clients, err := awsclient.NewClients(region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create AWS clients for CONFIG_SOURCE: %w", err)
+			}
+			reader = remoteReader{
+				SSMOperations: awsclient.NewSSMOperations(clients.GetSSM()),
+				S3Operations:  awsclient.NewS3Operations(clients.GetS3()),
+			}
+		}
+
+		data, err := fetchRemote(ctx, layers.Remote, reader)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, values.merge(data, layers.Remote, SourceRemote)...)
+	}
+
+	for env, value := range values {
+		if v := os.Getenv(env); v != "" {
+			value.Value = v
+			value.Source = SourceEnv
+			values[env] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		return values, &ValidationError{Problems: problems}
+	}
+	return values, nil
+}
+
+// Load resolves the layers and builds the result. Problems in the documents and in the resolved
+// values are reported together in one ValidationError.
+func Load(ctx context.Context, layers Layers, reader RemoteReader) (*Config, error) {
+	values, err := Resolve(ctx, layers, reader)
+	var merged *ValidationError
+	if err != nil && !errors.As(err, &merged) {
+		return nil, err
+	}
+
+	cfg, err := Build(values)
+	if merged == nil {
+		return cfg, err
+	}
+
+	var built *ValidationError
+	if errors.As(err, &built) {
+		merged.Problems = append(merged.Problems, built.Problems...)
+	}
+	return nil, merged
+}
+
+// remoteReader joins the SSM and S3 operations
+type remoteReader struct {
+	*awsclient.SSMOperations
+	*awsclient.S3Operations
+}
+
+// fetchRemote reads an ssm:<name> parameter or an s3://bucket/key object
+func fetchRemote(ctx context.Context, location string, reader RemoteReader) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(location, "ssm:"):
+		value, err := reader.GetParameter(ctx, strings.TrimPrefix(location, "ssm:"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CONFIG_SOURCE: %w", err)
+		}
+		return []byte(value), nil
+	case strings.HasPrefix(location, "s3://"):
+		bucket, key, err := awsclient.ParseS3URI(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONFIG_SOURCE: %w", err)
+		}
+		data, err := reader.ReadObject(ctx, bucket, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CONFIG_SOURCE: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("invalid CONFIG_SOURCE %q, expected ssm:<parameter> or s3://bucket/key", location)
+	}
+}
+
+// merge applies a JSON or YAML document of settings, returning its problems. Settings are named by
+// their file key or their environment variable.
+func (v Values) merge(data []byte, name, source string) []string {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []string{fmt.Sprintf("invalid config document %s: %v", name, err)}
+	}
+
+	byKey := map[string]Setting{}
+	for _, setting := range Schema {
+		byKey[setting.Key] = setting
+		byKey[setting.Env] = setting
+	}
+
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		setting, ok := byKey[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown setting %q in %s", key, name))
+			continue
+		}
+		if doc[key] == nil {
+			continue
+		}
+
+		value, err := flatten(setting, doc[key])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s in %s: %v", key, name, err))
+			continue
+		}
+		v[setting.Env] = Value{Setting: setting, Value: value, Source: source}
+	}
+	return problems
+}
+
+// flatten converts a document value to the setting's environment variable form
+func flatten(setting Setting, raw interface{}) (string, error) {
+	if s, ok := raw.(string); ok {
+		return s, nil
+	}
+
+	switch setting.Kind {
+	case KindList:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return "", errors.New("expected a list")
+		}
+		var parts []string
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return "", errors.New("expected a list of strings")
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ","), nil
+
+	case KindPairs:
+		pairs, ok := raw.(map[string]interface{})
+		if !ok {
+			return "", errors.New("expected an object")
+		}
+		keys := make([]string, 0, len(pairs))
+		for key := range pairs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var parts []string
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, pairs[key]))
+		}
+		return strings.Join(parts, ","), nil
+
+	case KindJSON:
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+
+	default:
+		switch raw.(type) {
+		case []interface{}, map[string]interface{}:
+			return "", errors.New("expected a single value")
+		}
+		return fmt.Sprint(raw), nil
+	}
+}
+
+// get returns a setting's value
+func (v Values) get(env string) string {
+	return v[env].Value
+}
+
+// Print writes every setting as ENV=value with the layer it came from, in Schema order. Secrets are
+// masked when redacted is set.
+func (v Values) Print(w io.Writer, redacted bool) error {
+	for _, setting := range Schema {
+		value := v[setting.Env]
+		shown := value.Value
+		if redacted && setting.Secret && shown != "" {
+			shown = redactedValue
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s  # %s\n", setting.Env, shown, value.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
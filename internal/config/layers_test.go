@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRemoteReader is a mock implementation of RemoteReader
+type MockRemoteReader struct {
+	mock.Mock
+}
+
+func (m *MockRemoteReader) GetParameter(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRemoteReader) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	args := m.Called(ctx, bucket, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestResolve_Layers(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", `
+dynamodbTableName: file-table
+elevenlabsSecretName: elevenlabs
+drainBatchSize: 50
+supportedLanguages: [en, es]
+retentionPolicies:
+  audio/calls/: 30d
+  "*": 365d
+usagePrices:
+  elevenlabs: 0.006
+`)
+	reader := new(MockRemoteReader)
+	reader.On("GetParameter", mock.Anything, "/transcription/config").Return(`{"DYNAMODB_TABLE_NAME": "remote-table", "logLevel": "warn"}`, nil)
+	t.Setenv("DYNAMODB_TABLE_NAME", "")
+	t.Setenv("DRAIN_BATCH_SIZE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	values, err := Resolve(context.Background(), Layers{File: file, Remote: "ssm:/transcription/config"}, reader)
+	assert.NoError(t, err)
+	assert.Equal(t, Value{Setting: values["AWS_REGION"].Setting, Value: "us-east-1", Source: SourceDefault}, values["AWS_REGION"])
+	assert.Equal(t, SourceRemote, values["DYNAMODB_TABLE_NAME"].Source)
+	assert.Equal(t, SourceEnv, values["LOG_LEVEL"].Source)
+
+	cfg, err := Build(values)
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-table", cfg.DynamoDBTableName)
+	assert.Equal(t, 50, cfg.DrainBatchSize)
+	assert.Equal(t, []string{"en", "es"}, cfg.SupportedLanguages)
+	assert.Equal(t, "*=365d,audio/calls/=30d", values.get("RETENTION_POLICIES"))
+	assert.Equal(t, `{"elevenlabs":0.006}`, values.get("USAGE_PRICES"))
+	assert.Equal(t, 30*time.Second, cfg.ElevenLabsTimeout)
+
+	// Unknown and malformed settings are reported together
+	file = writeConfigFile(t, "config.json", `{"dynamodbTable": "jobs", "supportedLanguages": {"en": true}}`)
+	_, err = Resolve(context.Background(), Layers{File: file}, nil)
+	assert.EqualError(t, err, `unknown setting "dynamodbTable" in `+file+`; invalid supportedLanguages in `+file+`: expected a list`)
+
+	_, err = Resolve(context.Background(), Layers{Remote: "dynamodb://config"}, reader)
+	assert.EqualError(t, err, `invalid CONFIG_SOURCE "dynamodb://config", expected ssm:<parameter> or s3://bucket/key`)
+}
+
+func TestBuild_ReportsEveryProblem(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("LOG_LEVEL", "loud")
+	t.Setenv("ELEVENLABS_MAX_RETRIES", "-1")
+	t.Setenv("NOTIFY_WEBHOOK_URLS", "https://example.com/hooks")
+	t.Setenv("NOTIFY_WEBHOOK_SECRET_NAME", "")
+
+	_, err := LoadConfig()
+	var validation *ValidationError
+	assert.ErrorAs(t, err, &validation)
+	assert.Equal(t, []string{
+		"DYNAMODB_TABLE_NAME is required",
+		`invalid LOG_LEVEL: unknown log level "loud"`,
+		`invalid ELEVENLABS_MAX_RETRIES "-1", expected zero or more retries`,
+		"NOTIFY_WEBHOOK_SECRET_NAME is required when NOTIFY_WEBHOOK_URLS is set",
+	}, validation.Problems)
+}
+
+func TestLoad_ReportsDocumentAndBuildProblems(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "")
+	t.Setenv("ELEVENLABS_SECRET_NAME", "test-secret")
+	t.Setenv("LOG_LEVEL", "")
+
+	// The unknown setting doesn't hide the missing table name or the bad log level
+	file := writeConfigFile(t, "config.yaml", "dynamodbTable: jobs\nlogLevel: loud\n")
+	_, err := Load(context.Background(), Layers{File: file}, nil)
+	var validation *ValidationError
+	assert.ErrorAs(t, err, &validation)
+	assert.Equal(t, []string{
+		`unknown setting "dynamodbTable" in ` + file,
+		"DYNAMODB_TABLE_NAME is required",
+		`invalid LOG_LEVEL: unknown log level "loud"`,
+	}, validation.Problems)
+}
+
+func TestValues_Print(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE_NAME", "test-table")
+	t.Setenv("NOTIFY_WEBHOOK_URLS", "https://hooks.example.com/t0ken")
+	t.Setenv("AWS_REGION", "")
+
+	values, err := Resolve(context.Background(), Layers{}, nil)
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, values.Print(&out, true))
+	assert.Contains(t, out.String(), "DYNAMODB_TABLE_NAME=test-table  # env\n")
+	assert.Contains(t, out.String(), "AWS_REGION=us-east-1  # default\n")
+	assert.Contains(t, out.String(), "NOTIFY_WEBHOOK_URLS=********  # env\n")
+	assert.NotContains(t, out.String(), "t0ken")
+	assert.Equal(t, len(Schema), strings.Count(out.String(), "\n"))
+
+	out.Reset()
+	assert.NoError(t, values.Print(&out, false))
+	assert.Contains(t, out.String(), "NOTIFY_WEBHOOK_URLS=https://hooks.example.com/t0ken  # env\n")
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/elevenlabs"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/settings"
+)
+
+// Kind is how a setting is written in a config file. Every setting ends up as the same string as its
+// environment variable; files may use the structured form instead.
+type Kind int
+
+const (
+	// KindString is a plain value; numbers and booleans in files are written as they are
+	KindString Kind = iota
+
+	// KindList is a comma-separated list; files may use an array
+	KindList
+
+	// KindPairs is comma-separated key=value pairs; files may use an object of strings
+	KindPairs
+
+	// KindJSON is a JSON document; files may use the object itself
+	KindJSON
+)
+
+// Setting describes one configuration option
+type Setting struct {
+	// Env is the environment variable, which also names the setting in validation errors
+	Env string
+
+	// Key is the setting's name in config files
+	Key string
+
+	// Default is used when no layer sets the setting
+	Default string
+
+	// Required settings must be set by some layer
+	Required bool
+
+	// Secret values are masked by config print --redacted
+	Secret bool
+
+	Kind        Kind
+	Description string
+}
+
+// Schema lists every configuration option, in the order config print shows them
+var Schema = []Setting{
+	{Env: "AWS_REGION", Key: "awsRegion", Default: "us-east-1", Description: "AWS region for all service clients"},
+	{Env: "DYNAMODB_TABLE_NAME", Key: "dynamodbTableName", Required: true, Description: "DynamoDB table for job state"},
+	{Env: "LOG_LEVEL", Key: "logLevel", Default: "info", Description: "minimum level of structured log lines: debug, info, warn or error"},
+	{Env: "METRICS_NAMESPACE", Key: "metricsNamespace", Description: "CloudWatch namespace of the pipeline metrics; empty disables them"},
+	{Env: "TRACE_EXPORTER", Key: "traceExporter", Default: "none", Description: "trace exporter: none, otlp or stdout"},
+	{Env: "TRACE_OTLP_ENDPOINT", Key: "traceOtlpEndpoint", Secret: true, Description: "OTLP/HTTP collector endpoint"},
+
+	{Env: "LOCAL_MODE", Key: "localMode", Description: "true runs on files in LOCAL_DATA_DIR and a fake transcription API instead of AWS; defaults to AWS_SAM_LOCAL"},
+	{Env: "LOCAL_DATA_DIR", Key: "localDataDir", Default: ".local", Description: "directory holding the local buckets and job state file"},
+	{Env: "AWS_SAM_LOCAL", Key: "awsSamLocal", Description: "set to true by sam local"},
+
+	{Env: "SECRET_SOURCE", Key: "secretSource", Default: SecretSourceSecretsManager, Description: "where secrets are read: secretsmanager, or local for SECRET_<NAME> variables and files in SECRETS_DIR"},
+	{Env: "SECRETS_DIR", Key: "secretsDir", Description: "directory of local secret files, one per secret name"},
+	{Env: "SECRET_CACHE_TTL", Key: "secretCacheTtl", Default: awsclient.DefaultSecretTTL.String(), Description: "how long Secrets Manager secrets are cached; 0 reads them every time"},
+	{Env: "ELEVENLABS_SECRET_NAME", Key: "elevenlabsSecretName", Required: true, Description: "Secrets Manager secret with the ElevenLabs API key"},
+	{Env: "ELEVENLABS_BASE_URL", Key: "elevenlabsBaseUrl", Default: elevenlabs.DefaultBaseURL, Description: "ElevenLabs API base URL"},
+	{Env: "ELEVENLABS_TIMEOUT", Key: "elevenlabsTimeout", Default: "30s", Description: "time limit of one transcription request"},
+	{Env: "ELEVENLABS_MAX_RETRIES", Key: "elevenlabsMaxRetries", Default: "0", Description: "retries of throttled, failed or unreachable transcription requests"},
+	{Env: "ELEVENLABS_RETRY_BACKOFF", Key: "elevenlabsRetryBackoff", Default: "1s", Description: "wait before the first retry, doubled after each"},
+	{Env: "SUPPORTED_LANGUAGES", Key: "supportedLanguages", Kind: KindList, Description: "languages the default model handles well; empty means all"},
+	{Env: "LANGUAGE_ROUTES", Key: "languageRoutes", Kind: KindPairs, Description: "provider[:model] by language code for other languages"},
+
+	{Env: "OUTPUT_S3_BUCKET", Key: "outputBucket", Description: "bucket receiving transcripts"},
+	{Env: "ARTIFACTS_S3_BUCKET", Key: "artifactsBucket", Description: "bucket for transcripts too large to keep inline"},
+	{Env: "INLINE_TRANSCRIPT_MAX_BYTES", Key: "inlineTranscriptMaxBytes", Default: strconv.Itoa(model.DefaultInlineTranscriptLimit), Description: "largest transcript kept on the DynamoDB item"},
+	{Env: "RETENTION_POLICIES", Key: "retentionPolicies", Kind: KindPairs, Description: "retention period by input bucket/prefix or tenant:<id>, * for every job"},
+	{Env: "VOCABULARIES", Key: "vocabularies", Kind: KindPairs, Description: "custom vocabulary s3:// location by input bucket/prefix or tenant:<id>, * for every job"},
+	{Env: "POSTPROCESS_STAGES", Key: "postProcessStages", Kind: KindList, Description: "ordered post-processing stages, name[:fail|skip]"},
+	{Env: "REPLACE_DICTIONARY", Key: "replaceDictionary", Kind: KindJSON, Secret: true, Description: "replacement by phrase for the replace stage"},
+	{Env: "REDACT_PII_TYPES", Key: "redactPiiTypes", Kind: KindList, Description: "PII types masked in transcripts: card, ssn, phone, email"},
+	{Env: "REDACT_CUSTOM_PATTERNS", Key: "redactCustomPatterns", Kind: KindJSON, Secret: true, Description: "regular expression by name of additional redacted patterns"},
+	{Env: "UNREDACTED_S3_BUCKET", Key: "unredactedBucket", Description: "bucket keeping the original text of redacted transcripts"},
+	{Env: "UNREDACTED_KMS_KEY_ID", Key: "unredactedKmsKeyId", Description: "KMS key encrypting unredacted transcripts"},
+	{Env: "ENCRYPTION_KMS_KEY_ID", Key: "encryptionKmsKeyId", Description: "KMS key for envelope encryption of transcripts at rest"},
+	{Env: "USAGE_PRICES", Key: "usagePrices", Kind: KindJSON, Description: "USD per audio minute by provider or provider/model"},
+
+	{Env: "SUBMISSION_BUCKET", Key: "submissionBucket", Description: "bucket receiving the dispatch manifests written by the API, the scheduler and batch manifests"},
+	{Env: "TENANT_REGISTRY", Key: "tenantRegistry", Description: "tenant registry, a local path or s3://bucket/key"},
+	{Env: "QUOTA_RETRY_DELAY", Key: "quotaRetryDelay", Default: quota.DefaultRetryDelay.String(), Description: "wait of jobs deferred by a tenant's concurrency limit"},
+	{Env: "DRAIN_BATCH_SIZE", Key: "drainBatchSize", Default: strconv.Itoa(quota.DefaultDrainBatchSize), Description: "deferred jobs dispatched per scheduler run"},
+
+	{Env: "RUNTIME_SETTINGS", Key: "runtimeSettings", Description: "runtime settings document: ssm:<parameter>, s3://bucket/key or appconfig:<application>/<environment>/<profile>"},
+	{Env: "RUNTIME_SETTINGS_TTL", Key: "runtimeSettingsTtl", Default: settings.DefaultTTL.String(), Description: "how long fetched runtime settings are used before they are fetched again"},
+
+	{Env: "NOTIFY_SNS_TOPIC_ARN", Key: "notifySnsTopicArn", Description: "SNS topic for completion events"},
+	{Env: "NOTIFY_EVENT_BUS_NAME", Key: "notifyEventBusName", Description: "EventBridge bus for completion events"},
+	{Env: "NOTIFY_WEBHOOK_URLS", Key: "notifyWebhookUrls", Kind: KindList, Secret: true, Description: "webhook URLs receiving signed completion events"},
+	{Env: "NOTIFY_WEBHOOK_SECRET_NAME", Key: "notifyWebhookSecretName", Description: "Secrets Manager secret with the webhook and batch callback signing key"},
+	{Env: "BATCH_CALLBACK_HOSTS", Key: "batchCallbackHosts", Kind: KindList, Description: "hosts batch manifest callbacks may notify over https; empty rejects manifests with a callback"},
+}
@@ -4,13 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/metrics"
 	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
 )
 
 // Client provides methods for interacting with the ElevenLabs API
@@ -18,77 +25,262 @@ type Client struct {
 	httpClient  *http.Client
 	baseURL     string
 	apiKey      string
+	metrics     metrics.Recorder
+	maxRetries  int
+	backoff     time.Duration
+	secrets     awsclient.SecretSource
+	secretName  string
+	keyMu       sync.Mutex
+}
+
+// providerName is the provider dimension of the client's metrics
+const providerName = "elevenlabs"
+
+// DefaultBaseURL is the production ElevenLabs API endpoint
+const DefaultBaseURL = "https://api.elevenlabs.io/v1"
+
+// SecretsManagerAPI is the subset of the Secrets Manager client needed to load the API key
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// NewClient creates a new ElevenLabs client, loading the API key from Secrets Manager
+func NewClient(ctx context.Context, secretsClient SecretsManagerAPI, secretName string) (*Client, error) {
+	return NewClientWithSecrets(ctx, awsclient.NewSecretsManagerOperations(secretsClient), secretName)
+}
+
+// NewClientWithSecrets creates a new ElevenLabs client, loading the API key from a secret source. When
+// the API rejects the key, the client reloads it and tries the secret's pending and previous versions,
+// so requests keep working while the key is being rotated.
+func NewClientWithSecrets(ctx context.Context, secrets awsclient.SecretSource, secretName string) (*Client, error) {
+	apiKey, err := awsclient.GetSecretString(ctx, secrets, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ElevenLabs API key: %w", err)
+	}
+	
+	return &Client{
+		httpClient: defaultHTTPClient(),
+		baseURL:    DefaultBaseURL,
+		apiKey:     apiKey,
+		secrets:    secrets,
+		secretName: secretName,
+	}, nil
+}
+
+// SetMetrics records request latency and errors; by default they are discarded
+func (c *Client) SetMetrics(recorder metrics.Recorder) {
+	c.metrics = recorder
+}
+
+// record puts a metric with the provider dimension
+func (c *Client) record(ctx context.Context, name string, value float64, unit metrics.Unit, dims metrics.Dimensions) {
+	if c.metrics == nil {
+		return
+	}
+	if dims == nil {
+		dims = metrics.Dimensions{}
+	}
+	dims[metrics.DimProvider] = providerName
+	c.metrics.Put(ctx, name, value, unit, dims)
+}
+
+// SetBaseURL overrides the API base URL (e.g. from configuration)
+func (c *Client) SetBaseURL(baseURL string) {
+	if baseURL != "" {
+		c.baseURL = baseURL
+	}
+}
+
+// SetTimeout limits how long one request may take, including reading the response; zero keeps the default
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		c.httpClient = tracing.HTTPClient(&http.Client{Timeout: timeout})
+	}
+}
+
+// SetRetryPolicy retries requests that failed to connect, were throttled (429) or hit a server error
+// (5xx) up to maxRetries times, waiting backoff before the first retry and doubling it after each.
+// By default requests aren't retried.
+func (c *Client) SetRetryPolicy(maxRetries int, backoff time.Duration) {
+	c.maxRetries = maxRetries
+	c.backoff = backoff
 }
 
 // defaultHTTPClient returns a properly configured HTTP client
 func defaultHTTPClient() *http.Client {
-	return &http.Client{
+	return tracing.HTTPClient(&http.Client{
 		Timeout: 30 * time.Second,
-	}
+	})
 }
 
-// TranscribeAudio sends an audio file URL to the ElevenLabs API for transcription
-// Note: This implementation will be overridden by client_local.go when running in local mode
+// TranscribeAudio sends an audio file URL to the ElevenLabs API for transcription. In local mode the
+// base URL points at FakeHandler instead.
 func (c *Client) TranscribeAudio(ctx context.Context, audioURL string) (*model.ElevenLabsResponse, error) {
-	return c.sendTranscriptionRequest(ctx, audioURL)
+	return c.sendTranscriptionRequest(ctx, audioURL, model.TranscribeOptions{})
+}
+
+// TranscribeAudioWithOptions transcribes audio with an explicit language and/or model
+func (c *Client) TranscribeAudioWithOptions(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error) {
+	return c.sendTranscriptionRequest(ctx, audioURL, opts)
+}
+
+// SupportsKeywords reports that the ElevenLabs API boosts keywords passed as key terms
+func (c *Client) SupportsKeywords() bool {
+	return true
+}
+
+// sendTranscriptionRequest sends the request, retrying failures the retry policy allows. A rejected API
+// key is replaced by the next rotation candidate without counting as a retry.
+func (c *Client) sendTranscriptionRequest(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error) {
+	delay := c.backoff
+	var candidates []string
+	rotated := false
+	for attempt := 0; ; attempt++ {
+		response, retryable, err := c.send(ctx, audioURL, opts)
+		
+		var apiErr *statusError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized && c.secrets != nil {
+			if !rotated {
+				rotated = true
+				candidates = c.rotationKeys(ctx)
+			}
+			if len(candidates) > 0 {
+				logging.FromContext(ctx).Warn("ElevenLabs rejected the API key, trying another version of the secret")
+				c.setAPIKey(candidates[0])
+				candidates = candidates[1:]
+				attempt--
+				continue
+			}
+		}
+		
+		if err == nil || !retryable || attempt >= c.maxRetries {
+			return response, err
+		}
+		
+		logging.FromContext(ctx).Warn("Retrying ElevenLabs request", "retry", attempt+1, "delayMs", delay.Milliseconds(), logging.KeyError, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
 
-// sendTranscriptionRequest is the actual implementation that makes the HTTP request
-func (c *Client) sendTranscriptionRequest(ctx context.Context, audioURL string) (*model.ElevenLabsResponse, error) {
+// send makes one HTTP request. retryable reports whether a failure may succeed if the request is repeated.
+func (c *Client) send(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, bool, error) {
 	// Construct the API endpoint
 	endpoint := fmt.Sprintf("%s/transcribe", c.baseURL)
 	
 	// Create request body
 	requestBody := model.ElevenLabsRequest{
-		AudioURL: audioURL,
+		AudioURL:     audioURL,
+		LanguageCode: opts.LanguageCode,
+		ModelID:      opts.ModelID,
+		Keyterms:     opts.Keywords,
 	}
 	
 	// Marshal request to JSON
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, false, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 	
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, false, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("xi-api-key", c.apiKey)
+	req.Header.Set("xi-api-key", c.key())
 	
 	// Send request
+	logger := logging.FromContext(ctx).With("model", opts.ModelID, "language", opts.LanguageCode)
+	logger.Debug("Sending transcription request to ElevenLabs", "keywords", len(opts.Keywords))
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to ElevenLabs: %w", err)
+		logger.Warn("ElevenLabs request failed", logging.KeyError, err)
+		c.record(ctx, metrics.ProviderErrors, 1, metrics.Count, metrics.Dimensions{metrics.DimReason: "network"})
+		return nil, true, fmt.Errorf("failed to send request to ElevenLabs: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, false, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
+	latency := time.Since(start)
+	logger.Info("ElevenLabs responded", "statusCode", resp.StatusCode, "latencyMs", latency.Milliseconds())
+	c.record(ctx, metrics.ProviderLatency, float64(latency.Milliseconds()), metrics.Milliseconds, nil)
+	
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ElevenLabs API returned non-200 status code: %d, body: %s", 
-			resp.StatusCode, string(respBody))
+		c.record(ctx, metrics.ProviderErrors, 1, metrics.Count, metrics.Dimensions{metrics.DimReason: strconv.Itoa(resp.StatusCode)})
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, &statusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 	
 	// Parse response
 	var response model.ElevenLabsResponse
 	err = json.Unmarshal(respBody, &response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	
 	// Check for API-level errors
 	if !response.Success {
-		return nil, fmt.Errorf("ElevenLabs API returned error: %s", response.Error)
+		c.record(ctx, metrics.ProviderErrors, 1, metrics.Count, metrics.Dimensions{metrics.DimReason: "api"})
+		return nil, false, fmt.Errorf("ElevenLabs API returned error: %s", response.Error)
+	}
+	
+	return &response, false, nil
+}
+// statusError is a non-200 response from the API
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("ElevenLabs API returned non-200 status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// key returns the API key requests are sent with
+func (c *Client) key() string {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	return c.apiKey
+}
+
+// setAPIKey replaces the API key requests are sent with
+func (c *Client) setAPIKey(apiKey string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	c.apiKey = apiKey
+}
+
+// rotationKeys reads the secret again and returns its versions other than the rejected key, in the
+// order they should be tried
+func (c *Client) rotationKeys(ctx context.Context) []string {
+	rejected := c.key()
+	c.secrets.Invalidate(c.secretName)
+	
+	candidates, err := awsclient.RotationCandidates(ctx, c.secrets, c.secretName)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to reload the ElevenLabs API key", logging.KeyError, err)
+		return nil
 	}
 	
-	return &response, nil
-}
\ No newline at end of file
+	var keys []string
+	for _, candidate := range candidates {
+		if candidate.String != "" && candidate.String != rejected {
+			keys = append(keys, candidate.String)
+		}
+	}
+	return keys
+}
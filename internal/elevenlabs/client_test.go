@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
-	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/metrics"
 	"github.com/yourusername/transcription-service/internal/model"
 )
 
@@ -112,4 +114,179 @@ func TestTranscribeAudio_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "Invalid audio format")
 	
 	mockSecretsClient.AssertExpectations(t)
-}
\ No newline at end of file
+}
+func TestTranscribeAudioWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req model.ElevenLabsRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		assert.NoError(t, err)
+		assert.Equal(t, "ja", req.LanguageCode)
+		assert.Equal(t, "scribe_ja", req.ModelID)
+		assert.Equal(t, []string{"ElevenLabs"}, req.Keyterms)
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"x","text":"こんにちは","language_code":"ja","language_probability":0.98,"success":true}`))
+	}))
+	defer server.Close()
+	
+	client := &Client{httpClient: defaultHTTPClient(), baseURL: server.URL, apiKey: "key"}
+	
+	resp, err := client.TranscribeAudioWithOptions(context.Background(), "https://example.com/audio.aac", model.TranscribeOptions{
+		LanguageCode: "ja",
+		ModelID:      "scribe_ja",
+		Keywords:     []string{"ElevenLabs"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ja", resp.LanguageCode)
+	assert.Equal(t, 0.98, resp.LanguageProbability)
+}
+
+func TestTranscribeAudio_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("xi-api-key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(model.ElevenLabsResponse{Text: "Hi.", Success: true})
+	}))
+	defer server.Close()
+	
+	recorder := metrics.NewMemory()
+	client := &Client{httpClient: defaultHTTPClient(), baseURL: server.URL, apiKey: "key"}
+	client.SetMetrics(recorder)
+	ctx := metrics.WithDimensions(context.Background(), metrics.Dimensions{metrics.DimPrefix: "calls/"})
+	
+	_, err := client.TranscribeAudio(ctx, "https://example.com/a.aac")
+	assert.NoError(t, err)
+	
+	// Latency is recorded with the provider and the caller's dimensions
+	data := recorder.Data()
+	assert.Len(t, data, 1)
+	assert.Equal(t, metrics.ProviderLatency, data[0].Name)
+	assert.Equal(t, metrics.Milliseconds, data[0].Unit)
+	assert.Equal(t, metrics.Dimensions{metrics.DimProvider: "elevenlabs", metrics.DimPrefix: "calls/"}, data[0].Dimensions)
+	
+	// Errors are counted by status code
+	client.apiKey = "wrong"
+	_, err = client.TranscribeAudio(ctx, "https://example.com/a.aac")
+	assert.Error(t, err)
+	assert.Equal(t, float64(1), recorder.Sum(metrics.ProviderErrors, metrics.Dimensions{metrics.DimReason: "401"}))
+}
+
+func TestTranscribeAudio_Retries(t *testing.T) {
+	mockSecretsClient := new(MockSecretsManagerClient)
+	apiKey := "test-api-key"
+	secretName := "test-secret"
+	
+	mockSecretsClient.On("GetSecretValue", mock.Anything, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: &apiKey,
+	}, nil)
+	
+	// Throttled twice, then answered
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(model.ElevenLabsResponse{Text: "Hello", Success: true})
+	}))
+	defer server.Close()
+	
+	client, err := NewClient(context.Background(), mockSecretsClient, secretName)
+	assert.NoError(t, err)
+	client.baseURL = server.URL + "/v1"
+	client.SetRetryPolicy(2, time.Millisecond)
+	
+	resp, err := client.TranscribeAudio(context.Background(), "https://example.com/audio.aac")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Text)
+	assert.Equal(t, 3, calls)
+	
+	// Client errors aren't retried
+	calls = 0
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	client.baseURL = rejecting.URL + "/v1"
+	
+	_, err = client.TranscribeAudio(context.Background(), "https://example.com/audio.aac")
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// rotatingSecrets serves fixed versions of a secret by stage
+type rotatingSecrets map[string]string
+
+func (r rotatingSecrets) GetSecretStage(ctx context.Context, name, stage string) (*awsclient.Secret, error) {
+	value, ok := r[stage]
+	if !ok {
+		return nil, awsclient.ErrSecretNotFound
+	}
+	return &awsclient.Secret{Name: name, VersionID: stage, Stage: stage, String: value}, nil
+}
+
+func (r rotatingSecrets) Invalidate(name string) {}
+
+func TestTranscribeAudio_RotatedKey(t *testing.T) {
+	// Mid-rotation the provider only accepts the key that is still AWSPREVIOUS
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("xi-api-key"))
+		if r.Header.Get("xi-api-key") != "old-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(model.ElevenLabsResponse{Text: "Hello", Success: true})
+	}))
+	defer server.Close()
+	
+	secrets := rotatingSecrets{
+		awsclient.StageCurrent:  "new-key",
+		awsclient.StagePending:  "next-key",
+		awsclient.StagePrevious: "old-key",
+	}
+	client, err := NewClientWithSecrets(context.Background(), secrets, "api-key")
+	assert.NoError(t, err)
+	client.baseURL = server.URL
+	
+	resp, err := client.TranscribeAudio(context.Background(), "https://example.com/audio.aac")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Text)
+	assert.Equal(t, []string{"new-key", "next-key", "old-key"}, keys)
+	
+	// The working key is kept for later requests
+	keys = nil
+	_, err = client.TranscribeAudio(context.Background(), "https://example.com/audio.aac")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old-key"}, keys)
+	
+	// When no version is accepted the error is returned
+	delete(secrets, awsclient.StagePrevious)
+	client.setAPIKey("new-key")
+	_, err = client.TranscribeAudio(context.Background(), "https://example.com/audio.aac")
+	assert.ErrorContains(t, err, "status code: 401")
+}
+
+func TestFakeHandler(t *testing.T) {
+	server := httptest.NewServer(FakeHandler())
+	defer server.Close()
+
+	client := &Client{httpClient: defaultHTTPClient(), baseURL: server.URL + "/v1", apiKey: "any"}
+
+	resp, err := client.TranscribeAudioWithOptions(context.Background(), "file:///data/recordings/call.mp3", model.TranscribeOptions{LanguageCode: "es"})
+	assert.NoError(t, err)
+	assert.Equal(t, "This is a local transcript of call.mp3.", resp.Text)
+	assert.Equal(t, "es", resp.LanguageCode)
+	assert.Len(t, resp.Words, 7)
+
+	// The same file always gets the same transcript
+	again, err := client.TranscribeAudio(context.Background(), "file:///data/recordings/call.mp3")
+	assert.NoError(t, err)
+	assert.Equal(t, resp.Text, again.Text)
+	assert.Equal(t, "en", again.LanguageCode)
+}
@@ -0,0 +1,59 @@
+package elevenlabs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// FakeHandler serves the transcription API without transcribing anything, for local runs and tests.
+// Every request is answered with a transcript naming the audio file, with one word per half second and a
+// second of silence at the end, so the same file always gets the same transcript. The API key isn't checked.
+func FakeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/transcribe") {
+			http.NotFound(w, r)
+			return
+		}
+
+		var req model.ElevenLabsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AudioURL == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(model.ElevenLabsResponse{Error: "audio_url is required"})
+			return
+		}
+
+		name := req.AudioURL
+		if u, err := url.Parse(req.AudioURL); err == nil {
+			name = path.Base(u.Path)
+		}
+		text := fmt.Sprintf("This is a local transcript of %s.", name)
+
+		language := req.LanguageCode
+		if language == "" {
+			language = "en"
+		}
+
+		var words []model.Word
+		for i, token := range strings.Fields(text) {
+			start := float64(i) * 0.5
+			words = append(words, model.Word{Text: token, Start: start, End: start + 0.4, Type: "word"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(model.ElevenLabsResponse{
+			ID:                  "local-" + name,
+			Text:                text,
+			LanguageCode:        language,
+			LanguageProbability: 1,
+			Words:               words,
+			AudioDuration:       float64(len(words))*0.5 + 1,
+			Success:             true,
+		})
+	})
+}
@@ -0,0 +1,187 @@
+// Package envelope encrypts transcript content with per-item data keys from KMS. Content is sealed
+// locally with AES-256-GCM, so KMS only ever sees the 32-byte data keys.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// objectFormat marks S3 objects that hold a serialized envelope instead of plain content
+const objectFormat = "v1"
+
+// objectPrefix is how every serialized envelope starts
+var objectPrefix = []byte(`{"envelope":"` + objectFormat + `"`)
+
+// ErrContextMismatch is returned when an envelope was sealed under a different encryption context than
+// the one it is opened for, e.g. a ciphertext copied from another job
+var ErrContextMismatch = errors.New("envelope encryption context does not match")
+
+// JobContext is the encryption context binding content to the job with the given FileIdentifier
+func JobContext(fileIdentifier string) map[string]string {
+	return map[string]string{"FileIdentifier": fileIdentifier}
+}
+
+// KeyService generates and decrypts data keys; KMS in production
+type KeyService interface {
+	// GenerateDataKey returns a new 256-bit data key in plaintext and encrypted by keyID
+	GenerateDataKey(ctx context.Context, keyID string, encryptionContext map[string]string) (plaintext, encrypted []byte, err error)
+
+	// Decrypt returns the plaintext of a data key encrypted by keyID
+	Decrypt(ctx context.Context, keyID string, encrypted []byte, encryptionContext map[string]string) ([]byte, error)
+}
+
+// Encrypter seals and opens envelopes
+type Encrypter struct {
+	keys  KeyService
+	keyID string
+}
+
+// NewEncrypter creates an encrypter that seals content under keyID. An encrypter without a key ID can
+// still open envelopes, since they record the key that sealed them.
+func NewEncrypter(keys KeyService, keyID string) *Encrypter {
+	return &Encrypter{keys: keys, keyID: keyID}
+}
+
+// KeyID returns the key new envelopes are sealed under
+func (e *Encrypter) KeyID() string {
+	return e.keyID
+}
+
+// Seal encrypts plaintext under a new data key bound to the encryption context
+func (e *Encrypter) Seal(ctx context.Context, plaintext []byte, encryptionContext map[string]string) (*model.Envelope, error) {
+	if e.keyID == "" {
+		return nil, errors.New("no encryption key is configured")
+	}
+
+	dataKey, encryptedKey, err := e.keys.GenerateDataKey(ctx, e.keyID, encryptionContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &model.Envelope{
+		Algorithm:    model.EnvelopeAlgorithm,
+		KeyID:        e.keyID,
+		EncryptedKey: encryptedKey,
+		Context:      encryptionContext,
+		Nonce:        nonce,
+		Ciphertext:   gcm.Seal(nil, nonce, plaintext, additionalData(encryptionContext)),
+	}, nil
+}
+
+// Open decrypts an envelope sealed under the expected encryption context. An envelope carrying any
+// other context is refused with ErrContextMismatch, so it can't be replayed on content it wasn't sealed for.
+func (e *Encrypter) Open(ctx context.Context, envelope *model.Envelope, expected map[string]string) ([]byte, error) {
+	if envelope.Algorithm != model.EnvelopeAlgorithm {
+		return nil, fmt.Errorf("unsupported envelope algorithm %q", envelope.Algorithm)
+	}
+	if !sameContext(envelope.Context, expected) {
+		return nil, ErrContextMismatch
+	}
+
+	dataKey, err := e.keys.Decrypt(ctx, envelope.KeyID, envelope.EncryptedKey, expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("envelope nonce has the wrong size")
+	}
+
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, additionalData(expected))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt envelope: %w", err)
+	}
+	return plaintext, nil
+}
+
+// SealObject encrypts content for storage as a self-describing S3 object
+func (e *Encrypter) SealObject(ctx context.Context, plaintext []byte, encryptionContext map[string]string) ([]byte, error) {
+	envelope, err := e.Seal(ctx, plaintext, encryptionContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Format string `json:"envelope"`
+		*model.Envelope
+	}{objectFormat, envelope})
+}
+
+// OpenObject decrypts an object written by SealObject under the expected encryption context. Objects
+// that aren't envelopes, such as those written before encryption was enabled, are returned unchanged.
+func (e *Encrypter) OpenObject(ctx context.Context, data []byte, expected map[string]string) ([]byte, error) {
+	if !IsSealedObject(data) {
+		return data, nil
+	}
+
+	var envelope model.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+	return e.Open(ctx, &envelope, expected)
+}
+
+// IsSealedObject reports whether an object's content was written by SealObject
+func IsSealedObject(data []byte) bool {
+	return bytes.HasPrefix(data, objectPrefix)
+}
+
+// sameContext reports whether two encryption contexts hold the same pairs
+func sameContext(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// additionalData renders the encryption context in a canonical order for GCM authentication
+func additionalData(encryptionContext map[string]string) []byte {
+	keys := make([]string, 0, len(encryptionContext))
+	for k := range encryptionContext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%d:%s%d:%s", len(k), k, len(encryptionContext[k]), encryptionContext[k])
+	}
+	return []byte(b.String())
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package envelope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	keys, err := NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := NewEncrypter(keys, "alias/transcripts")
+	ctx := context.Background()
+	encCtx := JobContext("audio/call.mp3")
+
+	sealed, err := enc.Seal(ctx, []byte("hello world"), encCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, "alias/transcripts", sealed.KeyID)
+	assert.NotContains(t, string(sealed.Ciphertext), "hello")
+
+	plaintext, err := enc.Open(ctx, sealed, encCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(plaintext))
+
+	// The envelope is bound to its encryption context, even when its recorded context is rewritten
+	sealed.Context = JobContext("audio/other.mp3")
+	_, err = enc.Open(ctx, sealed, sealed.Context)
+	assert.Error(t, err)
+
+	// Sealing needs a key, opening doesn't
+	_, err = NewEncrypter(keys, "").Seal(ctx, []byte("x"), nil)
+	assert.EqualError(t, err, "no encryption key is configured")
+}
+
+func TestSealObject(t *testing.T) {
+	keys, err := NewLocalKeyService("key-1")
+	assert.NoError(t, err)
+	enc := NewEncrypter(keys, "key-1")
+	ctx := context.Background()
+
+	encCtx := map[string]string{"Object": "out.json"}
+	data, err := enc.SealObject(ctx, []byte(`{"text":"secret"}`), encCtx)
+	assert.NoError(t, err)
+	assert.True(t, IsSealedObject(data))
+	assert.NotContains(t, string(data), "secret")
+
+	plaintext, err := enc.OpenObject(ctx, data, encCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text":"secret"}`, string(plaintext))
+
+	// Objects written without encryption pass through
+	plaintext, err = enc.OpenObject(ctx, []byte("plain transcript"), encCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, "plain transcript", string(plaintext))
+
+	// A different key service can't open the data key
+	other, err := NewLocalKeyService("key-1")
+	assert.NoError(t, err)
+	_, err = NewEncrypter(other, "").OpenObject(ctx, data, encCtx)
+	assert.Error(t, err)
+}
+
+func TestOpenMovedEnvelope(t *testing.T) {
+	keys, err := NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := NewEncrypter(keys, "alias/transcripts")
+	ctx := context.Background()
+
+	// Content sealed for one job can't be read back as another job's, on the item or in S3
+	sealed, err := enc.Seal(ctx, []byte("job a's transcript"), JobContext("audio/a.mp3"))
+	assert.NoError(t, err)
+	_, err = enc.Open(ctx, sealed, JobContext("audio/b.mp3"))
+	assert.ErrorIs(t, err, ErrContextMismatch)
+
+	data, err := enc.SealObject(ctx, []byte("job a's transcript"), JobContext("audio/a.mp3"))
+	assert.NoError(t, err)
+	_, err = enc.OpenObject(ctx, data, JobContext("audio/b.mp3"))
+	assert.ErrorIs(t, err, ErrContextMismatch)
+
+	plaintext, err := enc.OpenObject(ctx, data, JobContext("audio/a.mp3"))
+	assert.NoError(t, err)
+	assert.Equal(t, "job a's transcript", string(plaintext))
+}
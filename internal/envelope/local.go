@@ -0,0 +1,74 @@
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// LocalKeyService is a KeyService that wraps data keys with AES-GCM under master keys held in memory.
+// It stands in for KMS in tests and local runs; its envelopes can't be opened by anything else.
+type LocalKeyService struct {
+	masterKeys map[string][]byte
+}
+
+// NewLocalKeyService creates a key service with a random master key for each key ID
+func NewLocalKeyService(keyIDs ...string) (*LocalKeyService, error) {
+	s := &LocalKeyService{masterKeys: map[string][]byte{}}
+	for _, keyID := range keyIDs {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		s.masterKeys[keyID] = key
+	}
+	return s, nil
+}
+
+// GenerateDataKey returns a random data key and its wrapped form
+func (s *LocalKeyService) GenerateDataKey(ctx context.Context, keyID string, encryptionContext map[string]string) ([]byte, []byte, error) {
+	master, ok := s.masterKeys[keyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("key %s not found", keyID)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := newGCM(master)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	wrapped := gcm.Seal(nonce, nonce, dataKey, additionalData(encryptionContext))
+	return dataKey, wrapped, nil
+}
+
+// Decrypt unwraps a data key; like KMS it fails if the encryption context differs
+func (s *LocalKeyService) Decrypt(ctx context.Context, keyID string, encrypted []byte, encryptionContext map[string]string) ([]byte, error) {
+	master, ok := s.masterKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found", keyID)
+	}
+
+	gcm, err := newGCM(master)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted data key is too short")
+	}
+
+	nonce, sealed := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	dataKey, err := gcm.Open(nil, nonce, sealed, additionalData(encryptionContext))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext or encryption context")
+	}
+	return dataKey, nil
+}
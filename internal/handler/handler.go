@@ -2,22 +2,30 @@ package handler
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"path/filepath"
-	"strings"
-	
+	"errors"
+
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
 	"github.com/yourusername/transcription-service/internal/processor"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// FileProcessor processes individual audio files and job manifests
+type FileProcessor interface {
+	ProcessFile(ctx context.Context, bucket, key string) error
+	ProcessManifest(ctx context.Context, bucket, key string) error
+}
+
 // Handler manages the Lambda function handler
 type Handler struct {
-	processor *processor.Processor
+	processor FileProcessor
 }
 
 // NewHandler creates a new handler instance
-func NewHandler(proc *processor.Processor) *Handler {
+func NewHandler(proc FileProcessor) *Handler {
 	return &Handler{
 		processor: proc,
 	}
@@ -25,30 +33,54 @@ func NewHandler(proc *processor.Processor) *Handler {
 
 // HandleS3Event processes S3 events from Lambda
 func (h *Handler) HandleS3Event(ctx context.Context, s3Event events.S3Event) error {
-	log.Printf("Received %d record(s) from S3", len(s3Event.Records))
+	// Every line logged for this invocation carries the Lambda request ID
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		ctx = logging.With(ctx, logging.KeyRequestID, lc.AwsRequestID)
+	}
+	logging.FromContext(ctx).Info("Received S3 records", "records", len(s3Event.Records))
+	
+	ctx, span := tracing.Start(ctx, "HandleS3Event", attribute.Int("records", len(s3Event.Records)))
+	defer span.End()
 	
 	for i, record := range s3Event.Records {
 		bucket := record.S3.Bucket.Name
 		key := record.S3.Object.URLDecodedKey
+		logger := logging.FromContext(ctx).With(logging.KeyBucket, bucket, logging.KeyFile, key)
+		
+		logger.Info("Processing record", "index", i+1, "total", len(s3Event.Records))
 		
-		log.Printf("[%d/%d] Processing file: s3://%s/%s", i+1, len(s3Event.Records), bucket, key)
+		// Job manifests fan out to one job per listed file
+		if h.isManifestFile(key) {
+			if err := h.processor.ProcessManifest(ctx, bucket, key); err != nil {
+				logger.Error("Failed to process manifest", logging.KeyError, err)
+				continue
+			}
+			
+			logger.Info("Successfully processed manifest")
+			continue
+		}
 		
 		// Validate file extension
 		if !h.isValidAudioFile(key) {
-			log.Printf("Skipping file with unsupported extension: %s", key)
+			logger.Info("Skipping file with unsupported extension")
 			continue
 		}
 		
 		// Process the file
 		err := h.processor.ProcessFile(ctx, bucket, key)
+		if errors.Is(err, processor.ErrDeferred) {
+			// The scheduler dispatches the job again once its tenant is under quota
+			logger.Info("File was deferred by its tenant's quotas")
+			continue
+		}
 		if err != nil {
-			log.Printf("ERROR processing %s: %v", key, err)
+			logger.Error("Failed to process file", logging.KeyError, err)
 			// Decision: Return error to trigger Lambda retry, or continue with next file?
 			// Here we continue with next files rather than failing the entire batch
 			continue
 		}
 		
-		log.Printf("Successfully processed file: %s", key)
+		logger.Info("Successfully processed file")
 	}
 	
 	return nil
@@ -56,17 +88,10 @@ func (h *Handler) HandleS3Event(ctx context.Context, s3Event events.S3Event) err
 
 // isValidAudioFile checks if the file has a supported audio extension
 func (h *Handler) isValidAudioFile(key string) bool {
-	ext := strings.ToLower(filepath.Ext(key))
-	
-	// List of supported audio extensions
-	supportedExts := map[string]bool{
-		".aac":  true,
-		".mp3":  true,
-		".wav":  true,
-		".flac": true,
-		".ogg":  true,
-		".m4a":  true,
-	}
-	
-	return supportedExts[ext]
-}
\ No newline at end of file
+	return model.IsAudioFile(key)
+}
+
+// isManifestFile checks if the object is a sidecar job manifest
+func (h *Handler) isManifestFile(key string) bool {
+	return model.IsManifestFile(key)
+}
@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/logging"
 )
 
 // MockProcessor is a mock implementation of the processor interface
@@ -21,6 +25,12 @@ func (m *MockProcessor) ProcessFile(ctx context.Context, bucket, key string) err
 	return args.Error(0)
 }
 
+// ProcessManifest mocks the ProcessManifest method
+func (m *MockProcessor) ProcessManifest(ctx context.Context, bucket, key string) error {
+	args := m.Called(ctx, bucket, key)
+	return args.Error(0)
+}
+
 func TestHandleS3Event(t *testing.T) {
 	// Create mock processor
 	mockProc := new(MockProcessor)
@@ -108,6 +118,45 @@ func TestHandleS3Event(t *testing.T) {
 	mockProc.AssertExpectations(t)
 }
 
+func TestHandleS3Event_Manifest(t *testing.T) {
+	mockProc := new(MockProcessor)
+	handler := NewHandler(mockProc)
+	
+	mockProc.On("ProcessManifest", mock.Anything, "test-bucket", "jobs/batch-1.manifest.json").Return(nil)
+	mockProc.On("ProcessFile", mock.Anything, "test-bucket", "audio/file1.aac").Return(nil)
+	
+	event := events.S3Event{
+		Records: []events.S3EventRecord{
+			{
+				S3: events.S3Entity{
+					Bucket: events.S3Bucket{Name: "test-bucket"},
+					Object: events.S3Object{Key: "jobs/batch-1.manifest.json", URLDecodedKey: "jobs/batch-1.manifest.json"},
+				},
+			},
+			{
+				S3: events.S3Entity{
+					Bucket: events.S3Bucket{Name: "test-bucket"},
+					Object: events.S3Object{Key: "audio/file1.aac", URLDecodedKey: "audio/file1.aac"},
+				},
+			},
+		},
+	}
+	
+	err := handler.HandleS3Event(context.Background(), event)
+	assert.NoError(t, err)
+	mockProc.AssertExpectations(t)
+	mockProc.AssertNumberOfCalls(t, "ProcessFile", 1)
+}
+
+func TestIsManifestFile(t *testing.T) {
+	handler := NewHandler(nil)
+	
+	assert.True(t, handler.isManifestFile("jobs/batch.manifest.json"))
+	assert.True(t, handler.isManifestFile("jobs/BATCH.MANIFEST.JSON"))
+	assert.False(t, handler.isManifestFile("jobs/batch.json"))
+	assert.False(t, handler.isManifestFile("audio/file.aac"))
+}
+
 func TestIsValidAudioFile(t *testing.T) {
 	handler := NewHandler(nil)
 	
@@ -122,4 +171,35 @@ func TestIsValidAudioFile(t *testing.T) {
 	assert.False(t, handler.isValidAudioFile("image.jpg"))
 	assert.False(t, handler.isValidAudioFile("noextension"))
 	assert.False(t, handler.isValidAudioFile(".htaccess"))
-}
\ No newline at end of file
+}
+func TestHandleS3Event_LogsRequestID(t *testing.T) {
+	mockProc := new(MockProcessor)
+	handler := NewHandler(mockProc)
+	
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, logging.LevelInfo))
+	ctx = lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{AwsRequestID: "req-123"})
+	
+	// The processor's logs carry the invocation's request ID
+	mockProc.On("ProcessFile", mock.Anything, "test-bucket", "audio/call.mp3").Run(func(args mock.Arguments) {
+		logging.FromContext(args.Get(0).(context.Context)).Info("processing")
+	}).Return(nil)
+	
+	err := handler.HandleS3Event(ctx, events.S3Event{
+		Records: []events.S3EventRecord{
+			{
+				S3: events.S3Entity{
+					Bucket: events.S3Bucket{Name: "test-bucket"},
+					Object: events.S3Object{URLDecodedKey: "audio/call.mp3"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		assert.Contains(t, line, `"requestId":"req-123"`)
+	}
+	assert.Contains(t, buf.String(), `"msg":"processing"`)
+	assert.Contains(t, buf.String(), `"file":"audio/call.mp3"`)
+}
@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// PurgeStore reads jobs and deletes them together with their audit record
+type PurgeStore interface {
+	GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error)
+	PurgeTranscriptionItem(ctx context.Context, fileIdentifier string, version int64, record *model.PurgeRecord) error
+}
+
+// ObjectDeleter deletes S3 objects
+type ObjectDeleter interface {
+	DeleteObject(ctx context.Context, bucket, key string) error
+}
+
+// PurgeOptions describe an erasure request
+type PurgeOptions struct {
+	// RequestedBy identifies who asked for the purge; it is kept in the audit record
+	RequestedBy string
+
+	// Reason is the justification kept in the audit record
+	Reason string
+
+	// DeleteSource also deletes the source audio file
+	DeleteSource bool
+}
+
+// Purger erases jobs and their stored transcripts ahead of their retention period
+type Purger struct {
+	store   PurgeStore
+	objects ObjectDeleter
+}
+
+// NewPurger creates a new purger
+func NewPurger(store PurgeStore, objects ObjectDeleter) *Purger {
+	return &Purger{
+		store:   store,
+		objects: objects,
+	}
+}
+
+// Purge deletes a finished job's transcript objects, optionally its source audio, and finally the job
+// itself, leaving an audit record that identifies the job only by its hash. Running jobs can't be
+// purged; cancel them first. Objects are deleted before the item so a failed purge can be retried.
+func (p *Purger) Purge(ctx context.Context, id string, opts PurgeOptions) (*model.PurgeRecord, error) {
+	if opts.RequestedBy == "" {
+		return nil, errors.New("purge requires the requester to be recorded")
+	}
+
+	item, err := p.store.GetTranscriptionItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrNotFound
+	}
+	if !item.Status.Terminal() {
+		return nil, fmt.Errorf("%w: job is %s", ErrConflict, item.Status)
+	}
+
+	var locations []string
+	if item.OutputLocation != "" {
+		locations = append(locations, item.OutputLocation)
+		if bucket, key, err := awsclient.ParseS3URI(item.OutputLocation); err == nil {
+			locations = append(locations, fmt.Sprintf("s3://%s/%s", bucket, model.StructuredOutputKey(key)))
+		}
+	}
+	if item.TranscriptRef != nil && item.TranscriptRef.Location != item.OutputLocation {
+		locations = append(locations, item.TranscriptRef.Location)
+	}
+	if item.UnredactedLocation != "" {
+		locations = append(locations, item.UnredactedLocation)
+	}
+	if opts.DeleteSource && item.SourceBucket != "" {
+		locations = append(locations, fmt.Sprintf("s3://%s/%s", item.SourceBucket, item.SourceKey))
+	}
+
+	for _, location := range locations {
+		bucket, key, err := awsclient.ParseS3URI(location)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.objects.DeleteObject(ctx, bucket, key); err != nil {
+			return nil, err
+		}
+	}
+
+	purgeID, err := newPurgeID()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &model.PurgeRecord{
+		FileIdentifier: model.PurgeKey(purgeID),
+		SubjectHash:    model.JobHash(id),
+		RequestedBy:    opts.RequestedBy,
+		Reason:         opts.Reason,
+		ObjectsDeleted: len(locations),
+		SourceDeleted:  opts.DeleteSource && item.SourceBucket != "",
+		PurgedAt:       time.Now().UTC(),
+	}
+
+	err = p.store.PurgeTranscriptionItem(ctx, id, item.Version, record)
+	if errors.Is(err, awsclient.ErrVersionConflict) {
+		return nil, fmt.Errorf("%w: job changed while it was being purged", ErrConflict)
+	}
+	if errors.Is(err, awsclient.ErrItemNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("Purged job", "purgeRecord", record.FileIdentifier, "objectsDeleted", record.ObjectsDeleted)
+	return record, nil
+}
+
+// newPurgeID returns a unique ID for a purge audit record
+func newPurgeID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate purge id: %w", err)
+	}
+	return fmt.Sprintf("%d-%s", time.Now().Unix(), hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// dispatchLease is how long a dispatched job is left alone before a later drain dispatches it again,
+// in case its manifest was lost
+const dispatchLease = 15 * time.Minute
+
+// SchedulerStore finds deferred jobs and the tenants' running job counts, and frees slots that were
+// never released
+type SchedulerStore interface {
+	QueryTranscriptionItems(ctx context.Context, query model.TranscriptionQuery) (*model.TranscriptionPage, error)
+	UpdateTranscriptionItem(ctx context.Context, update *awsclient.ItemUpdate) error
+	GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error)
+	ExpireJobSlots(ctx context.Context, tenantID string, now time.Time) (int, error)
+}
+
+// Scheduler dispatches jobs that were deferred by their tenant's quotas once they are due
+type Scheduler struct {
+	service   *Service
+	store     SchedulerStore
+	tenants   *tenant.Registry
+	batchSize int
+	now       func() time.Time
+}
+
+// NewScheduler creates a scheduler that hands due jobs to the transcriber through the service's
+// manifests, at most batchSize per drain
+func NewScheduler(service *Service, store SchedulerStore, tenants *tenant.Registry, batchSize int) *Scheduler {
+	if batchSize <= 0 {
+		batchSize = quota.DefaultDrainBatchSize
+	}
+	return &Scheduler{
+		service:   service,
+		store:     store,
+		tenants:   tenants,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// Drain dispatches the due deferred jobs in weighted fair-share order and returns how many it
+// dispatched. Jobs that were changed since they were read, e.g. cancelled, are skipped. Running slots
+// of the waiting tenants whose lease ran out, e.g. because their invocation timed out, are freed first.
+func (s *Scheduler) Drain(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+
+	// Only tenants' jobs are deferred, by their tenant's quotas
+	var backlog []model.TranscriptionItem
+	for _, t := range s.tenants.Tenants() {
+		due, err := s.dueJobs(ctx, t.ID, now)
+		if err != nil {
+			return 0, err
+		}
+		backlog = append(backlog, due...)
+	}
+	if len(backlog) == 0 {
+		return 0, nil
+	}
+
+	running := map[string]int{}
+	for _, item := range backlog {
+		if _, ok := running[item.TenantID]; ok || item.TenantID == "" {
+			continue
+		}
+		expired, err := s.store.ExpireJobSlots(ctx, item.TenantID, now)
+		if err != nil {
+			return 0, err
+		}
+		if expired > 0 {
+			logging.FromContext(ctx).Warn("Freed job slots that were never released", logging.KeyTenant, item.TenantID, "expired", expired)
+		}
+
+		usage, err := s.store.GetTenantUsage(ctx, item.TenantID, now.Format(model.UsageDayFormat))
+		if err != nil {
+			return 0, err
+		}
+		running[item.TenantID] = usage.Running
+	}
+
+	dispatched := 0
+	for _, item := range quota.FairShare(backlog, running, s.tenants, s.batchSize) {
+		// Pushing the deferral out first keeps concurrent drains from dispatching the job twice
+		err := s.store.UpdateTranscriptionItem(ctx, awsclient.NewItemUpdate(item.FileIdentifier).
+			ExpectVersion(item.Version).
+			Set(awsclient.AttrDeferredUntil, now.Add(dispatchLease)))
+		if errors.Is(err, awsclient.ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return dispatched, fmt.Errorf("failed to dispatch job %s: %w", item.FileIdentifier, err)
+		}
+
+		var opts model.JobOptions
+		if item.DeferredOptions != nil {
+			opts = *item.DeferredOptions
+		}
+		name, err := newDispatchName()
+		if err != nil {
+			return dispatched, err
+		}
+
+		// A job from a batch is dispatched under that batch, which resumes and counts the job when it
+		// finishes; any other job is dispatched on its own. Each dispatch gets its own manifest so the
+		// batch's own manifest is kept.
+		manifest := model.JobManifest{
+			BatchID:  item.BatchID,
+			TenantID: item.BatchTenantID,
+			Files: []model.ManifestEntry{
+				{Bucket: item.SourceBucket, Key: item.SourceKey, Options: opts},
+			},
+		}
+		if manifest.BatchID == "" {
+			manifest.TenantID = item.TenantID
+		}
+		if err := s.service.writeManifest(ctx, name, manifest); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+
+	logging.FromContext(ctx).Info("Dispatched deferred jobs", "dispatched", dispatched, "due", len(backlog))
+	return dispatched, nil
+}
+
+// dueJobs returns up to batchSize of a tenant's deferred jobs that are due, the longest waiting first.
+// Pending jobs are read batchSize at a time, so a drain reads little more than it can dispatch.
+func (s *Scheduler) dueJobs(ctx context.Context, tenantID string, now time.Time) ([]model.TranscriptionItem, error) {
+	query := model.TranscriptionQuery{
+		Status:      model.StatusPending,
+		TenantID:    tenantID,
+		Limit:       int32(s.batchSize),
+		OldestFirst: true,
+	}
+
+	var due []model.TranscriptionItem
+	for len(due) < s.batchSize {
+		page, err := s.store.QueryTranscriptionItems(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			if item.DeferredUntil != nil && !item.DeferredUntil.After(now) && len(due) < s.batchSize {
+				due = append(due, item)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	return due, nil
+}
@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/processor"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// deferredJobs serves the scheduler's queries from the jobs it was given in a local store, and records
+// the queries and the tenants whose slots were expired
+type deferredJobs struct {
+	*awsclient.LocalStore
+	ids     []string
+	queries []model.TranscriptionQuery
+	expired []string
+}
+
+func (d *deferredJobs) QueryTranscriptionItems(ctx context.Context, query model.TranscriptionQuery) (*model.TranscriptionPage, error) {
+	d.queries = append(d.queries, query)
+	page := &model.TranscriptionPage{}
+	for _, id := range d.ids {
+		item, err := d.GetTranscriptionItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil && item.Status == query.Status && item.TenantID == query.TenantID {
+			page.Items = append(page.Items, *item)
+		}
+	}
+	return page, nil
+}
+
+func (d *deferredJobs) GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error) {
+	return &model.QuotaUsage{}, nil
+}
+
+func (d *deferredJobs) ExpireJobSlots(ctx context.Context, tenantID string, now time.Time) (int, error) {
+	d.expired = append(d.expired, tenantID)
+	return 1, nil
+}
+
+// stubClient transcribes every file as the same text
+type stubClient struct{}
+
+func (stubClient) TranscribeAudio(ctx context.Context, audioURL string) (*model.ElevenLabsResponse, error) {
+	return &model.ElevenLabsResponse{Text: "hello", Success: true}, nil
+}
+
+func (stubClient) TranscribeAudioWithOptions(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error) {
+	return &model.ElevenLabsResponse{Text: "hello", Success: true}, nil
+}
+
+func TestScheduler_DrainFinishesBatch(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := awsclient.OpenLocalStore(filepath.Join(root, "jobs.json"))
+	assert.NoError(t, err)
+	objects := awsclient.NewLocalS3(root)
+	registry, err := tenant.Parse([]byte(`{"tenants": [{"id": "support", "bucket": "audio", "prefix": "support/"}]}`))
+	assert.NoError(t, err)
+
+	// The batch's first file has finished and its second was deferred by the tenant's quotas
+	assert.NoError(t, store.CreateBatchItem(ctx, &model.BatchItem{
+		BatchID:     "batch-1",
+		TenantID:    "support",
+		BatchStatus: model.BatchStatusInProgress,
+		TotalFiles:  2,
+	}))
+	_, _, err = store.RecordBatchProgress(ctx, "support", "batch-1", model.TenantJobID("support", "support/one.mp3"), true)
+	assert.NoError(t, err)
+
+	jobID := model.TenantJobID("support", "support/two.mp3")
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	assert.NoError(t, store.CreateTranscriptionItem(ctx, &model.TranscriptionItem{
+		FileIdentifier:  jobID,
+		TenantID:        "support",
+		Status:          model.StatusPending,
+		SourceBucket:    "audio",
+		SourceKey:       "support/two.mp3",
+		BatchID:         "batch-1",
+		BatchTenantID:   "support",
+		DeferredUntil:   &due,
+		DeferredReason:  "concurrency",
+		DeferredOptions: &model.JobOptions{},
+	}))
+	assert.NoError(t, objects.UploadText(ctx, "audio", "support/two.mp3", "audio"))
+
+	deferred := &deferredJobs{LocalStore: store, ids: []string{jobID}}
+	scheduler := NewScheduler(NewService(nil, objects, "submissions"), deferred, registry, 0)
+	dispatched, err := scheduler.Drain(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	// The waiting tenant's lost slots were freed before its running jobs were counted
+	assert.Equal(t, []string{"support"}, deferred.expired)
+
+	// The transcriber runs the dispatched job under its batch, which then finishes
+	manifests, err := filepath.Glob(filepath.Join(root, "submissions", "jobs", "dispatch", "*.manifest.json"))
+	assert.NoError(t, err)
+	if !assert.Len(t, manifests, 1) {
+		return
+	}
+	proc := processor.NewProcessorWithOperations(objects, store, stubClient{}, "")
+	proc.SetTenants(registry, nil)
+	assert.NoError(t, proc.ProcessManifest(ctx, "submissions", model.DispatchManifestPrefix+filepath.Base(manifests[0])))
+
+	item, err := store.GetTranscriptionItem(ctx, jobID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusCompleted, item.Status)
+
+	batch, err := store.GetBatchItem(ctx, "support", "batch-1")
+	assert.NoError(t, err)
+	assert.Equal(t, model.BatchStatusCompleted, batch.BatchStatus)
+	assert.Equal(t, 2, batch.CompletedFiles)
+}
+
+func TestScheduler_DrainDispatchesJobWithoutBatch(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := awsclient.OpenLocalStore(filepath.Join(root, "jobs.json"))
+	assert.NoError(t, err)
+	objects := awsclient.NewLocalS3(root)
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "support", "bucket": "audio", "prefix": "support/"},
+		{"id": "sales", "bucket": "audio", "prefix": "sales/"}
+	]}`))
+	assert.NoError(t, err)
+
+	// An uploaded file that was deferred by its tenant's quotas has no batch
+	jobID := model.TenantJobID("support", "support/upload.mp3")
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	assert.NoError(t, store.CreateTranscriptionItem(ctx, &model.TranscriptionItem{
+		FileIdentifier:  jobID,
+		TenantID:        "support",
+		Status:          model.StatusPending,
+		SourceBucket:    "audio",
+		SourceKey:       "support/upload.mp3",
+		DeferredUntil:   &due,
+		DeferredReason:  "concurrency",
+		DeferredOptions: &model.JobOptions{},
+	}))
+	assert.NoError(t, objects.UploadText(ctx, "audio", "support/upload.mp3", "audio"))
+
+	deferred := &deferredJobs{LocalStore: store, ids: []string{jobID}}
+	scheduler := NewScheduler(NewService(nil, objects, "submissions"), deferred, registry, 10)
+	dispatched, err := scheduler.Drain(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	// Each tenant's pending jobs are read oldest first, a page at most the drain's size
+	if assert.Len(t, deferred.queries, 2) {
+		for _, query := range deferred.queries {
+			assert.Equal(t, model.StatusPending, query.Status)
+			assert.Equal(t, int32(10), query.Limit)
+			assert.True(t, query.OldestFirst)
+		}
+		assert.Equal(t, "support", deferred.queries[0].TenantID)
+		assert.Equal(t, "sales", deferred.queries[1].TenantID)
+	}
+
+	// The job is dispatched on its own, without a batch being made up for it
+	manifests, err := filepath.Glob(filepath.Join(root, "submissions", "jobs", "dispatch", "*.manifest.json"))
+	assert.NoError(t, err)
+	if !assert.Len(t, manifests, 1) {
+		return
+	}
+	data, err := objects.ReadObject(ctx, "submissions", model.DispatchManifestPrefix+filepath.Base(manifests[0]))
+	assert.NoError(t, err)
+	var manifest model.JobManifest
+	assert.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Empty(t, manifest.BatchID)
+	assert.Equal(t, "support", manifest.TenantID)
+
+	proc := processor.NewProcessorWithOperations(objects, store, stubClient{}, "")
+	proc.SetTenants(registry, nil)
+	assert.NoError(t, proc.ProcessManifest(ctx, "submissions", model.DispatchManifestPrefix+filepath.Base(manifests[0])))
+
+	item, err := store.GetTranscriptionItem(ctx, jobID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusCompleted, item.Status)
+	assert.Empty(t, item.BatchID)
+}
@@ -0,0 +1,250 @@
+// Package jobs implements operator actions on transcription jobs: submit, reprocess, cancel and purge.
+// Work is handed to the transcriber by writing one-file manifests that its S3 trigger picks up.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+var (
+	// ErrNotFound is returned when the job doesn't exist
+	ErrNotFound = errors.New("job not found")
+
+	// ErrConflict is returned when the job's status doesn't allow the operation
+	ErrConflict = errors.New("job status does not allow this operation")
+)
+
+// Store reads and records transcription job state
+type Store interface {
+	GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error)
+	CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error
+	CancelTranscriptionItem(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error
+	RequeueTranscriptionItem(ctx context.Context, fileIdentifier string, batch model.BatchRef, record model.AttemptRecord) error
+}
+
+// ObjectWriter uploads manifest objects
+type ObjectWriter interface {
+	UploadText(ctx context.Context, bucket, key, content string) error
+}
+
+// Service performs job operations
+type Service struct {
+	store            Store
+	objects          ObjectWriter
+	submissionBucket string
+	tenants          *tenant.Registry
+}
+
+// NewService creates a new job service. Manifests are written to submissionBucket,
+// or to the audio file's own bucket when it is empty.
+func NewService(store Store, objects ObjectWriter, submissionBucket string) *Service {
+	return &Service{
+		store:            store,
+		objects:          objects,
+		submissionBucket: submissionBucket,
+	}
+}
+
+// SetTenants stores submitted jobs under the partition of the tenant owning the audio, as the
+// transcriber does
+func (s *Service) SetTenants(registry *tenant.Registry) {
+	s.tenants = registry
+}
+
+// Submit queues an audio file for transcription. Jobs that are already completed, queued or running
+// are returned unchanged with accepted set to false.
+func (s *Service) Submit(ctx context.Context, bucket, key string, opts model.JobOptions) (item *model.TranscriptionItem, accepted bool, err error) {
+	id := s.tenants.JobID(bucket, key)
+	existing, err := s.store.GetTranscriptionItem(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Queued and running jobs are left alone, as are completed ones
+	if existing != nil && (existing.Status == model.StatusCompleted || !existing.Status.Terminal()) {
+		return existing, false, nil
+	}
+
+	if existing != nil {
+		// Failed, cancelled and rejected jobs are re-queued
+		item, err := s.Reprocess(ctx, id, opts)
+		return item, err == nil, err
+	}
+
+	batch, err := newBatch(id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	item = &model.TranscriptionItem{
+		FileIdentifier: id,
+		TenantID:       batch.TenantID,
+		Status:         model.StatusPending,
+		SourceBucket:   bucket,
+		SourceKey:      key,
+		BatchID:        batch.BatchID,
+		BatchTenantID:  batch.TenantID,
+		Metadata:       opts.Metadata,
+		LanguageHint:   opts.Language,
+	}
+	if err := s.store.CreateTranscriptionItem(ctx, item); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.enqueue(ctx, batch, bucket, key, opts); err != nil {
+		return nil, false, err
+	}
+
+	return item, true, nil
+}
+
+// Reprocess forces a new attempt for a job that isn't currently running, optionally with new
+// options such as a different provider or model.
+func (s *Service) Reprocess(ctx context.Context, id string, opts model.JobOptions) (*model.TranscriptionItem, error) {
+	item, err := s.store.GetTranscriptionItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrNotFound
+	}
+
+	batch, err := newBatch(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	err = s.store.RequeueTranscriptionItem(ctx, id, batch, model.AttemptRecord{
+		Status:     model.StatusPending,
+		StartedAt:  now,
+		FinishedAt: now,
+		Provider:   opts.Provider,
+		ModelID:    opts.ModelID,
+		Note:       fmt.Sprintf("reprocess requested (previous status %s)", item.Status),
+	})
+	if errors.Is(err, awsclient.ErrStatusConflict) {
+		return nil, fmt.Errorf("%w: job is %s", ErrConflict, item.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enqueue(ctx, batch, item.SourceBucket, item.SourceKey, opts); err != nil {
+		return nil, err
+	}
+
+	item.Status = model.StatusPending
+	item.ErrorMessage = ""
+	item.BatchID = batch.BatchID
+	item.BatchTenantID = batch.TenantID
+	item.WaitingBatches = nil
+	logging.FromContext(ctx).Info("Requeued job for reprocessing", logging.KeyFile, id)
+	return item, nil
+}
+
+// Cancel stops a pending or running job. A running attempt finishes at the provider but its
+// result is discarded.
+func (s *Service) Cancel(ctx context.Context, id string) (*model.TranscriptionItem, error) {
+	item, err := s.store.GetTranscriptionItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now().UTC()
+	err = s.store.CancelTranscriptionItem(ctx, id, model.AttemptRecord{
+		Status:     model.StatusCancelled,
+		StartedAt:  now,
+		FinishedAt: now,
+		Note:       fmt.Sprintf("cancel requested (previous status %s)", item.Status),
+	})
+	if errors.Is(err, awsclient.ErrStatusConflict) {
+		return nil, fmt.Errorf("%w: job is %s", ErrConflict, item.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status = model.StatusCancelled
+	logging.FromContext(ctx).Info("Cancelled job", logging.KeyFile, id)
+	return item, nil
+}
+
+// enqueue writes a one-file manifest for the transcriber to pick up under the job's batch
+func (s *Service) enqueue(ctx context.Context, batch model.BatchRef, bucket, key string, opts model.JobOptions) error {
+	manifest := model.JobManifest{
+		BatchID:  batch.BatchID,
+		TenantID: batch.TenantID,
+		Files: []model.ManifestEntry{
+			{Bucket: bucket, Key: key, Options: opts},
+		},
+	}
+	return s.writeManifest(ctx, batch.BatchID, manifest)
+}
+
+// writeManifest uploads a one-file dispatch manifest as jobs/dispatch/<name>.manifest.json
+func (s *Service) writeManifest(ctx context.Context, name string, manifest model.JobManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	file := manifest.Files[0]
+	manifestBucket := s.submissionBucket
+	if manifestBucket == "" {
+		manifestBucket = file.Bucket
+	}
+	manifestKey := model.DispatchManifestKey(name)
+
+	if err := s.objects.UploadText(ctx, manifestBucket, manifestKey, string(data)); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Queued job via manifest", logging.KeyFile, file.Key, logging.KeyBucket, file.Bucket,
+		"manifest", fmt.Sprintf("s3://%s/%s", manifestBucket, manifestKey))
+	return nil
+}
+
+// newBatch returns a new single-file batch for a job. It is kept in the partition of the tenant owning
+// the job, so the job and its batch name the same tenant.
+func newBatch(jobID string) (model.BatchRef, error) {
+	batchID, err := newBatchID()
+	if err != nil {
+		return model.BatchRef{}, err
+	}
+	tenantID, _, _ := model.SplitTenantJobID(jobID)
+	return model.BatchRef{TenantID: tenantID, BatchID: batchID}, nil
+}
+
+// newBatchID returns a unique batch ID for a single-file submission
+func newBatchID() (string, error) {
+	return uniqueName("job")
+}
+
+// newDispatchName returns a unique name for the manifest dispatching a deferred job
+func newDispatchName() (string, error) {
+	return uniqueName("dispatch")
+}
+
+// uniqueName returns a unique name of the given kind
+func uniqueName(kind string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate %s id: %w", kind, err)
+	}
+	return fmt.Sprintf("%s-%d-%s", kind, time.Now().Unix(), hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/processor"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// submitStore is a local store for submissions, which never cancel or requeue
+type submitStore struct {
+	*awsclient.LocalStore
+}
+
+func (submitStore) CancelTranscriptionItem(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	return nil
+}
+
+func (submitStore) RequeueTranscriptionItem(ctx context.Context, fileIdentifier string, batch model.BatchRef, record model.AttemptRecord) error {
+	return nil
+}
+
+func TestService_SubmitTenantBatch(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := awsclient.OpenLocalStore(filepath.Join(root, "jobs.json"))
+	assert.NoError(t, err)
+	objects := awsclient.NewLocalS3(root)
+	registry, err := tenant.Parse([]byte(`{"tenants": [{"id": "support", "bucket": "audio", "prefix": "support/"}]}`))
+	assert.NoError(t, err)
+	assert.NoError(t, objects.UploadText(ctx, "audio", "support/call.mp3", "audio"))
+
+	service := NewService(submitStore{store}, objects, "submissions")
+	service.SetTenants(registry)
+	item, accepted, err := service.Submit(ctx, "audio", "support/call.mp3", model.JobOptions{})
+	assert.NoError(t, err)
+	assert.True(t, accepted)
+
+	// The submission's batch is kept in the tenant's partition, next to its job
+	manifests, err := filepath.Glob(filepath.Join(root, "submissions", "jobs", "dispatch", "*.manifest.json"))
+	assert.NoError(t, err)
+	if !assert.Len(t, manifests, 1) {
+		return
+	}
+	proc := processor.NewProcessorWithOperations(objects, store, stubClient{}, "")
+	proc.SetTenants(registry, nil)
+	assert.NoError(t, proc.ProcessManifest(ctx, "submissions", model.DispatchManifestPrefix+filepath.Base(manifests[0])))
+
+	// The job reports to its own batch only, which resolves in the tenant's partition
+	assert.Equal(t, "support", item.BatchTenantID)
+	job, err := store.GetTranscriptionItem(ctx, item.FileIdentifier)
+	assert.NoError(t, err)
+	if assert.NotNil(t, job) {
+		assert.Equal(t, model.StatusCompleted, job.Status)
+		assert.Equal(t, []model.BatchRef{{TenantID: "support", BatchID: item.BatchID}}, job.Batches())
+		assert.Empty(t, job.WaitingBatches)
+	}
+
+	batch, err := store.GetBatchItem(ctx, "support", item.BatchID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, batch) {
+		assert.Equal(t, model.BatchStatusCompleted, batch.BatchStatus)
+		assert.Equal(t, 1, batch.CompletedFiles)
+
+		// The submission's batch expires like its dispatch manifest
+		assert.NotZero(t, batch.ExpiresAt)
+	}
+
+	global, err := store.GetBatchItem(ctx, "", item.BatchID)
+	assert.NoError(t, err)
+	assert.Nil(t, global)
+}
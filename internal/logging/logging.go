@@ -0,0 +1,244 @@
+// Package logging writes structured JSON log lines in the style of log/slog. A Logger carries
+// correlation fields (request ID, file, bucket, attempt, provider) and travels with the context, so
+// every line logged while handling a job can be filtered by them in CloudWatch Logs Insights.
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Correlation field names attached to job logs
+const (
+	KeyRequestID     = "requestId"
+	KeyFile          = "file"
+	KeyBucket        = "bucket"
+	KeyAttempt       = "attempt"
+	KeyProvider      = "provider"
+	KeyTenant        = "tenant"
+	KeyConfigVersion = "configVersion"
+	KeyError         = "error"
+)
+
+// Level is a log severity; the values match log/slog
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+// String returns the level name written to log lines
+func (l Level) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// ParseLevel parses a level name (debug, info, warn or error); the empty string is info
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+type field struct {
+	key   string
+	value interface{}
+}
+
+// output is shared by a logger and everything derived from it with With
+type output struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level Level
+	now   func() time.Time
+}
+
+// Logger writes JSON lines with a fixed set of fields
+type Logger struct {
+	out    *output
+	fields []field
+}
+
+// New creates a logger writing lines at level and above to w
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{out: &output{w: w, level: level, now: time.Now}}
+}
+
+// With returns a logger that adds the given key/value pairs to every line
+func (l *Logger) With(args ...interface{}) *Logger {
+	fields := make([]field, len(l.fields), len(l.fields)+len(args)/2+1)
+	copy(fields, l.fields)
+	return &Logger{out: l.out, fields: appendArgs(fields, args)}
+}
+
+// Enabled reports whether lines at level are written
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+// Debug logs at LevelDebug; args are alternating keys and values
+func (l *Logger) Debug(msg string, args ...interface{}) { l.log(LevelDebug, msg, args) }
+
+// Info logs at LevelInfo; args are alternating keys and values
+func (l *Logger) Info(msg string, args ...interface{}) { l.log(LevelInfo, msg, args) }
+
+// Warn logs at LevelWarn; args are alternating keys and values
+func (l *Logger) Warn(msg string, args ...interface{}) { l.log(LevelWarn, msg, args) }
+
+// Error logs at LevelError; args are alternating keys and values
+func (l *Logger) Error(msg string, args ...interface{}) { l.log(LevelError, msg, args) }
+
+func (l *Logger) log(level Level, msg string, args []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	fields := append([]field{
+		{"time", l.out.now().UTC().Format(time.RFC3339Nano)},
+		{"level", level.String()},
+		{"msg", msg},
+	}, l.fields...)
+	fields = appendArgs(fields, args)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	seen := make(map[string]bool, len(fields))
+	for i := len(fields) - 1; i >= 0; i-- {
+		// Later fields override earlier ones with the same key
+		if seen[fields[i].key] {
+			fields[i].key = ""
+			continue
+		}
+		seen[fields[i].key] = true
+	}
+	first := true
+	for _, f := range fields {
+		if f.key == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		writeJSON(&b, f.key)
+		b.WriteByte(':')
+		writeJSON(&b, f.value)
+	}
+	b.WriteString("}\n")
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	_, _ = io.WriteString(l.out.w, b.String())
+}
+
+// appendArgs turns alternating keys and values into fields. A value without a key is kept under
+// "!BADKEY", as log/slog does.
+func appendArgs(fields []field, args []interface{}) []field {
+	for len(args) > 0 {
+		key, ok := args[0].(string)
+		if !ok || len(args) == 1 {
+			fields = append(fields, field{"!BADKEY", args[0]})
+			args = args[1:]
+			continue
+		}
+		fields = append(fields, field{key, args[1]})
+		args = args[2:]
+	}
+	return fields
+}
+
+func writeJSON(b *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	case fmt.Stringer:
+		value = v.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	b.Write(data)
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(os.Stderr, LevelInfo)
+)
+
+// Default returns the logger used when a context carries none
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault makes l the default logger. Lines written with the standard log package are sent through
+// it at info level as well, so code not yet using contexts still produces JSON.
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defaultLogger = l
+	defaultMu.Unlock()
+
+	log.SetFlags(0)
+	log.SetOutput(stdWriter{l})
+}
+
+// stdWriter adapts the standard logger's output to a Logger
+type stdWriter struct {
+	logger *Logger
+}
+
+func (w stdWriter) Write(p []byte) (int, error) {
+	w.logger.Info(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the logger
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the context's logger, or the default logger
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// With returns a context whose logger adds the given key/value pairs to every line
+func With(ctx context.Context, args ...interface{}) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(args...))
+}
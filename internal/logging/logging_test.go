@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(level Level) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := New(&buf, level)
+	l.out.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return l, &buf
+}
+
+func TestLoggerWritesJSON(t *testing.T) {
+	l, buf := newTestLogger(LevelInfo)
+
+	l.With(KeyFile, "audio/call.mp3", KeyAttempt, 2).Warn("upload failed", KeyError, errors.New("timeout"), "bytes", 42)
+	assert.Equal(t, `{"time":"2024-03-01T12:00:00Z","level":"WARN","msg":"upload failed","file":"audio/call.mp3","attempt":2,"error":"timeout","bytes":42}`+"\n", buf.String())
+
+	// Lines below the level are dropped
+	buf.Reset()
+	l.Debug("noisy")
+	assert.Empty(t, buf.String())
+}
+
+func TestLoggerFieldsOverride(t *testing.T) {
+	l, buf := newTestLogger(LevelDebug)
+
+	l.With(KeyProvider, "elevenlabs").Debug("routed", KeyProvider, "whisper", "dangling")
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "whisper", line[KeyProvider])
+	assert.Equal(t, "dangling", line["!BADKEY"])
+	assert.Equal(t, "DEBUG", line["level"])
+}
+
+func TestContextLogger(t *testing.T) {
+	l, buf := newTestLogger(LevelInfo)
+
+	ctx := NewContext(context.Background(), l)
+	ctx = With(ctx, KeyRequestID, "req-1")
+	ctx = With(ctx, KeyBucket, "input")
+	FromContext(ctx).Info("processing")
+
+	assert.True(t, strings.Contains(buf.String(), `"requestId":"req-1","bucket":"input"`))
+
+	// Contexts without a logger use the default
+	assert.Same(t, Default(), FromContext(context.Background()))
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{"": LevelInfo, "DEBUG": LevelDebug, "warn": LevelWarn, "error": LevelError} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, level)
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.EqualError(t, err, `unknown log level "verbose"`)
+}
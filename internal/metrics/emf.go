@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EMF writes each metric value as a CloudWatch Embedded Metric Format log line
+type EMF struct {
+	mu        sync.Mutex
+	w         io.Writer
+	namespace string
+	now       func() time.Time
+}
+
+// NewEMF creates a recorder writing metrics in namespace to w, normally the Lambda's stdout
+func NewEMF(w io.Writer, namespace string) *EMF {
+	return &EMF{w: w, namespace: namespace, now: time.Now}
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+// Put writes one value; empty dimension values are left out
+func (e *EMF) Put(ctx context.Context, name string, value float64, unit Unit, dims Dimensions) {
+	line := map[string]interface{}{}
+	names := []string{}
+	for dim, v := range merge(DimensionsFrom(ctx), dims) {
+		if v == "" {
+			continue
+		}
+		line[dim] = v
+		names = append(names, dim)
+	}
+	sort.Strings(names)
+
+	line[name] = value
+	line["_aws"] = emfMetadata{
+		Timestamp: e.now().UnixNano() / int64(time.Millisecond),
+		CloudWatchMetrics: []emfDirective{{
+			Namespace:  e.namespace,
+			Dimensions: [][]string{names},
+			Metrics:    []emfMetric{{Name: name, Unit: unit}},
+		}},
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, _ = e.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,142 @@
+// Package metrics records pipeline metrics. In Lambda they are written to stdout in CloudWatch Embedded
+// Metric Format, which CloudWatch turns into metrics without any API calls; tests use the in-memory
+// recorder instead.
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Unit is a CloudWatch metric unit
+type Unit string
+
+const (
+	Count        Unit = "Count"
+	Milliseconds Unit = "Milliseconds"
+	Seconds      Unit = "Seconds"
+	Bytes        Unit = "Bytes"
+)
+
+// Metric names
+const (
+	JobsStarted       = "JobsStarted"
+	JobsCompleted     = "JobsCompleted"
+	JobsFailed        = "JobsFailed"
+	JobRetries        = "JobRetries"
+	JobsDeferred      = "JobsDeferred"
+	SkippedDuplicates = "SkippedDuplicates"
+	ProviderLatency   = "ProviderLatency"
+	ProviderErrors    = "ProviderErrors"
+	AudioDuration     = "AudioDuration"
+	TranscriptLength  = "TranscriptLength"
+)
+
+// Dimension names
+const (
+	DimProvider = "provider"
+	DimPrefix   = "prefix"
+	DimReason   = "reason"
+)
+
+// Dimensions are the dimension values of a metric, by name
+type Dimensions map[string]string
+
+// Recorder records metric values. Dimensions added to the context with WithDimensions are combined
+// with the ones passed in, which take precedence.
+type Recorder interface {
+	Put(ctx context.Context, name string, value float64, unit Unit, dims Dimensions)
+}
+
+type contextKey struct{}
+
+// WithDimensions returns a context whose metrics carry the given dimensions
+func WithDimensions(ctx context.Context, dims Dimensions) context.Context {
+	return context.WithValue(ctx, contextKey{}, merge(DimensionsFrom(ctx), dims))
+}
+
+// DimensionsFrom returns the dimensions carried by the context
+func DimensionsFrom(ctx context.Context) Dimensions {
+	dims, _ := ctx.Value(contextKey{}).(Dimensions)
+	return dims
+}
+
+// merge combines dimension sets; later sets override earlier ones
+func merge(sets ...Dimensions) Dimensions {
+	merged := Dimensions{}
+	for _, dims := range sets {
+		for name, value := range dims {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// Prefix returns the input prefix dimension of a source key: its first path segment, or "/" for keys
+// at the top of the bucket
+func Prefix(key string) string {
+	if i := strings.Index(key, "/"); i > 0 {
+		return key[:i+1]
+	}
+	return "/"
+}
+
+// Nop discards metrics
+type Nop struct{}
+
+// Put does nothing
+func (Nop) Put(ctx context.Context, name string, value float64, unit Unit, dims Dimensions) {}
+
+// Datum is a recorded metric value
+type Datum struct {
+	Name       string
+	Value      float64
+	Unit       Unit
+	Dimensions Dimensions
+}
+
+// Memory keeps metrics in memory for tests
+type Memory struct {
+	mu   sync.Mutex
+	data []Datum
+}
+
+// NewMemory creates an empty in-memory recorder
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Put records a value
+func (m *Memory) Put(ctx context.Context, name string, value float64, unit Unit, dims Dimensions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = append(m.data, Datum{Name: name, Value: value, Unit: unit, Dimensions: merge(DimensionsFrom(ctx), dims)})
+}
+
+// Data returns everything recorded so far
+func (m *Memory) Data() []Datum {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Datum(nil), m.data...)
+}
+
+// Sum adds up the values of a metric recorded with at least the given dimensions
+func (m *Memory) Sum(name string, dims Dimensions) float64 {
+	var sum float64
+	for _, d := range m.Data() {
+		if d.Name == name && matches(d.Dimensions, dims) {
+			sum += d.Value
+		}
+	}
+	return sum
+}
+
+func matches(have, want Dimensions) bool {
+	for name, value := range want {
+		if have[name] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEMF(t *testing.T) {
+	var buf bytes.Buffer
+	emf := NewEMF(&buf, "TranscriptionService")
+	emf.now = func() time.Time { return time.UnixMilli(1700000000000) }
+
+	ctx := WithDimensions(context.Background(), Dimensions{DimPrefix: "calls/", DimProvider: "elevenlabs"})
+	emf.Put(ctx, JobsFailed, 1, Count, Dimensions{DimReason: "provider", DimProvider: "whisper"})
+
+	assert.JSONEq(t, `{
+		"_aws": {
+			"Timestamp": 1700000000000,
+			"CloudWatchMetrics": [{
+				"Namespace": "TranscriptionService",
+				"Dimensions": [["prefix", "provider", "reason"]],
+				"Metrics": [{"Name": "JobsFailed", "Unit": "Count"}]
+			}]
+		},
+		"prefix": "calls/",
+		"provider": "whisper",
+		"reason": "provider",
+		"JobsFailed": 1
+	}`, buf.String())
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	ctx := WithDimensions(context.Background(), Dimensions{DimPrefix: "calls/"})
+
+	m.Put(ctx, JobsStarted, 1, Count, Dimensions{DimProvider: "elevenlabs"})
+	m.Put(ctx, JobsStarted, 1, Count, Dimensions{DimProvider: "whisper"})
+	m.Put(context.Background(), JobsStarted, 1, Count, nil)
+
+	assert.Equal(t, float64(3), m.Sum(JobsStarted, nil))
+	assert.Equal(t, float64(2), m.Sum(JobsStarted, Dimensions{DimPrefix: "calls/"}))
+	assert.Equal(t, float64(1), m.Sum(JobsStarted, Dimensions{DimPrefix: "calls/", DimProvider: "whisper"}))
+	assert.Zero(t, m.Sum(JobsCompleted, nil))
+}
+
+func TestPrefix(t *testing.T) {
+	assert.Equal(t, "calls/", Prefix("calls/2024/a.mp3"))
+	assert.Equal(t, "/", Prefix("a.mp3"))
+	assert.Equal(t, "/", Prefix("/a.mp3"))
+}
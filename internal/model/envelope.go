@@ -0,0 +1,27 @@
+package model
+
+// EnvelopeAlgorithm names the cipher used for envelope-encrypted content
+const EnvelopeAlgorithm = "AES-256-GCM"
+
+// Envelope is content encrypted under a data key that is itself encrypted by a KMS key. Everything but
+// the plaintext is kept, so the content can be decrypted by anyone allowed to decrypt the data key
+// with the same encryption context.
+type Envelope struct {
+	// Algorithm is the content cipher, EnvelopeAlgorithm
+	Algorithm string `json:"algorithm" dynamodbav:"Algorithm"`
+
+	// KeyID is the KMS key that encrypted the data key
+	KeyID string `json:"keyId" dynamodbav:"KeyID"`
+
+	// EncryptedKey is the data key, encrypted by KeyID
+	EncryptedKey []byte `json:"encryptedKey" dynamodbav:"EncryptedKey"`
+
+	// Context is the KMS encryption context; it is also authenticated with the content
+	Context map[string]string `json:"context,omitempty" dynamodbav:"Context,omitempty"`
+
+	// Nonce is the AES-GCM nonce
+	Nonce []byte `json:"nonce" dynamodbav:"Nonce"`
+
+	// Ciphertext is the encrypted content followed by the GCM tag
+	Ciphertext []byte `json:"ciphertext" dynamodbav:"Ciphertext"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "fmt"
+
+// QuotaItemPrefix prefixes the FileIdentifier of the per-tenant quota counters stored in the transcription table
+const QuotaItemPrefix = "quota#"
+
+// QuotaUsage is what a tenant is currently using of its quotas
+type QuotaUsage struct {
+	// TenantID is the tenant the counters belong to
+	TenantID string `json:"tenantId"`
+
+	// Day is the UTC date Minutes were counted on
+	Day string `json:"day"`
+
+	// Running is how many of the tenant's jobs hold a running slot
+	Running int `json:"running"`
+
+	// Minutes is how many minutes of audio the tenant transcribed on Day
+	Minutes float64 `json:"minutes"`
+}
+
+// QuotaKey returns the DynamoDB FileIdentifier of a tenant's running job counter
+func QuotaKey(tenantID string) string {
+	return QuotaItemPrefix + tenantID
+}
+
+// QuotaDayKey returns the DynamoDB FileIdentifier of a tenant's audio minute counter for one UTC day
+func QuotaDayKey(tenantID, day string) string {
+	return fmt.Sprintf("%s%s#%s", QuotaItemPrefix, tenantID, day)
+}
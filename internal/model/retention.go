@@ -0,0 +1,74 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// RetentionTagKey is the S3 object tag carrying an output's retention period in days. Bucket lifecycle
+// rules filter on it to expire objects.
+const RetentionTagKey = "retention-days"
+
+// RetentionPolicy keeps the jobs in Scope for Period after their last attempt
+type RetentionPolicy struct {
+	Scope  Scope
+	Period time.Duration
+}
+
+// Days returns the retention period in whole days, rounded up
+func (p RetentionPolicy) Days() int {
+	day := 24 * time.Hour
+	return int((p.Period + day - 1) / day)
+}
+
+// ExpiresAt returns the DynamoDB TTL (Unix seconds) for an item retained from the given time
+func (p RetentionPolicy) ExpiresAt(from time.Time) int64 {
+	return from.Add(p.Period).Unix()
+}
+
+// Tags returns the S3 object tags that let lifecycle rules expire the job's outputs
+func (p RetentionPolicy) Tags() map[string]string {
+	return map[string]string{RetentionTagKey: strconv.Itoa(p.Days())}
+}
+
+// RetentionFor returns the policy with the most specific scope matching the job
+func RetentionFor(policies []RetentionPolicy, source JobSource) (RetentionPolicy, bool) {
+	i := bestMatch(len(policies), func(i int) Scope { return policies[i].Scope }, source)
+	if i < 0 {
+		return RetentionPolicy{}, false
+	}
+	return policies[i], true
+}
+
+// PurgeRecordPrefix prefixes the FileIdentifier of purge audit records stored in the transcription table
+const PurgeRecordPrefix = "purge#"
+
+// PurgeRecord is the audit trail of an on-demand erasure. It deliberately doesn't contain the job's
+// identifier, only its hash, so the record itself holds no personal data.
+type PurgeRecord struct {
+	// FileIdentifier is PurgeRecordPrefix plus a unique purge ID
+	FileIdentifier string `json:"fileIdentifier" dynamodbav:"FileIdentifier"`
+
+	// SubjectHash is the JobHash of the purged job
+	SubjectHash string `json:"subjectHash" dynamodbav:"SubjectHash"`
+
+	// RequestedBy identifies the operator or system that requested the purge
+	RequestedBy string `json:"requestedBy" dynamodbav:"RequestedBy"`
+
+	// Reason is the operator's justification, e.g. a ticket reference
+	Reason string `json:"reason,omitempty" dynamodbav:"Reason,omitempty"`
+
+	// ObjectsDeleted is how many S3 objects were deleted
+	ObjectsDeleted int `json:"objectsDeleted" dynamodbav:"ObjectsDeleted"`
+
+	// SourceDeleted reports whether the source audio was deleted as well
+	SourceDeleted bool `json:"sourceDeleted" dynamodbav:"SourceDeleted"`
+
+	// PurgedAt is when the purge completed
+	PurgedAt time.Time `json:"purgedAt" dynamodbav:"PurgedAt"`
+}
+
+// PurgeKey returns the DynamoDB FileIdentifier for a purge ID
+func PurgeKey(purgeID string) string {
+	return PurgeRecordPrefix + purgeID
+}
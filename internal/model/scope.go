@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TenantScopePrefix marks a scope naming a tenant, e.g. "tenant:support"
+const TenantScopePrefix = "tenant:"
+
+// JobSource identifies a job's input object and the tenant that owns it
+type JobSource struct {
+	TenantID string
+	Bucket   string
+	Key      string
+}
+
+// Scope selects the jobs a per-job setting applies to: those of one tenant, those whose input is in a
+// bucket under a key prefix, or, when empty, every job
+type Scope struct {
+	TenantID string
+	Bucket   string
+	Prefix   string
+}
+
+// ParseScope parses "*" (every job), "tenant:<id>" or "bucket/prefix"; the prefix may be empty
+func ParseScope(value string) (Scope, error) {
+	if value == "*" {
+		return Scope{}, nil
+	}
+	if strings.HasPrefix(value, TenantScopePrefix) {
+		id := strings.TrimPrefix(value, TenantScopePrefix)
+		if id == "" {
+			return Scope{}, fmt.Errorf("scope %q has no tenant ID", value)
+		}
+		return Scope{TenantID: id}, nil
+	}
+	bucket, prefix, _ := strings.Cut(value, "/")
+	if bucket == "" {
+		return Scope{}, fmt.Errorf("scope %q has no bucket, expected *, tenant:<id> or bucket/prefix", value)
+	}
+	return Scope{Bucket: bucket, Prefix: prefix}, nil
+}
+
+// Matches reports whether the scope applies to a job
+func (s Scope) Matches(source JobSource) bool {
+	switch {
+	case s.TenantID != "":
+		return s.TenantID == source.TenantID
+	case s.Bucket != "":
+		return s.Bucket == source.Bucket && strings.HasPrefix(source.Key, s.Prefix)
+	default:
+		return true
+	}
+}
+
+// specificity orders matching scopes: a bucket and prefix, the longest prefix first, then a tenant,
+// then every job
+func (s Scope) specificity() int {
+	switch {
+	case s.Bucket != "":
+		return 2 + len(s.Prefix)
+	case s.TenantID != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// bestMatch returns the index of the most specific scope matching the job, or -1 if none does
+func bestMatch(n int, scope func(int) Scope, source JobSource) int {
+	match := -1
+	for i := 0; i < n; i++ {
+		s := scope(i)
+		if s.Matches(source) && (match < 0 || s.specificity() > scope(match).specificity()) {
+			match = i
+		}
+	}
+	return match
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScope(t *testing.T) {
+	for value, want := range map[string]Scope{
+		"*":               {},
+		"tenant:support":  {TenantID: "support"},
+		"audio":           {Bucket: "audio"},
+		"audio/calls/":    {Bucket: "audio", Prefix: "calls/"},
+		"audio/calls/vip": {Bucket: "audio", Prefix: "calls/vip"},
+	} {
+		scope, err := ParseScope(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, want, scope, value)
+	}
+
+	for _, value := range []string{"", "tenant:", "/calls/"} {
+		_, err := ParseScope(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRetentionFor_Scopes(t *testing.T) {
+	day := 24 * time.Hour
+	policies := []RetentionPolicy{
+		{Period: 365 * day},
+		{Scope: Scope{TenantID: "support"}, Period: 90 * day},
+		{Scope: Scope{Bucket: "audio"}, Period: 60 * day},
+		{Scope: Scope{Bucket: "audio", Prefix: "calls/"}, Period: 30 * day},
+	}
+
+	period := func(source JobSource) time.Duration {
+		policy, ok := RetentionFor(policies, source)
+		assert.True(t, ok)
+		return policy.Period
+	}
+
+	// A bucket and prefix outrank the tenant, and the longest prefix wins
+	assert.Equal(t, 30*day, period(JobSource{TenantID: "support", Bucket: "audio", Key: "calls/a.mp3"}))
+	assert.Equal(t, 60*day, period(JobSource{TenantID: "support", Bucket: "audio", Key: "memos/a.mp3"}))
+	assert.Equal(t, 90*day, period(JobSource{TenantID: "support", Bucket: "archive", Key: "calls/a.mp3"}))
+
+	// The same key in another bucket falls back to every job
+	assert.Equal(t, 365*day, period(JobSource{Bucket: "archive", Key: "calls/a.mp3"}))
+
+	_, ok := RetentionFor(policies[1:], JobSource{Bucket: "archive", Key: "calls/a.mp3"})
+	assert.False(t, ok)
+}
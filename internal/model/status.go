@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TranscriptionStatus indicates the current status of a transcription job
+type TranscriptionStatus string
+
+const (
+	// StatusPending indicates the job is queued but not yet claimed by a processor
+	StatusPending TranscriptionStatus = "PENDING"
+
+	// StatusClaimed indicates a processor has taken the job
+	StatusClaimed TranscriptionStatus = "CLAIMED"
+
+	// StatusSubmitted indicates the audio was handed to an asynchronous provider that hasn't started on it
+	StatusSubmitted TranscriptionStatus = "SUBMITTED"
+
+	// StatusTranscribing indicates the provider is transcribing the audio
+	StatusTranscribing TranscriptionStatus = "TRANSCRIBING"
+
+	// StatusPostprocessing indicates the transcript is being stored and post-processed
+	StatusPostprocessing TranscriptionStatus = "POSTPROCESSING"
+
+	// StatusCompleted indicates the transcription was successfully completed
+	StatusCompleted TranscriptionStatus = "COMPLETED"
+
+	// StatusFailed indicates the transcription encountered an error
+	StatusFailed TranscriptionStatus = "FAILED"
+
+	// StatusCancelled indicates the transcription was cancelled by an operator
+	StatusCancelled TranscriptionStatus = "CANCELLED"
+
+	// StatusRejected indicates the input was refused before transcription and won't be retried automatically
+	StatusRejected TranscriptionStatus = "REJECTED"
+
+	// StatusInProgress is the single processing state used before CLAIMED, SUBMITTED, TRANSCRIBING and
+	// POSTPROCESSING existed. It is never written; items still in it can only finish or be cancelled.
+	StatusInProgress TranscriptionStatus = "IN_PROGRESS"
+)
+
+// ErrInvalidTransition is returned when a status change isn't allowed by the job state machine
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// transitions lists the statuses each status may move to. The empty status is a job that doesn't exist yet.
+var transitions = map[TranscriptionStatus][]TranscriptionStatus{
+	"":                   {StatusPending, StatusClaimed, StatusRejected},
+	StatusPending:        {StatusClaimed, StatusCancelled, StatusRejected},
+	StatusClaimed:        {StatusSubmitted, StatusTranscribing, StatusFailed, StatusCancelled, StatusRejected},
+	StatusSubmitted:      {StatusTranscribing, StatusFailed, StatusCancelled},
+	StatusTranscribing:   {StatusPostprocessing, StatusFailed, StatusCancelled},
+	StatusPostprocessing: {StatusCompleted, StatusFailed, StatusCancelled},
+	StatusCompleted:      {StatusPending},
+	StatusFailed:         {StatusPending, StatusClaimed},
+	StatusCancelled:      {StatusPending},
+	StatusRejected:       {StatusPending},
+	StatusInProgress:     {StatusCompleted, StatusFailed, StatusCancelled},
+}
+
+// Statuses returns every known status, including the legacy IN_PROGRESS
+func Statuses() []TranscriptionStatus {
+	return []TranscriptionStatus{
+		StatusPending,
+		StatusClaimed,
+		StatusSubmitted,
+		StatusTranscribing,
+		StatusPostprocessing,
+		StatusCompleted,
+		StatusFailed,
+		StatusCancelled,
+		StatusRejected,
+		StatusInProgress,
+	}
+}
+
+// Valid reports whether s is a known status
+func (s TranscriptionStatus) Valid() bool {
+	_, ok := transitions[s]
+	return ok && s != ""
+}
+
+// Terminal reports whether the job has finished, successfully or not. Terminal jobs only leave
+// their status when an operator requeues them.
+func (s TranscriptionStatus) Terminal() bool {
+	switch s {
+	case StatusCompleted, StatusFailed, StatusCancelled, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// Active reports whether a processor is working on the job
+func (s TranscriptionStatus) Active() bool {
+	switch s {
+	case StatusClaimed, StatusSubmitted, StatusTranscribing, StatusPostprocessing, StatusInProgress:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a job may move from one status to another
+func CanTransition(from, to TranscriptionStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error wrapping ErrInvalidTransition if the move isn't allowed
+func ValidateTransition(from, to TranscriptionStatus) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	if from == "" {
+		return fmt.Errorf("%w: new job cannot start in %s", ErrInvalidTransition, to)
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+// AllowedFrom returns the existing statuses a job may move to the given status from, in the order of
+// Statuses. The empty (new job) status is not included.
+func AllowedFrom(to TranscriptionStatus) []TranscriptionStatus {
+	var from []TranscriptionStatus
+	for _, status := range Statuses() {
+		if CanTransition(status, to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
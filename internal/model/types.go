@@ -1,22 +1,12 @@
 package model
 
-import "time"
-
-// TranscriptionStatus indicates the current status of a transcription job
-type TranscriptionStatus string
-
-const (
-	// StatusPending indicates the transcription is queued but not processed
-	StatusPending TranscriptionStatus = "PENDING"
-	
-	// StatusInProgress indicates the transcription is currently being processed
-	StatusInProgress TranscriptionStatus = "IN_PROGRESS"
-	
-	// StatusCompleted indicates the transcription was successfully completed
-	StatusCompleted TranscriptionStatus = "COMPLETED"
-	
-	// StatusFailed indicates the transcription encountered an error
-	StatusFailed TranscriptionStatus = "FAILED"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // TranscriptionItem represents an item in the DynamoDB table
@@ -24,6 +14,9 @@ type TranscriptionItem struct {
 	// FileIdentifier is the unique identifier (usually the S3 key)
 	FileIdentifier string `json:"fileIdentifier" dynamodbav:"FileIdentifier"`
 	
+	// TenantID is the tenant owning the source audio, when the deployment serves several
+	TenantID string `json:"tenantId,omitempty" dynamodbav:"TenantID,omitempty"`
+	
 	// Status is the current status of the transcription
 	Status TranscriptionStatus `json:"status" dynamodbav:"Status"`
 	
@@ -33,12 +26,31 @@ type TranscriptionItem struct {
 	// SourceKey is the S3 key of the source audio file
 	SourceKey string `json:"sourceKey" dynamodbav:"SourceKey"`
 	
-	// TranscriptText contains the transcribed text (if completed)
+	// TranscriptText contains the transcribed text (if completed and small enough to keep inline)
 	TranscriptText string `json:"transcriptText,omitempty" dynamodbav:"TranscriptText,omitempty"`
 	
+	// EncryptedTranscript holds the transcript text when envelope encryption is enabled. GetTranscriptionItem
+	// decrypts it into TranscriptText when it has a key service.
+	EncryptedTranscript *Envelope `json:"-" dynamodbav:"EncryptedTranscript,omitempty"`
+	
+	// TranscriptRef points to the full text in S3 when it was too large to keep inline
+	TranscriptRef *TranscriptRef `json:"transcriptRef,omitempty" dynamodbav:"TranscriptRef,omitempty"`
+	
 	// OutputLocation contains the S3 URL to the transcript (if stored separately)
 	OutputLocation string `json:"outputLocation,omitempty" dynamodbav:"OutputLocation,omitempty"`
 	
+	// Redactions counts the personal data masked in the transcript, by pattern name
+	Redactions map[string]int `json:"redactions,omitempty" dynamodbav:"Redactions,omitempty"`
+	
+	// UnredactedLocation is the S3 URL of the KMS-encrypted original transcript, kept when anything was redacted
+	UnredactedLocation string `json:"unredactedLocation,omitempty" dynamodbav:"UnredactedLocation,omitempty"`
+	
+	// Vocabulary is the S3 URL of the custom vocabulary applied to the transcript
+	Vocabulary string `json:"vocabulary,omitempty" dynamodbav:"Vocabulary,omitempty"`
+	
+	// VocabularyVersion is the version of that vocabulary file
+	VocabularyVersion string `json:"vocabularyVersion,omitempty" dynamodbav:"VocabularyVersion,omitempty"`
+	
 	// ErrorMessage contains error details if the transcription failed
 	ErrorMessage string `json:"errorMessage,omitempty" dynamodbav:"ErrorMessage,omitempty"`
 	
@@ -48,19 +60,680 @@ type TranscriptionItem struct {
 	// UpdatedAt is when the record was last updated
 	UpdatedAt time.Time `json:"updatedAt" dynamodbav:"UpdatedAt"`
 	
+	// Version is incremented on every update, for optimistic locking; items written before it existed have version 0
+	Version int64 `json:"version,omitempty" dynamodbav:"Version,omitempty"`
+	
 	// ProcessingTime is how long the transcription took in seconds
 	ProcessingTime float64 `json:"processingTime,omitempty" dynamodbav:"ProcessingTime,omitempty"`
+	
+	// BatchID links the item to the manifest batch that submitted it (if any)
+	BatchID string `json:"batchId,omitempty" dynamodbav:"BatchID,omitempty"`
+
+	// BatchTenantID is the tenant whose partition holds the batch record; empty for a batch submitted
+	// outside any tenant's prefix
+	BatchTenantID string `json:"-" dynamodbav:"BatchTenantID,omitempty"`
+
+	// WaitingBatches are other batches that listed the file while the job was running; they're told its
+	// outcome as well when it finishes
+	WaitingBatches []BatchRef `json:"-" dynamodbav:"WaitingBatches,omitempty"`
+
+	// Metadata contains caller-supplied key/value pairs from the job manifest
+	Metadata map[string]string `json:"metadata,omitempty" dynamodbav:"Metadata,omitempty"`
+	
+	// LanguageHint is the requested language code, or "auto" for detection
+	LanguageHint string `json:"languageHint,omitempty" dynamodbav:"LanguageHint,omitempty"`
+	
+	// DetectedLanguage is the language code reported by the provider
+	DetectedLanguage string `json:"detectedLanguage,omitempty" dynamodbav:"DetectedLanguage,omitempty"`
+	
+	// LanguageProbability is the provider's confidence in DetectedLanguage (0-1)
+	LanguageProbability float64 `json:"languageProbability,omitempty" dynamodbav:"LanguageProbability,omitempty"`
+	
+	// Provider is the transcription provider that produced the transcript
+	Provider string `json:"provider,omitempty" dynamodbav:"Provider,omitempty"`
+	
+	// ModelID is the provider model that produced the transcript
+	ModelID string `json:"modelId,omitempty" dynamodbav:"ModelID,omitempty"`
+	
+	// History is the append-only list of processing attempts and operator actions
+	History []AttemptRecord `json:"history,omitempty" dynamodbav:"History,omitempty"`
+	
+	// Notifications records delivery of the latest completion event to each sink
+	Notifications []NotificationDelivery `json:"notifications,omitempty" dynamodbav:"Notifications,omitempty"`
+	
+	// ExpiresAt is the DynamoDB TTL (Unix seconds) set by the job's retention policy, if any
+	ExpiresAt int64 `json:"expiresAt,omitempty" dynamodbav:"ExpiresAt,omitempty"`
+	
+	// DeferredUntil is when a PENDING job held back by its tenant's quotas may be dispatched again
+	DeferredUntil *time.Time `json:"deferredUntil,omitempty" dynamodbav:"DeferredUntil,omitempty"`
+	
+	// DeferredReason is the quota that held the job back
+	DeferredReason string `json:"deferredReason,omitempty" dynamodbav:"DeferredReason,omitempty"`
+	
+	// DeferredOptions are the job options the deferred job is dispatched with
+	DeferredOptions *JobOptions `json:"-" dynamodbav:"DeferredOptions,omitempty"`
+	
+	// ConfigVersion is the version of the runtime settings the latest attempt ran with
+	ConfigVersion string `json:"configVersion,omitempty" dynamodbav:"ConfigVersion,omitempty"`
+}
+
+// JobEventVersion is the schema version of published job events. Additive changes keep
+// the version; removing or changing fields bumps it.
+const JobEventVersion = "1"
+
+// Job event types
+const (
+	// EventTypeCompleted is published when a transcription completes
+	EventTypeCompleted = "transcription.completed"
+	
+	// EventTypeFailed is published when a transcription attempt fails
+	EventTypeFailed = "transcription.failed"
+)
+
+// JobEvent is published to notification sinks when a job reaches COMPLETED or FAILED
+type JobEvent struct {
+	// Version is the event schema version (JobEventVersion)
+	Version string `json:"version"`
+	
+	// ID uniquely identifies the event so receivers can de-duplicate redeliveries
+	ID string `json:"id"`
+	
+	// Type is EventTypeCompleted or EventTypeFailed
+	Type string `json:"type"`
+	
+	// Time is when the event was published
+	Time time.Time `json:"time"`
+	
+	// FileIdentifier is the job's file identifier
+	FileIdentifier string `json:"fileIdentifier"`
+	
+	// Status is the job's final status
+	Status TranscriptionStatus `json:"status"`
+	
+	// SourceBucket is the S3 bucket containing the source audio file
+	SourceBucket string `json:"sourceBucket"`
+	
+	// SourceKey is the S3 key of the source audio file
+	SourceKey string `json:"sourceKey"`
+	
+	// BatchID is the manifest batch that submitted the job (if any)
+	BatchID string `json:"batchId,omitempty"`
+	
+	// OutputLocation is the S3 URL of the plain-text transcript
+	OutputLocation string `json:"outputLocation,omitempty"`
+	
+	// StructuredOutputLocation is the S3 URL of the transcript with word timings
+	StructuredOutputLocation string `json:"structuredOutputLocation,omitempty"`
+	
+	// DurationSeconds is how long the attempt took
+	DurationSeconds float64 `json:"durationSeconds"`
+	
+	// Error describes why the job failed
+	Error string `json:"error,omitempty"`
+	
+	// Metadata contains caller-supplied key/value pairs from the job manifest
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// DeliveryStatus is the outcome of publishing an event to one sink
+type DeliveryStatus string
+
+const (
+	// DeliveryDelivered indicates the sink accepted the event
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	
+	// DeliveryFailed indicates the sink rejected the event or every attempt failed
+	DeliveryFailed DeliveryStatus = "FAILED"
+)
+
+// NotificationDelivery records the delivery of an event to one sink
+type NotificationDelivery struct {
+	// Sink names the destination, e.g. "sns:<topic arn>" or "webhook:<url>"
+	Sink string `json:"sink" dynamodbav:"Sink"`
+	
+	// EventID is the delivered event's ID
+	EventID string `json:"eventId" dynamodbav:"EventID"`
+	
+	// Status is the delivery outcome
+	Status DeliveryStatus `json:"status" dynamodbav:"Status"`
+	
+	// Attempts is how many times delivery was tried
+	Attempts int `json:"attempts" dynamodbav:"Attempts"`
+	
+	// Error describes the last failure
+	Error string `json:"error,omitempty" dynamodbav:"Error,omitempty"`
+	
+	// At is when delivery finished
+	At time.Time `json:"at" dynamodbav:"At"`
+}
+
+// Attempts returns how many processing attempts the item's history records, not counting operator actions
+func (i *TranscriptionItem) Attempts() int {
+	n := 0
+	for _, record := range i.History {
+		if record.Note == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Batches returns the batches the job's outcome is counted for when it finishes
+func (i *TranscriptionItem) Batches() []BatchRef {
+	batches := i.WaitingBatches
+	if i.BatchID != "" {
+		batches = append([]BatchRef{{TenantID: i.BatchTenantID, BatchID: i.BatchID}}, batches...)
+	}
+	return batches
+}
+
+// ReportsTo reports whether the job's outcome is counted for the batch when it finishes
+func (i *TranscriptionItem) ReportsTo(batch BatchRef) bool {
+	for _, ref := range i.Batches() {
+		if ref == batch {
+			return true
+		}
+	}
+	return false
+}
+
+// AttemptRecord is one entry in a transcription item's history
+type AttemptRecord struct {
+	// Status is the outcome of the attempt, or the status set by an operator action
+	Status TranscriptionStatus `json:"status" dynamodbav:"Status"`
+	
+	// StartedAt is when the attempt or action began
+	StartedAt time.Time `json:"startedAt" dynamodbav:"StartedAt"`
+	
+	// FinishedAt is when the attempt or action ended
+	FinishedAt time.Time `json:"finishedAt" dynamodbav:"FinishedAt"`
+	
+	// DurationSeconds is the attempt's wall-clock duration
+	DurationSeconds float64 `json:"durationSeconds,omitempty" dynamodbav:"DurationSeconds,omitempty"`
+	
+	// Provider is the transcription provider used by the attempt
+	Provider string `json:"provider,omitempty" dynamodbav:"Provider,omitempty"`
+	
+	// ModelID is the provider model used by the attempt
+	ModelID string `json:"modelId,omitempty" dynamodbav:"ModelID,omitempty"`
+	
+	// Error describes why the attempt failed
+	Error string `json:"error,omitempty" dynamodbav:"Error,omitempty"`
+	
+	// Note describes operator actions such as cancel or reprocess requests
+	Note string `json:"note,omitempty" dynamodbav:"Note,omitempty"`
+	
+	// Stages records each post-processing stage the attempt ran
+	Stages []StageRun `json:"stages,omitempty" dynamodbav:"Stages,omitempty"`
+}
+
+// StageRun is the outcome of one post-processing stage
+type StageRun struct {
+	// Name is the configured stage name
+	Name string `json:"name" dynamodbav:"Name"`
+	
+	// DurationSeconds is how long the stage ran
+	DurationSeconds float64 `json:"durationSeconds" dynamodbav:"DurationSeconds"`
+	
+	// Skipped is true when the stage failed and its changes were discarded
+	Skipped bool `json:"skipped,omitempty" dynamodbav:"Skipped,omitempty"`
+	
+	// Error describes why the stage failed
+	Error string `json:"error,omitempty" dynamodbav:"Error,omitempty"`
+}
+
+// TranscriptionQuery filters transcription items through a secondary index.
+// Status queries range over UpdatedAt; source bucket queries range over CreatedAt.
+type TranscriptionQuery struct {
+	// Status restricts results to one status
+	Status TranscriptionStatus
+	
+	// SourceBucket restricts results to one input bucket
+	SourceBucket string
+	
+	// TenantID restricts results to one tenant's jobs
+	TenantID string
+	
+	// Since is the inclusive lower time bound (zero means unbounded)
+	Since time.Time
+	
+	// Until is the inclusive upper time bound (zero means unbounded)
+	Until time.Time
+	
+	// Limit is the maximum number of items per page (zero means the service default)
+	Limit int32
+
+	// OldestFirst returns the least recently updated items first instead of the newest
+	OldestFirst bool
+	
+	// Cursor continues a previous query from its NextCursor
+	Cursor string
+}
+
+// ParseTimeBound parses a query time bound given as a duration before now (24h),
+// a date (2006-01-02) or an RFC3339 timestamp. An empty value means unbounded.
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	
+	return time.Parse(time.RFC3339, value)
+}
+
+// TranscriptionPage is one page of query results
+type TranscriptionPage struct {
+	// Items are the matching transcription items
+	Items []TranscriptionItem `json:"items"`
+	
+	// NextCursor is set when more results are available
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// JobOptions holds per-file options supplied by a manifest or API submission
+type JobOptions struct {
+	// BatchID is the manifest batch this job belongs to
+	BatchID string `json:"-"`
+
+	// BatchTenantID is the tenant whose partition holds the batch, if any
+	BatchTenantID string `json:"-"`
+	
+	// OutputBucket overrides the configured output bucket for this file
+	OutputBucket string `json:"outputBucket,omitempty"`
+	
+	// Language is an ISO-639 language code hint, or LanguageAuto to let the provider detect it
+	Language string `json:"language,omitempty"`
+	
+	// Provider forces a registered transcription provider instead of language-based routing
+	Provider string `json:"provider,omitempty"`
+	
+	// ModelID forces a provider model; used together with Provider
+	ModelID string `json:"modelId,omitempty"`
+	
+	// Metadata is stored as-is on the transcription item
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ManifestEntry describes a single audio object listed in a job manifest
+type ManifestEntry struct {
+	// Bucket is the S3 bucket of the audio object (defaults to the manifest's bucket)
+	Bucket string `json:"bucket,omitempty"`
+	
+	// Key is the S3 key of the audio object
+	Key string `json:"key"`
+	
+	// Options are the per-file job options
+	Options JobOptions `json:"options,omitempty"`
+}
+
+// JobManifest is a sidecar JSON file submitting several audio objects as one batch
+type JobManifest struct {
+	// BatchID identifies the batch; required and unique per manifest, except for a dispatch manifest
+	// handing over a job that isn't part of a batch
+	BatchID string `json:"batchId,omitempty"`
+
+	// TenantID is the tenant a manifest outside the tenants' prefixes submits for. A manifest in a
+	// tenant's prefix always submits for that tenant.
+	TenantID string `json:"tenantId,omitempty"`
+	
+	// Callback is an optional URL that receives a POST when the batch finishes
+	Callback string `json:"callback,omitempty"`
+	
+	// Files lists the audio objects to transcribe
+	Files []ManifestEntry `json:"files"`
+}
+
+// BatchStatus indicates the aggregate state of a manifest batch
+type BatchStatus string
+
+const (
+	// BatchStatusInProgress indicates some files in the batch are still being processed
+	BatchStatusInProgress BatchStatus = "IN_PROGRESS"
+	
+	// BatchStatusCompleted indicates every file in the batch completed successfully
+	BatchStatusCompleted BatchStatus = "COMPLETED"
+	
+	// BatchStatusCompletedWithErrors indicates the batch finished but some files failed
+	BatchStatusCompletedWithErrors BatchStatus = "COMPLETED_WITH_ERRORS"
+)
+
+// BatchItemPrefix prefixes the FileIdentifier of batch records stored in the transcription table
+const BatchItemPrefix = "batch#"
+
+// BatchItem tracks progress of a manifest batch in DynamoDB.
+// Batch records use BatchStatus rather than Status so they stay out of per-file status queries.
+type BatchItem struct {
+	// FileIdentifier is the BatchKey of the tenant and batch ID
+	FileIdentifier string `json:"-" dynamodbav:"FileIdentifier"`
+	
+	// BatchID is the caller-supplied batch identifier
+	BatchID string `json:"batchId" dynamodbav:"BatchID"`
+
+	// TenantID is the tenant that submitted the manifest, if any
+	TenantID string `json:"tenantId,omitempty" dynamodbav:"TenantID,omitempty"`
+	
+	// BatchStatus is the aggregate batch state
+	BatchStatus BatchStatus `json:"status" dynamodbav:"BatchStatus"`
+	
+	// ManifestLocation is the S3 URL of the manifest file
+	ManifestLocation string `json:"manifestLocation" dynamodbav:"ManifestLocation"`
+	
+	// Callback is the URL notified when the batch finishes
+	Callback string `json:"callback,omitempty" dynamodbav:"Callback,omitempty"`
+	
+	// TotalFiles is the number of files listed in the manifest
+	TotalFiles int `json:"totalFiles" dynamodbav:"TotalFiles"`
+	
+	// CompletedFiles is the number of files that finished successfully
+	CompletedFiles int `json:"completedFiles" dynamodbav:"CompletedFiles"`
+	
+	// FailedFiles is the number of files that failed
+	FailedFiles int `json:"failedFiles" dynamodbav:"FailedFiles"`
+	
+	// CountedFiles are the jobs already counted, so a file reported twice is counted once
+	CountedFiles []string `json:"-" dynamodbav:"CountedFiles,stringset,omitempty"`
+
+	// ExpiresAt is the DynamoDB TTL (Unix seconds) of a batch created from a dispatch manifest
+	ExpiresAt int64 `json:"-" dynamodbav:"ExpiresAt,omitempty"`
+
+	// CreatedAt is when the batch was first seen
+	CreatedAt time.Time `json:"createdAt" dynamodbav:"CreatedAt"`
+	
+	// UpdatedAt is when the batch was last updated
+	UpdatedAt time.Time `json:"updatedAt" dynamodbav:"UpdatedAt"`
+}
+
+// BatchRef names a batch record: a batch ID in the partition of the tenant that submitted it
+type BatchRef struct {
+	TenantID string `dynamodbav:"TenantID,omitempty"`
+	BatchID  string `dynamodbav:"BatchID"`
+}
+
+// BatchKey returns the DynamoDB FileIdentifier for a batch ID. A tenant's batches are in its partition,
+// so tenants can use the same batch IDs.
+func BatchKey(tenantID, batchID string) string {
+	if tenantID == "" {
+		return BatchItemPrefix + batchID
+	}
+	return TenantJobID(tenantID, BatchItemPrefix+batchID)
+}
+
+// Done reports whether every file in the batch has finished
+func (b *BatchItem) Done() bool {
+	return b.CompletedFiles+b.FailedFiles >= b.TotalFiles
+}
+
+// OutboxItemPrefix prefixes the FileIdentifier of outbox records stored in the transcription table
+const OutboxItemPrefix = "outbox#"
+
+// OutboxItem is a job event waiting for delivery. It is written in the same transaction as the
+// status change that produced it and delivered from the table's stream.
+type OutboxItem struct {
+	// FileIdentifier is OutboxItemPrefix followed by the event ID
+	FileIdentifier string `json:"-" dynamodbav:"FileIdentifier"`
+	
+	// EventID is the event's ID, sent to consumers as the de-duplication ID
+	EventID string `json:"eventId" dynamodbav:"EventID"`
+	
+	// JobID is the FileIdentifier of the job the event is about
+	JobID string `json:"jobId" dynamodbav:"JobID"`
+	
+	// EventType is the event's type
+	EventType string `json:"eventType" dynamodbav:"EventType"`
+	
+	// Payload is the JSON-encoded JobEvent
+	Payload string `json:"payload" dynamodbav:"Payload"`
+	
+	// TraceContext carries the W3C trace context of the attempt that recorded the event, so
+	// deliveries continue its trace
+	TraceContext map[string]string `json:"traceContext,omitempty" dynamodbav:"TraceContext,omitempty"`
+	
+	// Deliveries holds the latest delivery per sink name
+	Deliveries map[string]NotificationDelivery `json:"deliveries" dynamodbav:"Deliveries"`
+	
+	// Claims holds the Unix time at which a dispatcher claimed delivery to a sink
+	Claims map[string]int64 `json:"claims" dynamodbav:"Claims"`
+	
+	// CreatedAt is when the event was recorded
+	CreatedAt time.Time `json:"createdAt" dynamodbav:"CreatedAt"`
+	
+	// ExpiresAt is the Unix time after which DynamoDB TTL removes the record
+	ExpiresAt int64 `json:"expiresAt" dynamodbav:"ExpiresAt"`
+}
+
+// OutboxKey returns the DynamoDB FileIdentifier for an event ID
+func OutboxKey(eventID string) string {
+	return OutboxItemPrefix + eventID
+}
+
+// LanguageAuto requests automatic language detection
+const LanguageAuto = "auto"
+
+// TranscribeOptions tunes a single provider transcription call
+type TranscribeOptions struct {
+	// LanguageCode is the expected language; empty lets the provider detect it
+	LanguageCode string
+	
+	// ModelID selects a provider model; empty uses the provider default
+	ModelID string
+	
+	// Keywords are terms to boost; only set for providers that support keyword boosting
+	Keywords []string
+}
+
+// ProviderElevenLabs is the transcription provider every deployment registers
+const ProviderElevenLabs = "elevenlabs"
+
+// Providers are the transcription providers a deployment registers. Language routes and the runtime
+// settings' default route can only name these, since jobs sent to any other provider always fail.
+var Providers = []string{ProviderElevenLabs}
+
+// IsProvider reports whether name is one of Providers
+func IsProvider(name string) bool {
+	for _, provider := range Providers {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LanguageRoute names the provider and model used for a language the default model doesn't support
+type LanguageRoute struct {
+	// Provider is the registered provider name (empty means the default provider)
+	Provider string
+	
+	// ModelID is the provider model to request (empty means the provider default)
+	ModelID string
 }
 
 // ElevenLabsRequest represents a request to the ElevenLabs API
 type ElevenLabsRequest struct {
-	AudioURL string `json:"audio_url"`
+	AudioURL     string `json:"audio_url"`
+	LanguageCode string `json:"language_code,omitempty"`
+	ModelID      string `json:"model_id,omitempty"`
+	Keyterms     []string `json:"keyterms,omitempty"`
 }
 
 // ElevenLabsResponse represents a response from the ElevenLabs API
 type ElevenLabsResponse struct {
-	ID      string `json:"id"`
-	Text    string `json:"text"`
-	Error   string `json:"error,omitempty"`
-	Success bool   `json:"success"`
-}
\ No newline at end of file
+	ID                  string  `json:"id"`
+	Text                string  `json:"text"`
+	LanguageCode        string  `json:"language_code,omitempty"`
+	LanguageProbability float64 `json:"language_probability,omitempty"`
+	Words               []Word  `json:"words,omitempty"`
+	AudioDuration       float64 `json:"audio_duration_secs,omitempty"`
+	Error               string  `json:"error,omitempty"`
+	Success             bool    `json:"success"`
+}
+
+// Word is a timed token in a transcript
+type Word struct {
+	// Text is the token text (a word, whitespace, or an audio event description)
+	Text string `json:"text"`
+	
+	// Start is the offset in seconds where the token begins
+	Start float64 `json:"start"`
+	
+	// End is the offset in seconds where the token ends
+	End float64 `json:"end"`
+	
+	// Type is "word", "spacing" or "audio_event"
+	Type string `json:"type,omitempty"`
+}
+
+// Transcript is the structured transcript stored next to the plain text output
+type Transcript struct {
+	FileIdentifier      string  `json:"fileIdentifier"`
+	Text                string  `json:"text"`
+	LanguageCode        string  `json:"languageCode,omitempty"`
+	LanguageProbability float64 `json:"languageProbability,omitempty"`
+	Words               []Word  `json:"words,omitempty"`
+}
+
+// audioExtensions are the file extensions accepted for transcription
+var audioExtensions = map[string]bool{
+	".aac":  true,
+	".mp3":  true,
+	".wav":  true,
+	".flac": true,
+	".ogg":  true,
+	".m4a":  true,
+}
+
+// IsAudioFile checks if the key has a supported audio extension
+func IsAudioFile(key string) bool {
+	return audioExtensions[strings.ToLower(filepath.Ext(key))]
+}
+
+// manifestSuffix identifies sidecar job manifest files
+const manifestSuffix = ".manifest.json"
+
+// IsManifestFile checks if the key is a sidecar job manifest
+func IsManifestFile(key string) bool {
+	return strings.HasSuffix(strings.ToLower(key), manifestSuffix)
+}
+
+// DispatchManifestPrefix is where the Jobs API, the scheduler and batch manifests write the one-file
+// manifests that hand single jobs to the transcriber. They're only read once, so a lifecycle rule on the prefix expires them
+// after DispatchRetention.
+const DispatchManifestPrefix = "jobs/dispatch/"
+
+// DispatchRetention is how long dispatch manifests, and the one-file batch records created from them,
+// are kept
+const DispatchRetention = 30 * 24 * time.Hour
+
+// DispatchManifestKey returns the key of a dispatch manifest
+func DispatchManifestKey(name string) string {
+	return DispatchManifestPrefix + name + manifestSuffix
+}
+
+// IsDispatchManifest checks if the key is a dispatch manifest
+func IsDispatchManifest(key string) bool {
+	return strings.HasPrefix(key, DispatchManifestPrefix) && IsManifestFile(key)
+}
+
+// OutputKey returns the key under prefix of a job's text output. It is the job's full input key,
+// extension included, under the tenant's folder for a tenant's job, so no two jobs share an output:
+// calls/a.mp3 and calls/a.wav are written to calls/a.mp3.txt and calls/a.wav.txt.
+func OutputKey(prefix, fileIdentifier string) string {
+	tenantID, key, tenanted := SplitTenantJobID(fileIdentifier)
+	if tenanted {
+		key = tenantID + "/" + key
+	}
+	return prefix + key + ".txt"
+}
+
+// StructuredOutputKey returns the key of the structured JSON transcript stored next to a .txt output key
+func StructuredOutputKey(textKey string) string {
+	return strings.TrimSuffix(textKey, ".txt") + ".json"
+}
+
+// DefaultInlineTranscriptLimit is the largest transcript, in bytes, kept inline on the DynamoDB item.
+// It leaves room under the 400KB item limit for history and the other attributes.
+const DefaultInlineTranscriptLimit = 100 * 1024
+
+// TranscriptPreviewLength is the number of characters of an external transcript kept on the item
+const TranscriptPreviewLength = 500
+
+// TranscriptArtifactPrefix prefixes the keys of transcripts stored outside the item when no output bucket is configured
+const TranscriptArtifactPrefix = "artifacts/transcripts/"
+
+// TranscriptRef points to a transcript stored in S3 instead of on the item
+type TranscriptRef struct {
+	// Location is the s3:// URI of the full text
+	Location string `json:"location" dynamodbav:"Location"`
+	
+	// SHA256 is the hex SHA-256 of the full text, checked when it is loaded
+	SHA256 string `json:"sha256" dynamodbav:"SHA256"`
+	
+	// Size is the length of the full text in bytes
+	Size int `json:"size" dynamodbav:"Size"`
+	
+	// Preview is the beginning of the text, for listings that don't need all of it
+	Preview string `json:"preview,omitempty" dynamodbav:"Preview,omitempty"`
+}
+
+// NewTranscriptRef describes text stored at location
+func NewTranscriptRef(location, text string) *TranscriptRef {
+	return &TranscriptRef{
+		Location: location,
+		SHA256:   transcriptHash(text),
+		Size:     len(text),
+		Preview:  preview(text, TranscriptPreviewLength),
+	}
+}
+
+// Verify checks that text is the transcript the reference was made for
+func (r *TranscriptRef) Verify(text string) error {
+	if len(text) != r.Size || transcriptHash(text) != r.SHA256 {
+		return fmt.Errorf("transcript at %s does not match its recorded hash", r.Location)
+	}
+	return nil
+}
+
+// TranscriptArtifactKey returns the key of a job's externally stored transcript, laid out like its
+// output key
+func TranscriptArtifactKey(fileIdentifier string) string {
+	return OutputKey(TranscriptArtifactPrefix, fileIdentifier)
+}
+
+func transcriptHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// preview returns the first n characters of text
+func preview(text string, n int) string {
+	i := 0
+	for pos := range text {
+		if i == n {
+			return text[:pos]
+		}
+		i++
+	}
+	return text
+}
+
+// TenantItemPrefix prefixes the FileIdentifier of jobs that belong to a tenant
+const TenantItemPrefix = "tenant#"
+
+// TenantJobID returns the FileIdentifier of a tenant's job for an input key
+func TenantJobID(tenantID, key string) string {
+	return TenantItemPrefix + tenantID + "#" + key
+}
+
+// SplitTenantJobID returns the tenant and input key of a tenant-partitioned FileIdentifier
+func SplitTenantJobID(fileIdentifier string) (tenantID, key string, ok bool) {
+	if !strings.HasPrefix(fileIdentifier, TenantItemPrefix) {
+		return "", fileIdentifier, false
+	}
+	return strings.Cut(strings.TrimPrefix(fileIdentifier, TenantItemPrefix), "#")
+}
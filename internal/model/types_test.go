@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputKey(t *testing.T) {
+	// Inputs differing only in their extension get their own outputs
+	assert.Equal(t, "transcripts/calls/a.mp3.txt", OutputKey("transcripts/", "calls/a.mp3"))
+	assert.Equal(t, "transcripts/calls/a.wav.txt", OutputKey("transcripts/", "calls/a.wav"))
+	assert.Equal(t, "transcripts/calls/a.mp3.json", StructuredOutputKey(OutputKey("transcripts/", "calls/a.mp3")))
+
+	// A tenant's outputs are under its folder
+	assert.Equal(t, "transcripts/support/calls/a.mp3.txt", OutputKey("transcripts/", TenantJobID("support", "calls/a.mp3")))
+}
+
+func TestTranscriptArtifactKey(t *testing.T) {
+	assert.Equal(t, "artifacts/transcripts/calls/a.mp3.txt", TranscriptArtifactKey("calls/a.mp3"))
+	assert.Equal(t, "artifacts/transcripts/support/calls/a.mp3.txt", TranscriptArtifactKey(TenantJobID("support", "calls/a.mp3")))
+}
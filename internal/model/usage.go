@@ -0,0 +1,112 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// UsageEntryPrefix prefixes the FileIdentifier of per-job usage entries stored in the transcription table
+const UsageEntryPrefix = "usage#"
+
+// UsageDailyPrefix prefixes the FileIdentifier of daily usage totals stored in the transcription table
+const UsageDailyPrefix = "usagedaily#"
+
+// UsageDayFormat is the layout of usage days, which are UTC calendar dates
+const UsageDayFormat = "2006-01-02"
+
+// UsageEntry records what one provider call of a job consumed
+type UsageEntry struct {
+	// FileIdentifier is UsageEntryPrefix followed by the job hash, attempt number and call number
+	FileIdentifier string `json:"-" dynamodbav:"FileIdentifier"`
+
+	// JobHash is the JobHash of the completed job. The ledger outlives purged jobs, so it never holds
+	// the job's input key.
+	JobHash string `json:"jobHash" dynamodbav:"JobHash"`
+
+	// Attempt is the attempt number that made the call
+	Attempt int `json:"attempt" dynamodbav:"Attempt"`
+
+	// Call numbers the attempt's provider calls from 1. A job re-routed after language detection makes
+	// two, and the provider bills both.
+	Call int `json:"call" dynamodbav:"Call"`
+
+	// Replaced is set on a call whose transcript was discarded for a later call's. It is billed but not
+	// counted as a job.
+	Replaced bool `json:"replaced,omitempty" dynamodbav:"Replaced,omitempty"`
+
+	// TenantID is the tenant owning the job, if the deployment serves several
+	TenantID string `json:"tenantId,omitempty" dynamodbav:"TenantID,omitempty"`
+
+	// Prefix is the first path segment of the source key, or "/" for keys at the top of the bucket
+	Prefix string `json:"prefix" dynamodbav:"Prefix"`
+
+	// Provider and ModelID identify what produced the transcript
+	Provider string `json:"provider" dynamodbav:"Provider"`
+	ModelID  string `json:"modelId,omitempty" dynamodbav:"ModelID,omitempty"`
+
+	// AudioSeconds is the length of the audio sent to the provider
+	AudioSeconds float64 `json:"audioSeconds" dynamodbav:"AudioSeconds"`
+
+	// Characters is the length of the call's transcript, after post-processing for the final call
+	Characters int `json:"characters" dynamodbav:"Characters"`
+
+	// EstimatedCost is the cost in USD according to the configured price table
+	EstimatedCost float64 `json:"estimatedCost" dynamodbav:"EstimatedCost"`
+
+	// Day is the UTC date the call was recorded
+	Day string `json:"day" dynamodbav:"Day"`
+
+	// CompletedAt is when the call was recorded
+	CompletedAt time.Time `json:"completedAt" dynamodbav:"CompletedAt"`
+}
+
+// UsageKey returns the DynamoDB FileIdentifier of the usage entry of a job attempt's provider call
+func UsageKey(jobHash string, attempt, call int) string {
+	return fmt.Sprintf("%s%s#%d#%d", UsageEntryPrefix, jobHash, attempt, call)
+}
+
+// Jobs is how many jobs the entry adds to its daily total: one, unless its transcript was replaced
+func (e *UsageEntry) Jobs() int {
+	if e.Replaced {
+		return 0
+	}
+	return 1
+}
+
+// JobHash returns the hex SHA-256 of a job's FileIdentifier, which names the job in records kept after
+// it is purged without revealing its input key
+func JobHash(fileIdentifier string) string {
+	sum := sha256.Sum256([]byte(fileIdentifier))
+	return hex.EncodeToString(sum[:])
+}
+
+// UsageDaily is the running total of usage for one day, tenant, prefix, provider and model. Totals
+// are added to atomically in the same transaction that writes each UsageEntry.
+type UsageDaily struct {
+	// FileIdentifier is UsageDailyPrefix followed by the day, tenant, prefix, provider and model
+	FileIdentifier string `json:"-" dynamodbav:"FileIdentifier"`
+
+	// UsageDay is the UTC date, the hash key of the usage day index
+	UsageDay string `json:"day" dynamodbav:"UsageDay"`
+
+	// TenantID, Prefix, Provider and ModelID are copied from the entries counted
+	TenantID string `json:"tenantId,omitempty" dynamodbav:"TenantID,omitempty"`
+	Prefix   string `json:"prefix" dynamodbav:"Prefix"`
+	Provider string `json:"provider" dynamodbav:"Provider"`
+	ModelID  string `json:"modelId,omitempty" dynamodbav:"ModelID,omitempty"`
+
+	// Jobs is the number of completed jobs
+	Jobs int `json:"jobs" dynamodbav:"Jobs"`
+
+	// AudioSeconds, Characters and EstimatedCost are the sums over the day's entries
+	AudioSeconds  float64 `json:"audioSeconds" dynamodbav:"AudioSeconds"`
+	Characters    int     `json:"characters" dynamodbav:"Characters"`
+	EstimatedCost float64 `json:"estimatedCost" dynamodbav:"EstimatedCost"`
+}
+
+// UsageDailyKey returns the DynamoDB FileIdentifier of the daily total an entry is added to
+func UsageDailyKey(entry *UsageEntry) string {
+	return fmt.Sprintf("%s%s#%s#%s#%s#%s", UsageDailyPrefix, entry.Day, entry.TenantID, entry.Prefix, entry.Provider, entry.ModelID)
+}
@@ -0,0 +1,46 @@
+package model
+
+// Vocabulary is a custom vocabulary file: terms the provider should listen for and corrections for
+// what it still gets wrong. Files are JSON objects stored in S3.
+type Vocabulary struct {
+	// Version identifies the revision of the file; it defaults to a hash of the content
+	Version string `json:"version,omitempty"`
+
+	// Keywords are boosted by providers that support keyword boosting
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Corrections are applied to the transcript after transcription
+	Corrections []Correction `json:"corrections,omitempty"`
+
+	// Location is the s3:// URI the vocabulary was loaded from
+	Location string `json:"-"`
+}
+
+// Correction replaces mis-transcriptions of a term with its canonical spelling
+type Correction struct {
+	// From lists the phrases to replace. They match whole words only and ignore case unless
+	// CaseSensitive is set.
+	From []string `json:"from"`
+
+	// To is the replacement. A replacement written in lower case follows the case of the text it
+	// replaces, so a correction at the start of a sentence stays capitalized.
+	To string `json:"to"`
+
+	// CaseSensitive matches From exactly as written
+	CaseSensitive bool `json:"caseSensitive,omitempty"`
+}
+
+// VocabularySource assigns the vocabulary file at Location to the jobs in Scope
+type VocabularySource struct {
+	Scope    Scope
+	Location string
+}
+
+// VocabularyFor returns the source with the most specific scope matching the job
+func VocabularyFor(sources []VocabularySource, source JobSource) (VocabularySource, bool) {
+	i := bestMatch(len(sources), func(i int) Scope { return sources[i].Scope }, source)
+	if i < 0 {
+		return VocabularySource{}, false
+	}
+	return sources[i], true
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultClaimLease is how long a dispatcher may hold a delivery claim before another may take over.
+// It must exceed the slowest sink's total retry time.
+const defaultClaimLease = 2 * time.Minute
+
+// OutboxStore claims and records outbox deliveries
+type OutboxStore interface {
+	ClaimOutboxDelivery(ctx context.Context, eventID, sink string, lease time.Duration) (*model.OutboxItem, bool, error)
+	CompleteOutboxDelivery(ctx context.Context, eventID string, delivery model.NotificationDelivery) (*model.OutboxItem, error)
+	RecordNotifications(ctx context.Context, fileIdentifier string, deliveries []model.NotificationDelivery) error
+}
+
+// Dispatcher delivers outbox events from the transcription table's DynamoDB stream. Each sink
+// receives an event once: deliveries are claimed per sink and recorded on the outbox item, so
+// stream retries and concurrent invocations skip sinks that already have it.
+type Dispatcher struct {
+	store OutboxStore
+	sinks []Sink
+	lease time.Duration
+}
+
+// NewDispatcher creates a dispatcher for the given sinks
+func NewDispatcher(store OutboxStore, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		store: store,
+		sinks: sinks,
+		lease: defaultClaimLease,
+	}
+}
+
+// HandleStreamEvent delivers newly inserted outbox records. Records with failed deliveries are
+// reported as batch item failures so the stream retries them.
+func (d *Dispatcher) HandleStreamEvent(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	// Every line logged for this invocation carries the Lambda request ID
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		ctx = logging.With(ctx, logging.KeyRequestID, lc.AwsRequestID)
+	}
+
+	var response events.DynamoDBEventResponse
+
+	for _, record := range event.Records {
+		if record.EventName != string(events.DynamoDBOperationTypeInsert) {
+			continue
+		}
+
+		key := record.Change.Keys["FileIdentifier"]
+		if !strings.HasPrefix(key.String(), model.OutboxItemPrefix) {
+			continue
+		}
+
+		if err := d.dispatch(ctx, record.Change.NewImage); err != nil {
+			logging.FromContext(ctx).Error("Failed to dispatch outbox record", "record", key.String(), logging.KeyError, err)
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: record.Change.SequenceNumber,
+			})
+		}
+	}
+
+	return response, nil
+}
+
+// dispatch delivers one outbox record to every sink that doesn't have it yet
+func (d *Dispatcher) dispatch(ctx context.Context, image map[string]events.DynamoDBAttributeValue) error {
+	eventID := image["EventID"].String()
+	jobID := image["JobID"].String()
+	payload := []byte(image["Payload"].String())
+
+	// Deliveries continue the trace of the attempt that recorded the event
+	ctx, span := tracing.Start(tracing.Extract(ctx, traceContext(image)), "Dispatch",
+		attribute.String("event", eventID), attribute.String("file", jobID))
+	defer span.End()
+	ctx = logging.With(ctx, logging.KeyFile, jobID, "event", eventID)
+
+	var event model.JobEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		// A malformed payload will never deliver, so don't block the stream with it
+		logging.FromContext(ctx).Error("Skipping outbox event with invalid payload", logging.KeyError, err)
+		return nil
+	}
+
+	var outbox *model.OutboxItem
+	failed := 0
+	for _, sink := range d.sinks {
+		claimed, ok, err := d.store.ClaimOutboxDelivery(ctx, eventID, sink.Name(), d.lease)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logging.FromContext(ctx).Info("Outbox event already delivered or claimed, skipping", "sink", sink.Name())
+			continue
+		}
+
+		delivery := Deliver(ctx, sink, &event, payload)
+		delivery.Attempts += claimed.Deliveries[sink.Name()].Attempts
+		if delivery.Status != model.DeliveryDelivered {
+			failed++
+		}
+
+		if outbox, err = d.store.CompleteOutboxDelivery(ctx, eventID, delivery); err != nil {
+			return err
+		}
+	}
+
+	// Mirror the per-sink status onto the job so it's visible next to the transcript
+	if outbox != nil && len(outbox.Deliveries) > 0 {
+		if err := d.store.RecordNotifications(ctx, jobID, sortedDeliveries(outbox.Deliveries)); err != nil {
+			logging.FromContext(ctx).Warn("Failed to record notification delivery status", logging.KeyError, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sinks failed", failed, len(d.sinks))
+	}
+	return nil
+}
+
+// traceContext reads the trace context recorded with an outbox event
+func traceContext(image map[string]events.DynamoDBAttributeValue) map[string]string {
+	attr, ok := image["TraceContext"]
+	if !ok || attr.DataType() != events.DataTypeMap {
+		return nil
+	}
+
+	headers := map[string]string{}
+	for name, value := range attr.Map() {
+		if value.DataType() == events.DataTypeString {
+			headers[name] = value.String()
+		}
+	}
+	return headers
+}
+
+// sortedDeliveries returns the deliveries ordered by sink name
+func sortedDeliveries(deliveries map[string]model.NotificationDelivery) []model.NotificationDelivery {
+	names := make([]string, 0, len(deliveries))
+	for name := range deliveries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]model.NotificationDelivery, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, deliveries[name])
+	}
+	return sorted
+}
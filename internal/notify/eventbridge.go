@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// EventSource is the EventBridge source of published job events
+const EventSource = "transcription-service"
+
+// EventBridgeAPI is the subset of the EventBridge API used by EventBridgeSink
+type EventBridgeAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// EventBridgeSink puts events on an EventBridge bus
+type EventBridgeSink struct {
+	client  EventBridgeAPI
+	busName string
+}
+
+// NewEventBridgeSink creates a sink for the given event bus name or ARN
+func NewEventBridgeSink(client EventBridgeAPI, busName string) *EventBridgeSink {
+	return &EventBridgeSink{client: client, busName: busName}
+}
+
+// Name identifies the sink in delivery records
+func (s *EventBridgeSink) Name() string {
+	return "eventbridge:" + s.busName
+}
+
+// Publish puts the event on the bus with the event type as its detail-type
+func (s *EventBridgeSink) Publish(ctx context.Context, event *model.JobEvent, payload []byte) (int, error) {
+	out, err := s.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(s.busName),
+				Source:       aws.String(EventSource),
+				DetailType:   aws.String(event.Type),
+				Detail:       aws.String(string(payload)),
+				Resources:    []string{fmt.Sprintf("arn:aws:s3:::%s/%s", event.SourceBucket, event.SourceKey)},
+			},
+		},
+	})
+	if err != nil {
+		return 1, fmt.Errorf("failed to put event on EventBridge: %w", err)
+	}
+
+	// PutEvents reports per-entry failures in the response rather than as an error
+	if out.FailedEntryCount > 0 && len(out.Entries) > 0 {
+		entry := out.Entries[0]
+		return 1, fmt.Errorf("EventBridge rejected event: %s: %s", aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage))
+	}
+
+	return 1, nil
+}
@@ -0,0 +1,41 @@
+// Package notify delivers job completion events to SNS, EventBridge and signed HTTP webhooks.
+// Events are recorded in the transcription table's outbox and delivered from its stream by Dispatcher.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Sink delivers an encoded event to one destination
+type Sink interface {
+	// Name identifies the sink in delivery records
+	Name() string
+
+	// Publish delivers the JSON-encoded event and returns how many attempts were made
+	Publish(ctx context.Context, event *model.JobEvent, payload []byte) (attempts int, err error)
+}
+
+// Deliver publishes the event to one sink and returns the delivery record
+func Deliver(ctx context.Context, sink Sink, event *model.JobEvent, payload []byte) model.NotificationDelivery {
+	attempts, err := sink.Publish(ctx, event, payload)
+
+	delivery := model.NotificationDelivery{
+		Sink:     sink.Name(),
+		EventID:  event.ID,
+		Status:   model.DeliveryDelivered,
+		Attempts: attempts,
+		At:       time.Now().UTC(),
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to deliver event", "type", event.Type, logging.KeyFile, event.FileIdentifier,
+			"sink", sink.Name(), logging.KeyError, err)
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = err.Error()
+	}
+
+	return delivery
+}
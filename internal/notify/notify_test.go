@@ -0,0 +1,313 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// MockSNSPublisher is a mock implementation of the SNS API
+type MockSNSPublisher struct {
+	mock.Mock
+}
+
+func (m *MockSNSPublisher) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sns.PublishOutput), args.Error(1)
+}
+
+// MockEventBridgeAPI is a mock implementation of the EventBridge API
+type MockEventBridgeAPI struct {
+	mock.Mock
+}
+
+func (m *MockEventBridgeAPI) PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*eventbridge.PutEventsOutput), args.Error(1)
+}
+
+func testEvent() *model.JobEvent {
+	return &model.JobEvent{
+		Version:        model.JobEventVersion,
+		Type:           model.EventTypeCompleted,
+		FileIdentifier: "audio/call.mp3",
+		Status:         model.StatusCompleted,
+		SourceBucket:   "audio-bucket",
+		SourceKey:      "audio/call.mp3",
+	}
+}
+
+func TestWebhookSink_SignsAndRetries(t *testing.T) {
+	secret := []byte("shh")
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(TimestampHeader)
+
+		assert.Equal(t, Sign(secret, timestamp, body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "evt-1", r.Header.Get(EventIDHeader))
+		assert.Equal(t, model.EventTypeCompleted, r.Header.Get(EventTypeHeader))
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL+"/hooks?token=abc", secret)
+	sink.backoff = time.Millisecond
+
+	event := testEvent()
+	event.ID = "evt-1"
+
+	attempts, err := sink.Publish(context.Background(), event, []byte(`{"id":"evt-1"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, "webhook:"+server.URL+"/hooks", sink.Name())
+}
+
+func TestWebhookSink_ClientErrorIsNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, []byte("shh"))
+	sink.backoff = time.Millisecond
+
+	attempts, err := sink.Publish(context.Background(), testEvent(), []byte(`{}`))
+
+	assert.EqualError(t, err, "webhook returned status 400")
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.{}" keyed with "secret"
+	assert.Equal(t,
+		"sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		Sign([]byte("secret"), "1700000000", []byte("{}")))
+}
+
+func TestSNSSink(t *testing.T) {
+	client := new(MockSNSPublisher)
+	sink := NewSNSSink(client, "arn:aws:sns:us-east-1:123456789012:transcripts")
+	ctx := context.Background()
+
+	client.On("Publish", ctx, mock.MatchedBy(func(input *sns.PublishInput) bool {
+		return aws.ToString(input.TopicArn) == "arn:aws:sns:us-east-1:123456789012:transcripts" &&
+			aws.ToString(input.Message) == `{"id":"evt"}` &&
+			aws.ToString(input.MessageAttributes["status"].StringValue) == "COMPLETED"
+	})).Return(&sns.PublishOutput{}, nil)
+
+	attempts, err := sink.Publish(ctx, testEvent(), []byte(`{"id":"evt"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	client.AssertExpectations(t)
+}
+
+func TestEventBridgeSink_FailedEntry(t *testing.T) {
+	client := new(MockEventBridgeAPI)
+	sink := NewEventBridgeSink(client, "jobs")
+	ctx := context.Background()
+
+	client.On("PutEvents", ctx, mock.MatchedBy(func(input *eventbridge.PutEventsInput) bool {
+		entry := input.Entries[0]
+		return aws.ToString(entry.EventBusName) == "jobs" &&
+			aws.ToString(entry.Source) == EventSource &&
+			aws.ToString(entry.DetailType) == model.EventTypeCompleted
+	})).Return(&eventbridge.PutEventsOutput{
+		FailedEntryCount: 1,
+		Entries: []ebtypes.PutEventsResultEntry{
+			{ErrorCode: aws.String("ThrottlingException"), ErrorMessage: aws.String("slow down")},
+		},
+	}, nil)
+
+	_, err := sink.Publish(ctx, testEvent(), []byte(`{}`))
+
+	assert.EqualError(t, err, "EventBridge rejected event: ThrottlingException: slow down")
+}
+
+// MockOutboxStore is a mock implementation of the outbox store
+type MockOutboxStore struct {
+	mock.Mock
+}
+
+func (m *MockOutboxStore) ClaimOutboxDelivery(ctx context.Context, eventID, sink string, lease time.Duration) (*model.OutboxItem, bool, error) {
+	args := m.Called(ctx, eventID, sink, lease)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*model.OutboxItem), args.Bool(1), args.Error(2)
+}
+
+func (m *MockOutboxStore) CompleteOutboxDelivery(ctx context.Context, eventID string, delivery model.NotificationDelivery) (*model.OutboxItem, error) {
+	args := m.Called(ctx, eventID, delivery)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OutboxItem), args.Error(1)
+}
+
+func (m *MockOutboxStore) RecordNotifications(ctx context.Context, fileIdentifier string, deliveries []model.NotificationDelivery) error {
+	args := m.Called(ctx, fileIdentifier, deliveries)
+	return args.Error(0)
+}
+
+// outboxRecord builds a stream INSERT record for an outbox item
+func outboxRecord(sequence, eventID, payload string) events.DynamoDBEventRecord {
+	return events.DynamoDBEventRecord{
+		EventName: string(events.DynamoDBOperationTypeInsert),
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: sequence,
+			Keys: map[string]events.DynamoDBAttributeValue{
+				"FileIdentifier": events.NewStringAttribute(model.OutboxKey(eventID)),
+			},
+			NewImage: map[string]events.DynamoDBAttributeValue{
+				"FileIdentifier": events.NewStringAttribute(model.OutboxKey(eventID)),
+				"EventID":        events.NewStringAttribute(eventID),
+				"JobID":          events.NewStringAttribute("audio/call.mp3"),
+				"Payload":        events.NewStringAttribute(payload),
+			},
+		},
+	}
+}
+
+func TestDispatcher_DeliversOncePerSink(t *testing.T) {
+	store := new(MockOutboxStore)
+	snsClient := new(MockSNSPublisher)
+	ebClient := new(MockEventBridgeAPI)
+	ctx := context.Background()
+
+	payload := `{"version":"1","id":"evt-1","type":"transcription.completed","fileIdentifier":"audio/call.mp3","status":"COMPLETED"}`
+	snsSink := NewSNSSink(snsClient, "topic")
+	ebSink := NewEventBridgeSink(ebClient, "bus")
+	dispatcher := NewDispatcher(store, snsSink, ebSink)
+
+	// SNS already has the event from an earlier invocation; EventBridge fails and is retried
+	store.On("ClaimOutboxDelivery", mock.Anything, "evt-1", "sns:topic", defaultClaimLease).Return(nil, false, nil)
+	store.On("ClaimOutboxDelivery", mock.Anything, "evt-1", "eventbridge:bus", defaultClaimLease).Return(&model.OutboxItem{
+		Deliveries: map[string]model.NotificationDelivery{
+			"eventbridge:bus": {Sink: "eventbridge:bus", Status: model.DeliveryFailed, Attempts: 1},
+		},
+	}, true, nil)
+	ebClient.On("PutEvents", mock.Anything, mock.Anything).Return(nil, errors.New("access denied"))
+
+	delivered := model.NotificationDelivery{Sink: "sns:topic", EventID: "evt-1", Status: model.DeliveryDelivered, Attempts: 1}
+	store.On("CompleteOutboxDelivery", mock.Anything, "evt-1", mock.MatchedBy(func(delivery model.NotificationDelivery) bool {
+		return delivery.Sink == "eventbridge:bus" && delivery.Status == model.DeliveryFailed && delivery.Attempts == 2
+	})).Return(&model.OutboxItem{
+		Deliveries: map[string]model.NotificationDelivery{
+			"sns:topic":       delivered,
+			"eventbridge:bus": {Sink: "eventbridge:bus", Status: model.DeliveryFailed, Attempts: 2},
+		},
+	}, nil)
+	store.On("RecordNotifications", mock.Anything, "audio/call.mp3", mock.MatchedBy(func(deliveries []model.NotificationDelivery) bool {
+		return len(deliveries) == 2 && deliveries[0].Sink == "eventbridge:bus" && deliveries[1] == delivered
+	})).Return(nil)
+
+	resp, err := dispatcher.HandleStreamEvent(ctx, events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{
+			outboxRecord("100", "evt-1", payload),
+			// Job updates on the same stream are ignored
+			{
+				EventName: string(events.DynamoDBOperationTypeModify),
+				Change: events.DynamoDBStreamRecord{
+					SequenceNumber: "101",
+					Keys: map[string]events.DynamoDBAttributeValue{
+						"FileIdentifier": events.NewStringAttribute("audio/call.mp3"),
+					},
+				},
+			},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []events.DynamoDBBatchItemFailure{{ItemIdentifier: "100"}}, resp.BatchItemFailures)
+	snsClient.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestDispatcher_SkipsInvalidPayload(t *testing.T) {
+	store := new(MockOutboxStore)
+	dispatcher := NewDispatcher(store, NewSNSSink(new(MockSNSPublisher), "topic"))
+
+	resp, err := dispatcher.HandleStreamEvent(context.Background(), events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{outboxRecord("200", "evt-2", "not json")},
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.BatchItemFailures)
+	store.AssertNotCalled(t, "ClaimOutboxDelivery", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatcher_ContinuesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	store := new(MockOutboxStore)
+	snsClient := new(MockSNSPublisher)
+	dispatcher := NewDispatcher(store, NewSNSSink(snsClient, "topic"))
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	record := outboxRecord("300", "evt-3", `{"version":"1","id":"evt-3","type":"transcription.completed","fileIdentifier":"audio/call.mp3","status":"COMPLETED"}`)
+	record.Change.NewImage["TraceContext"] = events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{
+		"traceparent": events.NewStringAttribute(traceparent),
+	})
+
+	store.On("ClaimOutboxDelivery", mock.Anything, "evt-3", "sns:topic", defaultClaimLease).Return(&model.OutboxItem{}, true, nil)
+	snsClient.On("Publish", mock.Anything, mock.MatchedBy(func(input *sns.PublishInput) bool {
+		// The subscriber sees the dispatch span as the parent, in the original trace
+		value := aws.ToString(input.MessageAttributes["traceparent"].StringValue)
+		return strings.HasPrefix(value, "00-4bf92f3577b34da6a3ce929d0e0e4736-") && value != traceparent
+	})).Return(&sns.PublishOutput{}, nil)
+	store.On("CompleteOutboxDelivery", mock.Anything, "evt-3", mock.Anything).Return(&model.OutboxItem{}, nil)
+	store.On("RecordNotifications", mock.Anything, "audio/call.mp3", mock.Anything).Return(nil)
+
+	resp, err := dispatcher.HandleStreamEvent(context.Background(), events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{record},
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.BatchItemFailures)
+	snsClient.AssertExpectations(t)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "Dispatch", spans[0].Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+// SNSPublisher is the subset of the SNS API used by SNSSink
+type SNSPublisher interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SNSSink publishes events to an SNS topic. The event type and status are also sent as
+// message attributes so subscriptions can use filter policies.
+type SNSSink struct {
+	client   SNSPublisher
+	topicARN string
+}
+
+// NewSNSSink creates a sink for the given topic
+func NewSNSSink(client SNSPublisher, topicARN string) *SNSSink {
+	return &SNSSink{client: client, topicARN: topicARN}
+}
+
+// Name identifies the sink in delivery records
+func (s *SNSSink) Name() string {
+	return "sns:" + s.topicARN
+}
+
+// Publish sends the event to the topic. The SDK retries transient errors itself.
+func (s *SNSSink) Publish(ctx context.Context, event *model.JobEvent, payload []byte) (int, error) {
+	input := &sns.PublishInput{
+		TopicArn: aws.String(s.topicARN),
+		Message:  aws.String(string(payload)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"type": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+			"status": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(string(event.Status)),
+			},
+			"version": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Version),
+			},
+		},
+	}
+
+	// Trace context goes in message attributes, which SNS passes on to SQS and Lambda subscribers
+	for name, value := range tracing.Inject(ctx) {
+		input.MessageAttributes[name] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
+	// FIFO topics drop duplicates of the same event and keep each job's events in order
+	if strings.HasSuffix(s.topicARN, ".fifo") {
+		input.MessageDeduplicationId = aws.String(event.ID)
+		input.MessageGroupId = aws.String(event.FileIdentifier)
+	}
+
+	_, err := s.client.Publish(ctx, input)
+	if err != nil {
+		return 1, fmt.Errorf("failed to publish to SNS: %w", err)
+	}
+
+	return 1, nil
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+// Webhook request headers. Receivers verify SignatureHeader by computing
+// Sign(secret, TimestampHeader value, body) and comparing in constant time.
+const (
+	SignatureHeader = "X-Transcription-Signature"
+	TimestampHeader = "X-Transcription-Timestamp"
+	EventIDHeader   = "X-Transcription-Event-Id"
+	EventTypeHeader = "X-Transcription-Event-Type"
+)
+
+const (
+	// defaultWebhookAttempts is how many times a webhook delivery is tried
+	defaultWebhookAttempts = 4
+
+	// defaultWebhookBackoff is the delay before the first retry; it doubles per attempt
+	defaultWebhookBackoff = 500 * time.Millisecond
+)
+
+// WebhookSink POSTs events to an HTTP endpoint with an HMAC-SHA256 signature.
+// Network errors, 429 and 5xx responses are retried with exponential backoff.
+type WebhookSink struct {
+	url         string
+	secret      []byte
+	httpClient  *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewWebhookSink creates a sink that signs requests to endpoint with secret
+func NewWebhookSink(endpoint string, secret []byte) *WebhookSink {
+	return &WebhookSink{
+		url:         endpoint,
+		secret:      secret,
+		httpClient:  tracing.HTTPClient(&http.Client{Timeout: 10 * time.Second}),
+		maxAttempts: defaultWebhookAttempts,
+		backoff:     defaultWebhookBackoff,
+	}
+}
+
+// Name identifies the sink in delivery records. Query strings are dropped since they may carry tokens.
+func (s *WebhookSink) Name() string {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return "webhook"
+	}
+	return "webhook:" + u.Scheme + "://" + u.Host + u.Path
+}
+
+// Publish POSTs the event, retrying transient failures
+func (s *WebhookSink) Publish(ctx context.Context, event *model.JobEvent, payload []byte) (int, error) {
+	var lastErr error
+	backoff := s.backoff
+
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		retry, err := s.post(ctx, event, payload)
+		if err == nil {
+			return attempt, nil
+		}
+		lastErr = err
+
+		if !retry || attempt == s.maxAttempts {
+			return attempt, lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return s.maxAttempts, lastErr
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (s *WebhookSink) post(ctx context.Context, event *model.JobEvent, payload []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	// Signed per attempt so receivers can reject stale timestamps
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, event.Type)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(s.secret, timestamp, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// Sign returns the signature header value for a webhook body: "sha256=" followed by the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the shared secret
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,181 @@
+// Package postprocess runs an ordered pipeline of stages over a structured transcript between the
+// provider's response and storage: normalization, find-replace dictionaries, redaction and so on.
+package postprocess
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+// Document is the transcript a pipeline works on, with the job it belongs to
+type Document struct {
+	// FileIdentifier is the job's identifier
+	FileIdentifier string
+
+	// Source is the job's input object and the tenant that owns it
+	Source model.JobSource
+
+	// Transcript is the structured transcript; stages change its text and words in place
+	Transcript *model.Transcript
+
+	// Vocabulary is the job's custom vocabulary, if it has one
+	Vocabulary *model.Vocabulary
+
+	attributes map[string]interface{}
+}
+
+// Record asks for an attribute to be stored on the job's item when it completes. A nil value removes
+// the attribute, clearing what an earlier attempt recorded.
+func (d *Document) Record(attr string, value interface{}) {
+	if d.attributes == nil {
+		d.attributes = map[string]interface{}{}
+	}
+	d.attributes[attr] = value
+}
+
+// Attributes returns the recorded attribute names, sorted, and their values
+func (d *Document) Attributes() ([]string, map[string]interface{}) {
+	names := make([]string, 0, len(d.attributes))
+	for name := range d.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, d.attributes
+}
+
+// Stage transforms a document. A stage that fails may have partly changed it; the pipeline discards
+// those changes when the stage is skipped.
+type Stage interface {
+	Process(ctx context.Context, doc *Document) error
+}
+
+// StageFunc adapts a function to the Stage interface
+type StageFunc func(ctx context.Context, doc *Document) error
+
+// Process calls f
+func (f StageFunc) Process(ctx context.Context, doc *Document) error {
+	return f(ctx, doc)
+}
+
+// ErrorPolicy decides what a stage failure does to the job
+type ErrorPolicy string
+
+const (
+	// FailJob fails the job when the stage fails
+	FailJob ErrorPolicy = "fail"
+
+	// SkipStage discards the stage's changes and carries on with the next stage
+	SkipStage ErrorPolicy = "skip"
+)
+
+// Spec configures one stage of a pipeline by name
+type Spec struct {
+	Name    string
+	OnError ErrorPolicy
+}
+
+// ParseSpecs parses "name[:fail|skip],..." into stage specs. Stages fail the job by default.
+func ParseSpecs(value string) ([]Spec, error) {
+	var specs []Spec
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, policy, _ := strings.Cut(entry, ":")
+		spec := Spec{Name: strings.TrimSpace(name), OnError: ErrorPolicy(strings.TrimSpace(policy))}
+		if spec.OnError == "" {
+			spec.OnError = FailJob
+		}
+		if spec.Name == "" || (spec.OnError != FailJob && spec.OnError != SkipStage) {
+			return nil, fmt.Errorf("invalid stage %q, expected name[:fail|skip]", entry)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+type step struct {
+	Spec
+	stage Stage
+}
+
+// Pipeline runs stages in order
+type Pipeline struct {
+	steps []step
+}
+
+// New builds a pipeline from specs, looking stages up by name in available
+func New(specs []Spec, available map[string]Stage) (*Pipeline, error) {
+	p := &Pipeline{}
+	for _, spec := range specs {
+		stage, ok := available[spec.Name]
+		if !ok {
+			return nil, fmt.Errorf("post-processing stage %q is unknown or not configured", spec.Name)
+		}
+		p.steps = append(p.steps, step{Spec: spec, stage: stage})
+	}
+	return p, nil
+}
+
+// Len returns the number of stages
+func (p *Pipeline) Len() int {
+	return len(p.steps)
+}
+
+// Run passes the document through every stage and reports how each went. It stops with an error at
+// the first failing stage whose policy is FailJob.
+func (p *Pipeline) Run(ctx context.Context, doc *Document) ([]model.StageRun, error) {
+	runs := make([]model.StageRun, 0, len(p.steps))
+	for _, step := range p.steps {
+		transcript := cloneTranscript(doc.Transcript)
+		attributes := cloneAttributes(doc.attributes)
+
+		start := time.Now()
+		stageCtx, span := tracing.Start(ctx, "PostProcess "+step.Name)
+		err := step.stage.Process(stageCtx, doc)
+		tracing.End(span, err)
+		run := model.StageRun{Name: step.Name, DurationSeconds: time.Since(start).Seconds()}
+
+		if err != nil {
+			run.Error = err.Error()
+			if step.OnError != SkipStage {
+				runs = append(runs, run)
+				return runs, fmt.Errorf("post-processing stage %s failed: %w", step.Name, err)
+			}
+
+			logging.FromContext(ctx).Warn("Skipping failed post-processing stage", "stage", step.Name, logging.KeyError, err)
+			doc.Transcript = transcript
+			doc.attributes = attributes
+			run.Skipped = true
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// cloneTranscript copies a transcript so a failed stage's changes can be discarded
+func cloneTranscript(t *model.Transcript) *model.Transcript {
+	clone := *t
+	clone.Words = append([]model.Word(nil), t.Words...)
+	return &clone
+}
+
+func cloneAttributes(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(attributes))
+	for name, value := range attributes {
+		clone[name] = value
+	}
+	return clone
+}
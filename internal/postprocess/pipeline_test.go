@@ -0,0 +1,113 @@
+package postprocess
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func testDocument() *Document {
+	return &Document{
+		FileIdentifier: "calls/a.mp3",
+		Source:         model.JobSource{Bucket: "audio", Key: "calls/a.mp3"},
+		Transcript: &model.Transcript{
+			Text: "  Hello  world ,  this is  eleven labs . ",
+			Words: []model.Word{
+				{Text: " ", Start: 0.0, End: 0.1, Type: "spacing"},
+				{Text: "Hello", Start: 0.1, End: 0.4, Type: "word"},
+				{Text: "  ", Start: 0.4, End: 0.5, Type: "spacing"},
+				{Text: " ", Start: 0.5, End: 0.6, Type: "spacing"},
+				{Text: "world", Start: 0.6, End: 1.0, Type: "word"},
+				{Text: " ", Start: 1.0, End: 1.1, Type: "spacing"},
+				{Text: ",", Start: 1.1, End: 1.2, Type: "word"},
+				{Text: " ", Start: 1.2, End: 1.3, Type: "spacing"},
+				{Text: "eleven", Start: 1.3, End: 1.6, Type: "word"},
+				{Text: " ", Start: 1.6, End: 1.7, Type: "spacing"},
+				{Text: "labs", Start: 1.7, End: 2.0, Type: "word"},
+			},
+		},
+	}
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("normalize, replace:skip,redact:fail")
+	assert.NoError(t, err)
+	assert.Equal(t, []Spec{
+		{Name: "normalize", OnError: FailJob},
+		{Name: "replace", OnError: SkipStage},
+		{Name: "redact", OnError: FailJob},
+	}, specs)
+
+	_, err = ParseSpecs("normalize:retry")
+	assert.EqualError(t, err, `invalid stage "normalize:retry", expected name[:fail|skip]`)
+}
+
+func TestPipeline_Run(t *testing.T) {
+	replace, err := Replace(map[string]string{"eleven labs": "ElevenLabs", "eleven": "11"})
+	assert.NoError(t, err)
+
+	pipeline, err := New([]Spec{{Name: "normalize", OnError: FailJob}, {Name: "replace", OnError: FailJob}},
+		map[string]Stage{"normalize": Normalize(), "replace": replace})
+	assert.NoError(t, err)
+
+	doc := testDocument()
+	runs, err := pipeline.Run(context.Background(), doc)
+
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "normalize", runs[0].Name)
+	assert.Equal(t, "Hello world, this is ElevenLabs.", doc.Transcript.Text)
+	assert.Equal(t, []model.Word{
+		{Text: "Hello", Start: 0.1, End: 0.4, Type: "word"},
+		{Text: " ", Start: 0.4, End: 0.6, Type: "spacing"},
+		{Text: "world", Start: 0.6, End: 1.0, Type: "word"},
+		{Text: ",", Start: 1.1, End: 1.2, Type: "word"},
+		{Text: " ", Start: 1.2, End: 1.3, Type: "spacing"},
+		{Text: "ElevenLabs", Start: 1.3, End: 2.0, Type: "word"},
+	}, doc.Transcript.Words)
+}
+
+func TestPipeline_ErrorPolicy(t *testing.T) {
+	broken := StageFunc(func(ctx context.Context, doc *Document) error {
+		doc.Transcript.Text = "half done"
+		doc.Record("Broken", true)
+		return errors.New("dictionary unavailable")
+	})
+	record := StageFunc(func(ctx context.Context, doc *Document) error {
+		doc.Record("Checked", true)
+		return nil
+	})
+	available := map[string]Stage{"broken": broken, "record": record}
+
+	// A skipped stage's changes are rolled back and later stages still run
+	pipeline, err := New([]Spec{{Name: "broken", OnError: SkipStage}, {Name: "record", OnError: FailJob}}, available)
+	assert.NoError(t, err)
+
+	doc := testDocument()
+	runs, err := pipeline.Run(context.Background(), doc)
+
+	assert.NoError(t, err)
+	assert.True(t, runs[0].Skipped)
+	assert.Equal(t, "dictionary unavailable", runs[0].Error)
+	assert.Equal(t, testDocument().Transcript.Text, doc.Transcript.Text)
+	names, values := doc.Attributes()
+	assert.Equal(t, []string{"Checked"}, names)
+	assert.Equal(t, true, values["Checked"])
+
+	// A failing stage that must succeed stops the pipeline
+	pipeline, err = New([]Spec{{Name: "broken", OnError: FailJob}, {Name: "record", OnError: FailJob}}, available)
+	assert.NoError(t, err)
+
+	runs, err = pipeline.Run(context.Background(), testDocument())
+
+	assert.EqualError(t, err, "post-processing stage broken failed: dictionary unavailable")
+	assert.Len(t, runs, 1)
+}
+
+func TestNew_UnknownStage(t *testing.T) {
+	_, err := New([]Spec{{Name: "profanity", OnError: FailJob}}, map[string]Stage{})
+	assert.EqualError(t, err, `post-processing stage "profanity" is unknown or not configured`)
+}
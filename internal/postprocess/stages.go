@@ -0,0 +1,112 @@
+package postprocess
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/transcript"
+)
+
+var (
+	// spaceBeforePunct matches whitespace in front of closing punctuation
+	spaceBeforePunct = regexp.MustCompile(`\s+([,.;:!?)])`)
+
+	// repeatedPunct matches runs of the same separator, such as ",," left by the provider
+	repeatedPunct = regexp.MustCompile(`([,;:])[,;:]+`)
+)
+
+// Normalize returns the stage that collapses whitespace and tidies punctuation spacing. Word timings
+// are kept: spacing tokens are reduced to a single space and merged, and spacing before punctuation
+// is dropped.
+func Normalize() Stage {
+	return StageFunc(func(ctx context.Context, doc *Document) error {
+		doc.Transcript.Text = normalizeText(doc.Transcript.Text)
+		doc.Transcript.Words = normalizeWords(doc.Transcript.Words)
+		return nil
+	})
+}
+
+func normalizeText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = spaceBeforePunct.ReplaceAllString(text, "$1")
+	return repeatedPunct.ReplaceAllString(text, "$1")
+}
+
+func normalizeWords(words []model.Word) []model.Word {
+	normalized := make([]model.Word, 0, len(words))
+	for _, word := range words {
+		if word.Type == "spacing" {
+			if len(normalized) == 0 {
+				continue
+			}
+			if last := &normalized[len(normalized)-1]; last.Type == "spacing" {
+				last.End = word.End
+				continue
+			}
+			word.Text = " "
+			normalized = append(normalized, word)
+			continue
+		}
+
+		word.Text = normalizeText(word.Text)
+		if word.Text == "" {
+			continue
+		}
+		// Punctuation read as its own token attaches to the previous word
+		if n := len(normalized); n > 0 && normalized[n-1].Type == "spacing" && strings.Trim(word.Text, ",.;:!?)") == "" {
+			normalized = normalized[:n-1]
+		}
+		normalized = append(normalized, word)
+	}
+
+	if n := len(normalized); n > 0 && normalized[n-1].Type == "spacing" {
+		normalized = normalized[:n-1]
+	}
+	return normalized
+}
+
+// Replace returns the stage that applies a find-replace dictionary to the text and words. Phrases are
+// matched literally, longest first, and may span several words.
+func Replace(dictionary map[string]string) (Stage, error) {
+	phrases := make([]string, 0, len(dictionary))
+	for phrase := range dictionary {
+		if phrase == "" {
+			return nil, fmt.Errorf("replacement dictionary contains an empty phrase")
+		}
+		phrases = append(phrases, phrase)
+	}
+	if len(phrases) == 0 {
+		return nil, fmt.Errorf("replacement dictionary is empty")
+	}
+
+	// Leftmost-first alternation prefers earlier alternatives, so list longer phrases first
+	sort.Slice(phrases, func(i, j int) bool {
+		if len(phrases[i]) != len(phrases[j]) {
+			return len(phrases[i]) > len(phrases[j])
+		}
+		return phrases[i] < phrases[j]
+	})
+	quoted := make([]string, len(phrases))
+	for i, phrase := range phrases {
+		quoted[i] = regexp.QuoteMeta(phrase)
+	}
+	re := regexp.MustCompile(strings.Join(quoted, "|"))
+
+	find := func(s string) []transcript.Edit {
+		var edits []transcript.Edit
+		for _, loc := range re.FindAllStringIndex(s, -1) {
+			edits = append(edits, transcript.Edit{Start: loc[0], End: loc[1], Text: dictionary[s[loc[0]:loc[1]]]})
+		}
+		return edits
+	}
+
+	return StageFunc(func(ctx context.Context, doc *Document) error {
+		doc.Transcript.Text = transcript.ApplyEdits(doc.Transcript.Text, find(doc.Transcript.Text))
+		doc.Transcript.Words = transcript.RewriteWords(doc.Transcript.Words, find)
+		return nil
+	}), nil
+}
@@ -0,0 +1,127 @@
+package postprocess
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/transcript"
+)
+
+// Vocabulary returns the stage that applies the corrections in the job's custom vocabulary. Phrases
+// match whole words only, so "ai" doesn't change "said", and longer phrases win over shorter ones.
+// Jobs without a vocabulary pass through unchanged.
+func Vocabulary() Stage {
+	return StageFunc(func(ctx context.Context, doc *Document) error {
+		if doc.Vocabulary == nil || len(doc.Vocabulary.Corrections) == 0 {
+			return nil
+		}
+
+		corrector, err := newCorrector(doc.Vocabulary.Corrections)
+		if err != nil {
+			return err
+		}
+		doc.Transcript.Text = transcript.ApplyEdits(doc.Transcript.Text, corrector.find(doc.Transcript.Text))
+		doc.Transcript.Words = transcript.RewriteWords(doc.Transcript.Words, corrector.find)
+		return nil
+	})
+}
+
+type phrase struct {
+	re         *regexp.Regexp
+	length     int
+	correction model.Correction
+}
+
+// corrector finds vocabulary corrections in text
+type corrector struct {
+	phrases []phrase
+}
+
+func newCorrector(corrections []model.Correction) (*corrector, error) {
+	c := &corrector{}
+	for _, correction := range corrections {
+		for _, from := range correction.From {
+			if from == "" {
+				continue
+			}
+
+			pattern := regexp.QuoteMeta(from)
+			if first, _ := utf8.DecodeRuneInString(from); isWordRune(first) {
+				pattern = `\b` + pattern
+			}
+			if last, _ := utf8.DecodeLastRuneInString(from); isWordRune(last) {
+				pattern += `\b`
+			}
+			if !correction.CaseSensitive {
+				pattern = `(?i)` + pattern
+			}
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			c.phrases = append(c.phrases, phrase{re: re, length: len(from), correction: correction})
+		}
+	}
+
+	// Longer phrases first, so "eleven labs scribe" wins over "eleven labs"
+	sort.SliceStable(c.phrases, func(i, j int) bool { return c.phrases[i].length > c.phrases[j].length })
+	return c, nil
+}
+
+// find returns the non-overlapping corrections in s, ordered by position
+func (c *corrector) find(s string) []transcript.Edit {
+	var edits []transcript.Edit
+	for _, phrase := range c.phrases {
+		for _, loc := range phrase.re.FindAllStringIndex(s, -1) {
+			if overlapsEdit(edits, loc[0], loc[1]) {
+				continue
+			}
+			edits = append(edits, transcript.Edit{
+				Start: loc[0],
+				End:   loc[1],
+				Text:  matchCase(s[loc[0]:loc[1]], phrase.correction),
+			})
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+	return edits
+}
+
+// matchCase returns the correction's replacement for matched text. Replacements with capitals are
+// canonical spellings and used as written; lower-case ones follow the case of the match.
+func matchCase(matched string, correction model.Correction) string {
+	to := correction.To
+	if correction.CaseSensitive || strings.ToLower(to) != to {
+		return to
+	}
+
+	if strings.ToUpper(matched) == matched && strings.ToLower(matched) != matched && utf8.RuneCountInString(matched) > 1 {
+		return strings.ToUpper(to)
+	}
+	if first, _ := utf8.DecodeRuneInString(matched); unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(to)
+		return string(unicode.ToUpper(r)) + to[size:]
+	}
+	return to
+}
+
+// isWordRune reports whether r is a character \b treats as part of a word
+func isWordRune(r rune) bool {
+	return r == '_' || r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func overlapsEdit(edits []transcript.Edit, start, end int) bool {
+	for _, edit := range edits {
+		if start < edit.End && edit.Start < end {
+			return true
+		}
+	}
+	return false
+}
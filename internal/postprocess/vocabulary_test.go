@@ -0,0 +1,51 @@
+package postprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestVocabulary(t *testing.T) {
+	doc := &Document{
+		FileIdentifier: "calls/a.mp3",
+		Vocabulary: &model.Vocabulary{
+			Version: "v3",
+			Corrections: []model.Correction{
+				{From: []string{"eleven labs", "11 labs"}, To: "ElevenLabs"},
+				{From: []string{"cube control"}, To: "kubectl"},
+				{From: []string{"AI"}, To: "A.I.", CaseSensitive: true},
+			},
+		},
+		Transcript: &model.Transcript{
+			Text: "Eleven Labs said cube control. Cube control works with 11 labs and AI, not ai or said.",
+			Words: []model.Word{
+				{Text: "Eleven", Start: 0.0, End: 0.4, Type: "word"},
+				{Text: " ", Start: 0.4, End: 0.5, Type: "spacing"},
+				{Text: "Labs", Start: 0.5, End: 0.9, Type: "word"},
+				{Text: " ", Start: 0.9, End: 1.0, Type: "spacing"},
+				{Text: "said", Start: 1.0, End: 1.3, Type: "word"},
+			},
+		},
+	}
+
+	err := Vocabulary().Process(context.Background(), doc)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ElevenLabs said kubectl. Kubectl works with ElevenLabs and A.I., not ai or said.", doc.Transcript.Text)
+	assert.Equal(t, []model.Word{
+		{Text: "ElevenLabs", Start: 0.0, End: 0.9, Type: "word"},
+		{Text: " ", Start: 0.9, End: 1.0, Type: "spacing"},
+		{Text: "said", Start: 1.0, End: 1.3, Type: "word"},
+	}, doc.Transcript.Words)
+}
+
+func TestMatchCase(t *testing.T) {
+	correction := model.Correction{To: "kubectl"}
+	assert.Equal(t, "kubectl", matchCase("cube control", correction))
+	assert.Equal(t, "Kubectl", matchCase("Cube control", correction))
+	assert.Equal(t, "KUBECTL", matchCase("CUBE CONTROL", correction))
+	assert.Equal(t, "ElevenLabs", matchCase("ELEVEN LABS", model.Correction{To: "ElevenLabs"}))
+}
@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/notify"
+)
+
+// callbackHTTPClient is used to notify batch callback URLs. Redirects aren't followed, since they could
+// lead anywhere.
+var callbackHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// batchCallbacks signs batch summaries and limits where they are sent
+type batchCallbacks struct {
+	secret []byte
+	hosts  map[string]bool
+}
+
+// SetBatchCallbacks lets batch manifests name https callbacks on the allowed hosts. Summaries are signed
+// with secret like completion webhooks. Without callbacks, manifests naming one are rejected.
+func (p *Processor) SetBatchCallbacks(secret []byte, allowedHosts []string) {
+	hosts := map[string]bool{}
+	for _, host := range allowedHosts {
+		hosts[strings.ToLower(host)] = true
+	}
+	p.callbacks = &batchCallbacks{secret: secret, hosts: hosts}
+}
+
+// checkCallback returns an error unless the callback URL may be notified
+func (p *Processor) checkCallback(callback string) error {
+	if p.callbacks == nil {
+		return fmt.Errorf("batch callbacks are not enabled")
+	}
+
+	u, err := url.Parse(callback)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("callback URL must use https")
+	}
+	if !p.callbacks.hosts[strings.ToLower(u.Hostname())] {
+		return fmt.Errorf("callback host %q is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+// notifyBatchCallback POSTs the final batch summary to the manifest's callback URL, signed as
+// notify.Sign describes
+func (p *Processor) notifyBatchCallback(ctx context.Context, batch *model.BatchItem) error {
+	// The batch may have been recorded before the allowed hosts changed
+	if err := p.checkCallback(batch.Callback); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch summary: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batch.Callback, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create callback request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(notify.TimestampHeader, timestamp)
+	req.Header.Set(notify.SignatureHeader, notify.Sign(p.callbacks.secret, timestamp, body))
+
+	resp, err := callbackHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send callback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned non-2xx status code: %d", resp.StatusCode)
+	}
+
+	logging.FromContext(ctx).Info("Notified batch callback", "batch", batch.BatchID)
+	return nil
+}
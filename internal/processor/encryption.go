@@ -0,0 +1,26 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yourusername/transcription-service/internal/envelope"
+)
+
+// SetEncryption enables envelope encryption of transcripts at rest. The item's text is stored as an
+// encrypted envelope and S3 outputs are written as sealed objects; nothing is stored in plaintext.
+func (p *Processor) SetEncryption(encrypter *envelope.Encrypter) {
+	p.encrypter = encrypter
+}
+
+// uploadOutput writes a job output to S3, sealing it first when encryption is enabled
+func (p *Processor) uploadOutput(ctx context.Context, fileID, bucket, key, content string) error {
+	if p.encrypter != nil {
+		sealed, err := p.encrypter.SealObject(ctx, []byte(content), envelope.JobContext(fileID))
+		if err != nil {
+			return err
+		}
+		content = string(sealed)
+	}
+
+	return p.s3Operations.UploadText(ctx, bucket, key, content)
+}
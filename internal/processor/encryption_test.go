@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestProcessFile_EncryptsTranscript(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	keys, err := envelope.NewLocalKeyService("alias/transcripts")
+	assert.NoError(t, err)
+	enc := envelope.NewEncrypter(keys, "alias/transcripts")
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	processor.SetEncryption(enc)
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "calls/private.mp3"
+	text := "Account number is twelve."
+
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    text,
+		Success: true,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+
+	// The output object is sealed and bound to the job
+	var uploaded string
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", "transcripts/calls/private.mp3.txt", mock.MatchedBy(func(content string) bool {
+		uploaded = content
+		return envelope.IsSealedObject([]byte(content)) && !strings.Contains(content, "twelve")
+	})).Return(nil)
+
+	var sealed *model.Envelope
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		if update.NewStatus() != model.StatusCompleted {
+			return false
+		}
+		value, ok := update.Value(awsclient.AttrEncryptedTranscript)
+		_, hasText := update.Value(awsclient.AttrTranscriptText)
+		if !ok || hasText || !update.Removes(awsclient.AttrTranscriptText) {
+			return false
+		}
+		sealed = value.(*model.Envelope)
+		return true
+	})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+
+	err = processor.ProcessFile(ctx, bucket, key)
+
+	assert.NoError(t, err)
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+
+	assert.Equal(t, "alias/transcripts", sealed.KeyID)
+	assert.Equal(t, map[string]string{"FileIdentifier": key}, sealed.Context)
+	plaintext, err := enc.Open(ctx, sealed, envelope.JobContext(key))
+	assert.NoError(t, err)
+	assert.Equal(t, text, string(plaintext))
+
+	plaintext, err = enc.OpenObject(ctx, []byte(uploaded), envelope.JobContext(key))
+	assert.NoError(t, err)
+	assert.Equal(t, text, string(plaintext))
+}
@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultProviderName is the name under which the ElevenLabs client is registered
+const DefaultProviderName = model.ProviderElevenLabs
+
+// RegisterProvider makes an additional transcription provider available to language routes. Configured
+// routes are checked against model.Providers, so a new provider must be listed there too.
+func (p *Processor) RegisterProvider(name string, client TranscriptionClient) {
+	if p.providers == nil {
+		p.providers = make(map[string]TranscriptionClient)
+	}
+	p.providers[name] = client
+}
+
+// SetLanguageRouting configures which languages the default model supports and where other languages go.
+// An empty supported list means the default model is used for every language.
+func (p *Processor) SetLanguageRouting(supported []string, routes map[string]model.LanguageRoute) {
+	p.supportedLanguages = make(map[string]bool, len(supported))
+	for _, lang := range supported {
+		p.supportedLanguages[normalizeLanguage(lang)] = true
+	}
+
+	p.languageRoutes = make(map[string]model.LanguageRoute, len(routes))
+	for lang, route := range routes {
+		p.languageRoutes[normalizeLanguage(lang)] = route
+	}
+}
+
+// providerCall is a provider call that returned a transcript
+type providerCall struct {
+	route model.LanguageRoute
+	resp  *model.ElevenLabsResponse
+}
+
+// transcribe calls the provider for the audio, honouring the language hint and re-routing
+// to a language-specific provider/model when the detected language isn't supported by the default.
+// A non-empty explicit route (e.g. from a reprocess request) disables language-based routing.
+// Keywords from the job's vocabulary are sent to providers that support keyword boosting.
+// The provider bills the detection call of a re-routed job too, so it is returned as replaced, even
+// when the re-routed call fails.
+func (p *Processor) transcribe(ctx context.Context, audioURL, languageHint string, explicit model.LanguageRoute, vocabulary *model.Vocabulary) (*model.ElevenLabsResponse, model.LanguageRoute, []providerCall, error) {
+	language := normalizeLanguage(languageHint)
+
+	if explicit != (model.LanguageRoute{}) {
+		resp, err := p.callProvider(ctx, audioURL, explicit, language, vocabulary)
+		return resp, explicit, nil, err
+	}
+
+	var route model.LanguageRoute
+	if language != "" {
+		if r, ok := p.routeFor(language); ok {
+			route = r
+		}
+	}
+
+	resp, err := p.callProvider(ctx, audioURL, route, language, vocabulary)
+	if err != nil {
+		return nil, route, nil, err
+	}
+
+	detected := normalizeLanguage(resp.LanguageCode)
+	if language != "" || detected == "" {
+		return resp, route, nil, nil
+	}
+
+	rerouted, ok := p.routeFor(detected)
+	if !ok || rerouted == route {
+		return resp, route, nil, nil
+	}
+
+	logging.FromContext(ctx).Info("Detected language is not supported by the default model, re-routing",
+		"language", detected, "probability", resp.LanguageProbability, "routedProvider", providerName(rerouted), "model", rerouted.ModelID)
+
+	replaced := []providerCall{{route: route, resp: resp}}
+	routedResp, err := p.callProvider(ctx, audioURL, rerouted, detected, vocabulary)
+	if err != nil {
+		return nil, rerouted, replaced, fmt.Errorf("re-routed transcription for language %s failed: %w", detected, err)
+	}
+
+	// Keep the original detection if the routed provider doesn't report one, and its duration,
+	// since both calls were sent the same audio
+	if routedResp.LanguageCode == "" {
+		routedResp.LanguageCode = resp.LanguageCode
+		routedResp.LanguageProbability = resp.LanguageProbability
+	}
+	if routedResp.AudioDuration == 0 {
+		routedResp.AudioDuration = resp.AudioDuration
+	}
+
+	return routedResp, rerouted, replaced, nil
+}
+
+// callProvider sends the audio to the provider named by the route
+func (p *Processor) callProvider(ctx context.Context, audioURL string, route model.LanguageRoute, language string, vocabulary *model.Vocabulary) (resp *model.ElevenLabsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "Transcribe",
+		attribute.String("provider", providerName(route)),
+		attribute.String("model", route.ModelID),
+		attribute.String("language", language))
+	defer func() { tracing.End(span, err) }()
+
+	client, err := p.defaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if name := providerName(route); name != DefaultProviderName {
+		registered, ok := p.providers[name]
+		if !ok {
+			return nil, fmt.Errorf("transcription provider %q is not registered", name)
+		}
+		client = registered
+	}
+
+	keywords := keywordsFor(client, vocabulary)
+	if language == "" && route.ModelID == "" && len(keywords) == 0 {
+		return client.TranscribeAudio(ctx, audioURL)
+	}
+
+	return client.TranscribeAudioWithOptions(ctx, audioURL, model.TranscribeOptions{
+		LanguageCode: language,
+		ModelID:      route.ModelID,
+		Keywords:     keywords,
+	})
+}
+
+// routeFor returns the route for a language the default model doesn't support
+func (p *Processor) routeFor(language string) (model.LanguageRoute, bool) {
+	if len(p.supportedLanguages) == 0 || p.supportedLanguages[language] {
+		return model.LanguageRoute{}, false
+	}
+
+	// Fall back to the base language for regional codes such as pt-br
+	if base, _, ok := strings.Cut(language, "-"); ok && p.supportedLanguages[base] {
+		return model.LanguageRoute{}, false
+	}
+
+	route, ok := p.languageRoutes[language]
+	if !ok {
+		if base, _, cut := strings.Cut(language, "-"); cut {
+			route, ok = p.languageRoutes[base]
+		}
+	}
+	return route, ok
+}
+
+// providerName returns the provider a route targets
+func providerName(route model.LanguageRoute) string {
+	if route.Provider == "" {
+		return DefaultProviderName
+	}
+	return route.Provider
+}
+
+// normalizeLanguage lower-cases a language code and maps "auto" to detection
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	language = strings.ReplaceAll(language, "_", "-")
+	if language == model.LanguageAuto {
+		return ""
+	}
+	return language
+}
@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestProcessFile_RoutesUnsupportedDetectedLanguage(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	defaultClient := new(MockElevenLabsClient)
+	japaneseClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   defaultClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.RegisterProvider("whisper", japaneseClient)
+	processor.SetLanguageRouting([]string{"en", "es"}, map[string]model.LanguageRoute{
+		"ja": {Provider: "whisper", ModelID: "large-v3"},
+	})
+
+	ctx := context.Background()
+	key := "audio/call.aac"
+
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.LanguageHint == model.LanguageAuto
+	})).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "bucket", key, 3600).Return("https://presigned-url", nil)
+
+	defaultClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:                "garbled",
+		LanguageCode:        "ja",
+		LanguageProbability: 0.97,
+		Success:             true,
+	}, nil)
+	japaneseClient.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-url", model.TranscribeOptions{
+		LanguageCode: "ja",
+		ModelID:      "large-v3",
+	}).Return(&model.ElevenLabsResponse{Text: "こんにちは", Success: true}, nil)
+
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionLanguage", mock.Anything, key, "ja", 0.97, model.LanguageRoute{Provider: "whisper", ModelID: "large-v3"}).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusCompleted, awsclient.AttrTranscriptText, "こんにちは")).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Provider == "whisper" && record.ModelID == "large-v3"
+	})).Return(nil)
+
+	err := processor.ProcessFileWithOptions(ctx, "bucket", key, model.JobOptions{Language: model.LanguageAuto})
+
+	assert.NoError(t, err)
+	defaultClient.AssertExpectations(t)
+	japaneseClient.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestTranscribe_LanguageHint(t *testing.T) {
+	client := new(MockElevenLabsClient)
+	processor := &Processor{elevenlabsClient: client}
+	processor.SetLanguageRouting([]string{"en"}, map[string]model.LanguageRoute{
+		"fr": {ModelID: "scribe_fr"},
+	})
+
+	ctx := context.Background()
+
+	// Supported hint is passed through to the default model
+	client.On("TranscribeAudioWithOptions", mock.Anything, "url", model.TranscribeOptions{LanguageCode: "en-us"}).
+		Return(&model.ElevenLabsResponse{Text: "hello", Success: true}, nil)
+	resp, route, _, err := processor.transcribe(ctx, "url", "en_US", model.LanguageRoute{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", resp.Text)
+	assert.Equal(t, model.LanguageRoute{}, route)
+
+	// Unsupported hint is routed before the first call
+	client.On("TranscribeAudioWithOptions", mock.Anything, "url", model.TranscribeOptions{LanguageCode: "fr", ModelID: "scribe_fr"}).
+		Return(&model.ElevenLabsResponse{Text: "bonjour", Success: true}, nil)
+	resp, route, _, err = processor.transcribe(ctx, "url", "FR", model.LanguageRoute{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "bonjour", resp.Text)
+	assert.Equal(t, "scribe_fr", route.ModelID)
+
+	// Routes to unregistered providers fail loudly
+	processor.SetLanguageRouting([]string{"en"}, map[string]model.LanguageRoute{"de": {Provider: "missing"}})
+	_, _, _, err = processor.transcribe(ctx, "url", "de", model.LanguageRoute{}, nil)
+	assert.EqualError(t, err, `transcription provider "missing" is not registered`)
+}
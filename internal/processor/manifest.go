@@ -0,0 +1,310 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// SetSubmissionBucket sets where batch manifests write the dispatch manifests of their jobs; empty
+// writes them to the batch manifest's bucket
+func (p *Processor) SetSubmissionBucket(bucket string) {
+	p.submissionBucket = bucket
+}
+
+// ProcessManifest reads a job manifest from S3 and records the batch. A batch manifest fans out to one
+// dispatch manifest per listed file, so each job runs in an invocation of its own; dispatch manifests
+// are processed here. A file counts towards the batch once its job has finished; jobs that are still
+// running or deferred count when they finish. A manifest delivered again while its batch is in progress
+// resumes the batch, dispatching or processing only the files that have no job yet or whose job is
+// still pending. The scheduler dispatches deferred jobs this way, with one-file manifests for the jobs'
+// batches; a job that isn't part of a batch is dispatched with a manifest that names no batch and runs
+// on its own.
+func (p *Processor) ProcessManifest(ctx context.Context, bucket, key string) error {
+	logging.FromContext(ctx).Info("Reading job manifest", logging.KeyBucket, bucket, "manifest", key)
+
+	data, err := p.s3Operations.ReadObject(ctx, bucket, key)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	dispatch := model.IsDispatchManifest(key)
+	manifest, err := parseManifest(data, dispatch)
+	if err != nil {
+		return fmt.Errorf("invalid manifest s3://%s/%s: %w", bucket, key, err)
+	}
+
+	// A tenant's batches are kept apart from other tenants' batches with the same ID. Manifests outside
+	// the tenants' prefixes, like the scheduler's, name the tenant they submit for.
+	tenantID := manifest.TenantID
+	if owner, ok := p.tenants.Resolve(bucket, key); ok {
+		if tenantID != "" && tenantID != owner.ID {
+			return fmt.Errorf("invalid manifest s3://%s/%s: tenant %s doesn't own it", bucket, key, tenantID)
+		}
+		tenantID = owner.ID
+	}
+
+	if manifest.BatchID == "" {
+		return p.processDispatchedFile(ctx, bucket, tenantID, manifest.Files[0])
+	}
+	if manifest.Callback != "" && !dispatch {
+		if err := p.checkCallback(manifest.Callback); err != nil {
+			return fmt.Errorf("invalid manifest s3://%s/%s: %w", bucket, key, err)
+		}
+	}
+
+	batch := &model.BatchItem{
+		BatchID:          manifest.BatchID,
+		TenantID:         tenantID,
+		BatchStatus:      model.BatchStatusInProgress,
+		ManifestLocation: fmt.Sprintf("s3://%s/%s", bucket, key),
+		Callback:         manifest.Callback,
+		TotalFiles:       len(manifest.Files),
+	}
+	if dispatch {
+		// A one-file batch of the Jobs API is done with once its job is, like the manifest itself
+		batch.ExpiresAt = time.Now().Add(model.DispatchRetention).Unix()
+	}
+
+	resumed := false
+	err = p.dynamoDBOperations.CreateBatchItem(ctx, batch)
+	if errors.Is(err, awsclient.ErrBatchExists) {
+		existing, err := p.dynamoDBOperations.GetBatchItem(ctx, tenantID, manifest.BatchID)
+		if err != nil {
+			return fmt.Errorf("failed to read batch item: %w", err)
+		}
+		if existing == nil || existing.BatchStatus != model.BatchStatusInProgress {
+			logging.FromContext(ctx).Info("Batch was already submitted, skipping manifest", "batch", manifest.BatchID)
+			return nil
+		}
+		logging.FromContext(ctx).Info("Batch is still in progress, resuming", "batch", manifest.BatchID)
+		resumed = true
+	} else if err != nil {
+		return fmt.Errorf("failed to create batch item: %w", err)
+	}
+
+	ref := model.BatchRef{TenantID: tenantID, BatchID: manifest.BatchID}
+	failed := 0
+	for i, entry := range manifest.Files {
+		fileBucket := entry.Bucket
+		if fileBucket == "" {
+			fileBucket = bucket
+		}
+		fileID := p.jobID(fileBucket, entry.Key)
+
+		opts := entry.Options
+		opts.BatchID = manifest.BatchID
+		opts.BatchTenantID = tenantID
+
+		// A tenant's manifest may only submit that tenant's audio
+		if p.crossTenant(tenantID, fileBucket, entry.Key) {
+			logging.FromContext(ctx).Error("Failed to process batch file", "batch", manifest.BatchID, logging.KeyFile, entry.Key,
+				logging.KeyError, fmt.Errorf("s3://%s/%s is not owned by the manifest's tenant", fileBucket, entry.Key))
+			failed++
+			p.recordBatchProgress(ctx, ref, fileID, false)
+			continue
+		}
+
+		if resumed {
+			item, err := p.dynamoDBOperations.GetTranscriptionItem(ctx, fileID)
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to check batch file", "batch", manifest.BatchID, logging.KeyFile, entry.Key, logging.KeyError, err)
+				failed++
+				continue
+			}
+			if item != nil && item.Status != model.StatusPending {
+				p.trackBatchFile(ctx, ref, fileID)
+				continue
+			}
+		}
+
+		if !dispatch {
+			entry.Bucket = fileBucket
+			if err := p.dispatchBatchFile(ctx, bucket, ref, fileID, entry); err != nil {
+				logging.FromContext(ctx).Error("Failed to dispatch batch file", "batch", manifest.BatchID, logging.KeyFile, entry.Key, logging.KeyError, err)
+				failed++
+			}
+			continue
+		}
+
+		logging.FromContext(ctx).Info("Processing batch file", "batch", manifest.BatchID, "index", i+1, "total", len(manifest.Files), logging.KeyBucket, fileBucket, logging.KeyFile, entry.Key)
+
+		err := p.ProcessFileWithOptions(ctx, fileBucket, entry.Key, opts)
+		if errors.Is(err, ErrDeferred) {
+			// The job counts when the scheduler has dispatched it and it finishes
+			continue
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to process batch file", "batch", manifest.BatchID, logging.KeyFile, entry.Key, logging.KeyError, err)
+			failed++
+		}
+		p.trackBatchFile(ctx, ref, fileID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files in batch %s failed", failed, len(manifest.Files), manifest.BatchID)
+	}
+
+	return nil
+}
+
+// dispatchBatchFile writes the dispatch manifest handing one file of a batch to its own invocation.
+// Its name is derived from the batch and the job, so a batch manifest delivered again rewrites the
+// same dispatch manifests.
+func (p *Processor) dispatchBatchFile(ctx context.Context, manifestBucket string, batch model.BatchRef, fileID string, entry model.ManifestEntry) error {
+	data, err := json.Marshal(model.JobManifest{
+		BatchID:  batch.BatchID,
+		TenantID: batch.TenantID,
+		Files:    []model.ManifestEntry{entry},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dispatch manifest: %w", err)
+	}
+
+	bucket := p.submissionBucket
+	if bucket == "" {
+		bucket = manifestBucket
+	}
+	key := model.DispatchManifestKey("batch-" + model.JobHash(batch.TenantID+"#"+batch.BatchID+"#"+fileID))
+	if err := p.s3Operations.UploadText(ctx, bucket, key, string(data)); err != nil {
+		return fmt.Errorf("failed to write dispatch manifest: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Dispatched batch file", "batch", batch.BatchID, logging.KeyBucket, entry.Bucket, logging.KeyFile, entry.Key,
+		"manifest", fmt.Sprintf("s3://%s/%s", bucket, key))
+	return nil
+}
+
+// processDispatchedFile runs the job handed over by a dispatch manifest that names no batch. A job the
+// tenant's quotas defer again is left to the next drain.
+func (p *Processor) processDispatchedFile(ctx context.Context, bucket, tenantID string, entry model.ManifestEntry) error {
+	fileBucket := entry.Bucket
+	if fileBucket == "" {
+		fileBucket = bucket
+	}
+	if p.crossTenant(tenantID, fileBucket, entry.Key) {
+		return fmt.Errorf("s3://%s/%s is not owned by the manifest's tenant", fileBucket, entry.Key)
+	}
+
+	err := p.ProcessFileWithOptions(ctx, fileBucket, entry.Key, entry.Options)
+	if errors.Is(err, ErrDeferred) {
+		return nil
+	}
+	return err
+}
+
+// trackBatchFile counts a batch file whose job has finished. A job that is still running or deferred counts
+// itself when it finishes; one claimed for another batch is told to report to this batch as well. A file
+// that got no job, e.g. because its size couldn't be read, counts as failed.
+func (p *Processor) trackBatchFile(ctx context.Context, batch model.BatchRef, fileID string) {
+	item, err := p.dynamoDBOperations.GetTranscriptionItem(ctx, fileID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check batch file", "batch", batch.BatchID, logging.KeyFile, fileID, logging.KeyError, err)
+		return
+	}
+
+	switch {
+	case item == nil:
+		p.recordBatchProgress(ctx, batch, fileID, false)
+	case item.Status.Terminal():
+		p.recordBatchProgress(ctx, batch, fileID, item.Status == model.StatusCompleted)
+	case !item.ReportsTo(batch):
+		err := p.dynamoDBOperations.UpdateTranscriptionItem(ctx, awsclient.NewItemUpdate(fileID).Append(awsclient.AttrWaitingBatches, batch))
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to add batch to job", "batch", batch.BatchID, logging.KeyFile, fileID, logging.KeyError, err)
+			return
+		}
+		// The job may have finished before the batch was added
+		p.recordBatchOutcome(ctx, fileID)
+	}
+}
+
+// recordBatchOutcome counts a finished job against its batch and the batches waiting on it. A job that
+// hasn't finished isn't counted.
+func (p *Processor) recordBatchOutcome(ctx context.Context, fileID string) {
+	item, err := p.dynamoDBOperations.GetTranscriptionItem(ctx, fileID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read job for batch progress", logging.KeyError, err)
+		return
+	}
+	if item == nil || !item.Status.Terminal() {
+		return
+	}
+
+	for _, batch := range item.Batches() {
+		p.recordBatchProgress(ctx, batch, fileID, item.Status == model.StatusCompleted)
+	}
+}
+
+// recordBatchProgress counts a job against a batch and notifies the batch callback when that finishes the
+// batch; failures are logged only
+func (p *Processor) recordBatchProgress(ctx context.Context, batch model.BatchRef, fileID string, succeeded bool) {
+	progress, finalized, err := p.dynamoDBOperations.RecordBatchProgress(ctx, batch.TenantID, batch.BatchID, fileID, succeeded)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to record batch progress", "batch", batch.BatchID, logging.KeyError, err)
+		return
+	}
+
+	if finalized && progress.Callback != "" {
+		if err := p.notifyBatchCallback(ctx, progress); err != nil {
+			logging.FromContext(ctx).Warn("Failed to notify batch callback", "batch", batch.BatchID, logging.KeyError, err)
+		}
+	}
+}
+
+// joinBatch has a job that was already recorded report to the batch now processing it. A batch that was
+// still waiting on the job keeps waiting; one it already finished for has counted it.
+func joinBatch(update *awsclient.ItemUpdate, existingItem *model.TranscriptionItem, opts model.JobOptions) {
+	batch := model.BatchRef{TenantID: opts.BatchTenantID, BatchID: opts.BatchID}
+	if batch.BatchID == "" || existingItem.ReportsTo(batch) {
+		return
+	}
+
+	update.Set(awsclient.AttrBatchID, batch.BatchID)
+	if batch.TenantID != "" {
+		update.Set(awsclient.AttrBatchTenantID, batch.TenantID)
+	} else {
+		update.Remove(awsclient.AttrBatchTenantID)
+	}
+	if existingItem.BatchID != "" && !existingItem.Status.Terminal() {
+		update.Append(awsclient.AttrWaitingBatches, model.BatchRef{TenantID: existingItem.BatchTenantID, BatchID: existingItem.BatchID})
+	}
+}
+
+// parseManifest decodes and validates a job manifest document. Only a dispatch manifest may hand over a
+// single job without a batch.
+func parseManifest(data []byte, dispatch bool) (*model.JobManifest, error) {
+	var manifest model.JobManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	if manifest.BatchID == "" && !(dispatch && len(manifest.Files) == 1) {
+		return nil, errors.New("batchId is required")
+	}
+
+	if len(manifest.Files) == 0 {
+		return nil, errors.New("manifest lists no files")
+	}
+
+	// Jobs are identified by their key, and the batch counts each job once, so a key listed twice would
+	// keep the batch from ever finishing
+	listed := map[string]bool{}
+	for i, entry := range manifest.Files {
+		if entry.Key == "" {
+			return nil, fmt.Errorf("files[%d]: key is required", i)
+		}
+		if listed[entry.Key] {
+			return nil, fmt.Errorf("files[%d]: key %s is listed more than once", i, entry.Key)
+		}
+		listed[entry.Key] = true
+	}
+
+	return &manifest, nil
+}
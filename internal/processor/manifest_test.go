@@ -0,0 +1,321 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/notify"
+)
+
+func TestProcessManifest(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		tableName:          "test-table",
+	}
+	processor.SetSubmissionBucket("submissions")
+	processor.SetBatchCallbacks([]byte("signing-key"), []string{"hooks.example.com"})
+
+	ctx := context.Background()
+	manifest := `{
+		"batchId": "batch-1",
+		"callback": "https://hooks.example.com/transcription",
+		"files": [
+			{"key": "audio/one.aac", "options": {"outputBucket": "custom-output", "metadata": {"caller": "ingest"}}},
+			{"bucket": "other-bucket", "key": "audio/two.aac"}
+		]
+	}`
+	mockS3Ops.On("ReadObject", mock.Anything, "test-bucket", "jobs/batch-1.manifest.json").Return([]byte(manifest), nil)
+
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.MatchedBy(func(batch *model.BatchItem) bool {
+		return batch.BatchID == "batch-1" &&
+			batch.TotalFiles == 2 &&
+			batch.BatchStatus == model.BatchStatusInProgress &&
+			batch.Callback == "https://hooks.example.com/transcription" &&
+			batch.ManifestLocation == "s3://test-bucket/jobs/batch-1.manifest.json" &&
+			batch.ExpiresAt == 0
+	})).Return(nil)
+
+	// Each file is handed to its own invocation with a dispatch manifest naming the batch and the file's bucket
+	dispatched := map[string]model.JobManifest{}
+	mockS3Ops.On("UploadText", mock.Anything, "submissions", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		var m model.JobManifest
+		assert.NoError(t, json.Unmarshal([]byte(args.String(3)), &m))
+		assert.True(t, model.IsDispatchManifest(args.String(2)))
+		dispatched[args.String(2)] = m
+	}).Return(nil)
+
+	err := processor.ProcessManifest(ctx, "test-bucket", "jobs/batch-1.manifest.json")
+
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockElevenLabsClient.AssertNotCalled(t, "TranscribeAudio", mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertNotCalled(t, "CreateTranscriptionItem", mock.Anything, mock.Anything)
+	if assert.Len(t, dispatched, 2) {
+		var files []model.ManifestEntry
+		for _, m := range dispatched {
+			assert.Equal(t, "batch-1", m.BatchID)
+			assert.Empty(t, m.Callback)
+			files = append(files, m.Files...)
+		}
+		assert.ElementsMatch(t, []model.ManifestEntry{
+			{Bucket: "test-bucket", Key: "audio/one.aac", Options: model.JobOptions{OutputBucket: "custom-output", Metadata: map[string]string{"caller": "ingest"}}},
+			{Bucket: "other-bucket", Key: "audio/two.aac"},
+		}, files)
+	}
+
+	// Delivered again, the manifest rewrites the same dispatch manifests
+	keys := map[string]bool{}
+	for key := range dispatched {
+		keys[key] = true
+	}
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Unset()
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Return(awsclient.ErrBatchExists)
+	mockDynamoDBOps.On("GetBatchItem", mock.Anything, "", "batch-1").Return(&model.BatchItem{BatchID: "batch-1", BatchStatus: model.BatchStatusInProgress}, nil)
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, mock.Anything).Return(nil, nil)
+
+	assert.NoError(t, processor.ProcessManifest(ctx, "test-bucket", "jobs/batch-1.manifest.json"))
+	assert.Len(t, dispatched, 2)
+	for key := range dispatched {
+		assert.True(t, keys[key], key)
+	}
+}
+
+func TestProcessManifest_DispatchedFile(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		tableName:          "test-table",
+	}
+
+	// Callback server records the final batch summary and its signature
+	var notified model.BatchItem
+	var signature, timestamp string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &notified))
+		timestamp = r.Header.Get(notify.TimestampHeader)
+		signature = notify.Sign([]byte("signing-key"), timestamp, body)
+		assert.Equal(t, signature, r.Header.Get(notify.SignatureHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defaultClient := callbackHTTPClient
+	callbackHTTPClient = server.Client()
+	defer func() { callbackHTTPClient = defaultClient }()
+	processor.SetBatchCallbacks([]byte("signing-key"), []string{"127.0.0.1"})
+
+	ctx := context.Background()
+	manifest := `{"batchId": "batch-1", "files": [{"bucket": "test-bucket", "key": "audio/one.aac", "options": {"outputBucket": "custom-output", "metadata": {"caller": "ingest"}}}]}`
+	mockS3Ops.On("ReadObject", mock.Anything, "submissions", "jobs/dispatch/batch-one.manifest.json").Return([]byte(manifest), nil)
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Return(awsclient.ErrBatchExists)
+	mockDynamoDBOps.On("GetBatchItem", mock.Anything, "", "batch-1").Return(&model.BatchItem{BatchID: "batch-1", BatchStatus: model.BatchStatusInProgress, TotalFiles: 2}, nil)
+
+	// The file completes, carries its manifest options and is counted once it has finished
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "audio/one.aac").Return(nil, nil).Twice()
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "audio/one.aac").Return(&model.TranscriptionItem{Status: model.StatusCompleted, BatchID: "batch-1"}, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.FileIdentifier == "audio/one.aac" && item.BatchID == "batch-1" && item.Metadata["caller"] == "ingest"
+	})).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "test-bucket", "audio/one.aac", 3600).Return("https://presigned-one", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-one").Return(&model.ElevenLabsResponse{Text: "one", Success: true}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "custom-output", "transcripts/audio/one.aac.txt", "one").Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate("audio/one.aac", model.StatusTranscribing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate("audio/one.aac", model.StatusPostprocessing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate("audio/one.aac", model.StatusCompleted,
+		awsclient.AttrTranscriptText, "one",
+		awsclient.AttrOutputLocation, "s3://custom-output/transcripts/audio/one.aac.txt")).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, "audio/one.aac", mock.Anything).Return(nil)
+
+	// It's the last file of the batch, so the callback is notified
+	mockDynamoDBOps.On("RecordBatchProgress", mock.Anything, "", "batch-1", "audio/one.aac", true).Return(&model.BatchItem{
+		BatchID:        "batch-1",
+		BatchStatus:    model.BatchStatusCompleted,
+		Callback:       server.URL,
+		TotalFiles:     2,
+		CompletedFiles: 2,
+	}, true, nil)
+
+	err := processor.ProcessManifest(ctx, "submissions", "jobs/dispatch/batch-one.manifest.json")
+
+	assert.NoError(t, err)
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockElevenLabsClient.AssertExpectations(t)
+	assert.Equal(t, "batch-1", notified.BatchID)
+	assert.Equal(t, model.BatchStatusCompleted, notified.BatchStatus)
+	assert.Equal(t, 2, notified.CompletedFiles)
+	assert.NotEmpty(t, timestamp)
+}
+
+func TestProcessManifest_CallbackNotAllowed(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	processor := &Processor{
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	ctx := context.Background()
+
+	// Without allowed hosts no callback can be notified
+	mockS3Ops.On("ReadObject", mock.Anything, "test-bucket", "jobs/b.manifest.json").Return([]byte(`{"batchId":"b","callback":"https://hooks.example.com/t","files":[{"key":"a.aac"}]}`), nil).Once()
+	err := processor.ProcessManifest(ctx, "test-bucket", "jobs/b.manifest.json")
+	assert.EqualError(t, err, "invalid manifest s3://test-bucket/jobs/b.manifest.json: batch callbacks are not enabled")
+
+	processor.SetBatchCallbacks([]byte("signing-key"), []string{"hooks.example.com"})
+	for callback, want := range map[string]string{
+		"http://hooks.example.com/t":       "callback URL must use https",
+		"https://169.254.169.254/t":        `callback host "169.254.169.254" is not allowed`,
+		"https://hooks.example.com.evil/t": `callback host "hooks.example.com.evil" is not allowed`,
+	} {
+		mockS3Ops.On("ReadObject", mock.Anything, "test-bucket", "jobs/b.manifest.json").Return([]byte(`{"batchId":"b","callback":"`+callback+`","files":[{"key":"a.aac"}]}`), nil).Once()
+		err := processor.ProcessManifest(ctx, "test-bucket", "jobs/b.manifest.json")
+		assert.EqualError(t, err, "invalid manifest s3://test-bucket/jobs/b.manifest.json: "+want)
+	}
+
+	mockDynamoDBOps.AssertNotCalled(t, "CreateBatchItem", mock.Anything, mock.Anything)
+}
+
+func TestProcessManifest_DuplicateBatch(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+
+	processor := &Processor{
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+
+	ctx := context.Background()
+	mockS3Ops.On("ReadObject", mock.Anything, "test-bucket", "jobs/b.manifest.json").Return([]byte(`{"batchId":"b","files":[{"key":"a.aac"}]}`), nil)
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Return(awsclient.ErrBatchExists)
+	mockDynamoDBOps.On("GetBatchItem", mock.Anything, "", "b").Return(&model.BatchItem{BatchID: "b", BatchStatus: model.BatchStatusCompleted}, nil)
+
+	err := processor.ProcessManifest(ctx, "test-bucket", "jobs/b.manifest.json")
+
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertNotCalled(t, "GetTranscriptionItem", mock.Anything, mock.Anything)
+}
+
+func TestProcessManifest_ResumesBatch(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+
+	processor := &Processor{
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+
+	ctx := context.Background()
+	manifest := `{"batchId":"b","files":[{"key":"done.aac"},{"key":"running.aac"}]}`
+	mockS3Ops.On("ReadObject", mock.Anything, "test-bucket", "jobs/b.manifest.json").Return([]byte(manifest), nil)
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Return(awsclient.ErrBatchExists)
+	mockDynamoDBOps.On("GetBatchItem", mock.Anything, "", "b").Return(&model.BatchItem{BatchID: "b", BatchStatus: model.BatchStatusInProgress, TotalFiles: 2}, nil)
+
+	// A finished job is counted without being processed again
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "done.aac").Return(&model.TranscriptionItem{Status: model.StatusCompleted, BatchID: "b"}, nil)
+	mockDynamoDBOps.On("RecordBatchProgress", mock.Anything, "", "b", "done.aac", true).Return(&model.BatchItem{BatchID: "b", TotalFiles: 2, CompletedFiles: 1}, false, nil)
+
+	// A job running for another batch isn't counted yet, but will report to this batch when it finishes
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "running.aac").Return(&model.TranscriptionItem{Status: model.StatusTranscribing, BatchID: "a"}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		return update.FileIdentifier() == "running.aac" && update.NewStatus() == ""
+	})).Return(nil)
+
+	err := processor.ProcessManifest(ctx, "test-bucket", "jobs/b.manifest.json")
+
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockDynamoDBOps.AssertNotCalled(t, "CreateTranscriptionItem", mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertNotCalled(t, "RecordBatchProgress", mock.Anything, "", "b", "running.aac", mock.Anything)
+}
+
+func TestRecordBatchOutcome(t *testing.T) {
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	processor := &Processor{dynamoDBOperations: mockDynamoDBOps}
+	ctx := context.Background()
+
+	// A job that hasn't finished isn't counted
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "call.mp3").Return(&model.TranscriptionItem{Status: model.StatusPending, BatchID: "b1"}, nil).Once()
+	processor.recordBatchOutcome(ctx, "call.mp3")
+	mockDynamoDBOps.AssertNotCalled(t, "RecordBatchProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// A finished job counts for its batch and the batches waiting on it
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "call.mp3").Return(&model.TranscriptionItem{
+		Status:         model.StatusFailed,
+		BatchID:        "b1",
+		WaitingBatches: []model.BatchRef{{BatchID: "b2"}},
+	}, nil)
+	mockDynamoDBOps.On("RecordBatchProgress", mock.Anything, "", "b1", "call.mp3", false).Return(&model.BatchItem{BatchID: "b1"}, false, nil).Once()
+	mockDynamoDBOps.On("RecordBatchProgress", mock.Anything, "", "b2", "call.mp3", false).Return(&model.BatchItem{BatchID: "b2"}, false, nil).Once()
+	processor.recordBatchOutcome(ctx, "call.mp3")
+
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestParseManifest(t *testing.T) {
+	_, err := parseManifest([]byte(`not json`), false)
+	assert.Error(t, err)
+
+	_, err = parseManifest([]byte(`{"files":[{"key":"a.aac"}]}`), false)
+	assert.EqualError(t, err, "batchId is required")
+
+	// Only a dispatch manifest hands over a job that isn't part of a batch, and only one
+	_, err = parseManifest([]byte(`{"files":[{"key":"a.aac"}]}`), true)
+	assert.NoError(t, err)
+	_, err = parseManifest([]byte(`{"files":[{"key":"a.aac"},{"key":"b.aac"}]}`), true)
+	assert.EqualError(t, err, "batchId is required")
+
+	_, err = parseManifest([]byte(`{"batchId":"b","files":[]}`), false)
+	assert.EqualError(t, err, "manifest lists no files")
+
+	_, err = parseManifest([]byte(`{"batchId":"b","files":[{"key":""}]}`), false)
+	assert.EqualError(t, err, "files[0]: key is required")
+
+	// A file listed twice would only be counted once, so the batch could never finish
+	_, err = parseManifest([]byte(`{"batchId":"b","files":[{"key":"a.aac"},{"bucket":"other","key":"a.aac"}]}`), false)
+	assert.EqualError(t, err, "files[1]: key a.aac is listed more than once")
+
+	manifest, err := parseManifest([]byte(`{"batchId":"b","files":[{"key":"a.aac","options":{"outputBucket":"out"}}]}`), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "out", manifest.Files[0].Options.OutputBucket)
+}
+
+func TestJoinBatch(t *testing.T) {
+	// A job claimed for a tenant's batch records the batch's partition
+	existing := &model.TranscriptionItem{FileIdentifier: "tenant#support#support/call.mp3", Status: model.StatusFailed}
+	update := awsclient.NewItemUpdate(existing.FileIdentifier)
+	joinBatch(update, existing, model.JobOptions{BatchID: "b", BatchTenantID: "support"})
+
+	batchID, _ := update.Value(awsclient.AttrBatchID)
+	tenantID, _ := update.Value(awsclient.AttrBatchTenantID)
+	assert.Equal(t, "b", batchID)
+	assert.Equal(t, "support", tenantID)
+
+	// A job already reporting to the batch is left alone
+	existing.BatchID, existing.BatchTenantID = "b", "support"
+	update = awsclient.NewItemUpdate(existing.FileIdentifier)
+	joinBatch(update, existing, model.JobOptions{BatchID: "b", BatchTenantID: "support"})
+
+	_, ok := update.Value(awsclient.AttrBatchID)
+	assert.False(t, ok)
+}
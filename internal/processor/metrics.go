@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yourusername/transcription-service/internal/metrics"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Failure reasons reported as the JobsFailed reason dimension
+const (
+	reasonPresign     = "presign"
+	reasonProvider    = "provider"
+	reasonPostprocess = "postprocess"
+	reasonStorage     = "storage"
+	reasonStatus      = "status"
+)
+
+// SetMetrics sets where pipeline metrics are recorded; by default they are discarded
+func (p *Processor) SetMetrics(recorder metrics.Recorder) {
+	p.metrics = recorder
+}
+
+// count adds one to a metric
+func (p *Processor) count(ctx context.Context, name string, dims metrics.Dimensions) {
+	p.put(ctx, name, 1, metrics.Count, dims)
+}
+
+func (p *Processor) put(ctx context.Context, name string, value float64, unit metrics.Unit, dims metrics.Dimensions) {
+	if p.metrics != nil {
+		p.metrics.Put(ctx, name, value, unit, dims)
+	}
+}
+
+// jobFailed counts a failed attempt by reason
+func (p *Processor) jobFailed(ctx context.Context, reason string) {
+	p.count(ctx, metrics.JobsFailed, metrics.Dimensions{metrics.DimReason: reason})
+}
+
+// jobCompleted records the size of a finished job
+func (p *Processor) jobCompleted(ctx context.Context, resp *model.ElevenLabsResponse) {
+	p.count(ctx, metrics.JobsCompleted, nil)
+	p.put(ctx, metrics.TranscriptLength, float64(len(resp.Text)), metrics.Bytes, nil)
+	if duration := audioSeconds(resp); duration > 0 {
+		p.put(ctx, metrics.AudioDuration, duration, metrics.Seconds, nil)
+	}
+}
+
+// audioSeconds returns the length of the transcribed audio as reported by the provider. For providers
+// that don't report it, the end of the last timed word is the best estimate available.
+func audioSeconds(resp *model.ElevenLabsResponse) float64 {
+	if resp.AudioDuration > 0 {
+		return resp.AudioDuration
+	}
+	return audioDuration(resp.Words)
+}
+
+// audioDuration estimates the audio length in seconds from the end of the last timed word
+func audioDuration(words []model.Word) float64 {
+	var end float64
+	for _, w := range words {
+		if w.End > end {
+			end = w.End
+		}
+	}
+	return end
+}
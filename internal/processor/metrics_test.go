@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/metrics"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestProcessFile_Metrics(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	recorder := metrics.NewMemory()
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetMetrics(recorder)
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+
+	// A retried job that completes
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "calls/ok.mp3").Return(&model.TranscriptionItem{
+		FileIdentifier: "calls/ok.mp3",
+		Status:         model.StatusFailed,
+		History:        []model.AttemptRecord{{Status: model.StatusFailed}},
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, mock.Anything, 3600).Return("https://presigned-url", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Hello there.",
+		Words:   []model.Word{{Text: "Hello", Start: 0.2, End: 0.6}, {Text: "there.", Start: 0.7, End: 1.5}},
+		Success: true,
+	}, nil).Once()
+
+	assert.NoError(t, processor.ProcessFile(ctx, bucket, "calls/ok.mp3"))
+
+	job := metrics.Dimensions{metrics.DimPrefix: "calls/", metrics.DimProvider: "elevenlabs"}
+	assert.Equal(t, float64(1), recorder.Sum(metrics.JobsStarted, job))
+	assert.Equal(t, float64(1), recorder.Sum(metrics.JobRetries, job))
+	assert.Equal(t, float64(1), recorder.Sum(metrics.JobsCompleted, job))
+	assert.Equal(t, 1.5, recorder.Sum(metrics.AudioDuration, job))
+	assert.Equal(t, float64(len("Hello there.")), recorder.Sum(metrics.TranscriptLength, job))
+
+	// A new job the provider fails
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "voicemail.mp3").Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItemWithEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(nil, errors.New("boom")).Once()
+
+	assert.Error(t, processor.ProcessFile(ctx, bucket, "voicemail.mp3"))
+	assert.Equal(t, float64(1), recorder.Sum(metrics.JobsFailed, metrics.Dimensions{metrics.DimPrefix: "/", metrics.DimReason: reasonProvider}))
+	assert.Zero(t, recorder.Sum(metrics.JobRetries, metrics.Dimensions{metrics.DimPrefix: "/"}))
+
+	// Duplicate deliveries of a finished job are skipped
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "calls/done.mp3").Return(&model.TranscriptionItem{
+		Status: model.StatusCompleted,
+	}, nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, bucket, "calls/done.mp3"))
+	assert.Equal(t, float64(1), recorder.Sum(metrics.SkippedDuplicates, metrics.Dimensions{metrics.DimPrefix: "calls/"}))
+}
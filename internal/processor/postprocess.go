@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/tracing"
+)
+
+// SetPostProcessing sets the stages run on every transcript before it is stored
+func (p *Processor) SetPostProcessing(pipeline *postprocess.Pipeline) {
+	p.pipeline = pipeline
+}
+
+// postProcess runs the pipeline over a provider response and returns the processed copy, the document
+// holding the attributes stages recorded, and each stage's outcome
+func (p *Processor) postProcess(ctx context.Context, fileID string, source model.JobSource, resp *model.ElevenLabsResponse, vocabulary *model.Vocabulary) (*model.ElevenLabsResponse, *postprocess.Document, []model.StageRun, error) {
+	doc := &postprocess.Document{
+		FileIdentifier: fileID,
+		Source:         source,
+		Vocabulary:     vocabulary,
+		Transcript: &model.Transcript{
+			FileIdentifier:      fileID,
+			Text:                resp.Text,
+			LanguageCode:        resp.LanguageCode,
+			LanguageProbability: resp.LanguageProbability,
+			Words:               resp.Words,
+		},
+	}
+	if p.pipeline == nil || p.pipeline.Len() == 0 {
+		return resp, doc, nil, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "PostProcess")
+	runs, err := p.pipeline.Run(ctx, doc)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, nil, runs, err
+	}
+
+	processed := *resp
+	processed.Text = doc.Transcript.Text
+	processed.Words = doc.Transcript.Words
+	return &processed, doc, runs, nil
+}
+
+// recordAttributes adds the attributes stages recorded to the completion update
+func recordAttributes(update *awsclient.ItemUpdate, doc *postprocess.Document) {
+	names, values := doc.Attributes()
+	for _, name := range names {
+		if values[name] == nil {
+			update.Remove(name)
+		} else {
+			update.Set(name, values[name])
+		}
+	}
+}
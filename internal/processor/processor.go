@@ -2,27 +2,91 @@ package processor
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/yourusername/transcription-service/internal/awsclient"
 	"github.com/yourusername/transcription-service/internal/elevenlabs"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/metrics"
 	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/settings"
+	"github.com/yourusername/transcription-service/internal/tenant"
+	"github.com/yourusername/transcription-service/internal/tracing"
+	"github.com/yourusername/transcription-service/internal/usage"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// S3API is the subset of S3 operations used by the processor
+type S3API interface {
+	DownloadFile(ctx context.Context, bucket, key string) (string, error)
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+	GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error)
+	UploadText(ctx context.Context, bucket, key, content string) error
+	TagObject(ctx context.Context, bucket, key string, tags map[string]string) error
+	UploadEncryptedText(ctx context.Context, bucket, key, content, kmsKeyID string) error
+	ObjectSize(ctx context.Context, bucket, key string) (int64, error)
+}
+
+// DynamoDBAPI is the subset of DynamoDB operations used by the processor
+type DynamoDBAPI interface {
+	CreateTranscriptionItem(ctx context.Context, item *model.TranscriptionItem) error
+	UpdateTranscriptionItem(ctx context.Context, update *awsclient.ItemUpdate) error
+	GetTranscriptionItem(ctx context.Context, fileIdentifier string) (*model.TranscriptionItem, error)
+	CreateBatchItem(ctx context.Context, batch *model.BatchItem) error
+	GetBatchItem(ctx context.Context, tenantID, batchID string) (*model.BatchItem, error)
+	RecordBatchProgress(ctx context.Context, tenantID, batchID, fileID string, succeeded bool) (*model.BatchItem, bool, error)
+	UpdateTranscriptionLanguage(ctx context.Context, fileIdentifier, detectedLanguage string, probability float64, route model.LanguageRoute) error
+	AppendAttempt(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error
+	UpdateTranscriptionItemWithEvent(ctx context.Context, update *awsclient.ItemUpdate, event *model.JobEvent) error
+}
+
+// TranscriptionClient sends audio to a speech-to-text provider
+type TranscriptionClient interface {
+	TranscribeAudio(ctx context.Context, audioURL string) (*model.ElevenLabsResponse, error)
+	TranscribeAudioWithOptions(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error)
+}
+
 // Processor handles the transcription business logic
 type Processor struct {
 	s3Client            *s3.Client
 	dynamoDBClient      *dynamodb.Client
-	elevenlabsClient    *elevenlabs.Client
-	s3Operations        *awsclient.S3Operations
-	dynamoDBOperations  *awsclient.DynamoDBOperations
+	elevenlabsClient    TranscriptionClient
+	s3Operations        S3API
+	dynamoDBOperations  DynamoDBAPI
 	tableName           string
 	outputBucket        string
+	providers           map[string]TranscriptionClient
+	supportedLanguages  map[string]bool
+	languageRoutes      map[string]model.LanguageRoute
+	publishEvents       bool
+	artifactsBucket     string
+	inlineLimit         int
+	retention           []model.RetentionPolicy
+	pipeline            *postprocess.Pipeline
+	vocabularies        *vocabularies
+	encrypter           *envelope.Encrypter
+	metrics             metrics.Recorder
+	ledger              *usage.Ledger
+	tenants             *tenant.Registry
+	tenantClients       ClientFactory
+	quotas              *quota.Limiter
+	settings            *settings.Cache
+	submissionBucket    string
+	callbacks           *batchCallbacks
+	clientMu            sync.Mutex
+	clientCache         map[string]TranscriptionClient
 }
 
 // NewProcessor creates a new processor instance
@@ -41,15 +105,89 @@ func NewProcessor(
 		dynamoDBOperations: awsclient.NewDynamoDBOperations(dynamoDBClient, tableName),
 		tableName:          tableName,
 		outputBucket:       outputBucket,
+		providers:          map[string]TranscriptionClient{DefaultProviderName: elevenlabsClient},
 	}
 }
 
+// NewProcessorWithOperations creates a processor on other storage than the AWS clients, e.g. the local
+// files of awsclient.LocalS3 and awsclient.LocalStore
+func NewProcessorWithOperations(
+	s3Operations S3API,
+	dynamoDBOperations DynamoDBAPI,
+	client TranscriptionClient,
+	outputBucket string,
+) *Processor {
+	return &Processor{
+		elevenlabsClient:   client,
+		s3Operations:       s3Operations,
+		dynamoDBOperations: dynamoDBOperations,
+		outputBucket:       outputBucket,
+		providers:          map[string]TranscriptionClient{DefaultProviderName: client},
+	}
+}
+
+// SetPublishEvents enables completion events. Events are written to the table's outbox together
+// with the final status and delivered by the stream dispatcher.
+func (p *Processor) SetPublishEvents(enabled bool) {
+	p.publishEvents = enabled
+}
+
 // ProcessFile processes an audio file from S3 for transcription
 func (p *Processor) ProcessFile(ctx context.Context, bucket, key string) error {
+	return p.ProcessFileWithOptions(ctx, bucket, key, model.JobOptions{})
+}
+
+// ProcessFileWithOptions processes an audio file from S3 using per-file job options. It returns
+// ErrDeferred when the tenant's quotas deferred the job.
+func (p *Processor) ProcessFileWithOptions(ctx context.Context, bucket, key string, opts model.JobOptions) error {
+	ctx, span := tracing.Start(ctx, "ProcessFile", attribute.String("file", key), attribute.String("bucket", bucket))
+	err := p.processFile(ctx, bucket, key, opts)
+	if errors.Is(err, ErrDeferred) {
+		tracing.End(span, nil)
+		return err
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// processFile runs one attempt of a job; the stages that call other services get their own spans
+func (p *Processor) processFile(ctx context.Context, bucket, key string, opts model.JobOptions) error {
 	startTime := time.Now()
 	fileID := key // Using the S3 key as the file identifier
+	ctx = logging.With(ctx, logging.KeyFile, fileID, logging.KeyBucket, bucket)
 	
-	log.Printf("Starting processing of file: %s", fileID)
+	// With tenants, a job belongs to the tenant owning the audio and is stored under its partition
+	var owner *tenant.Tenant
+	var tenantID string
+	if p.tenants != nil {
+		var ok bool
+		if owner, ok = p.tenants.Resolve(bucket, key); !ok {
+			logging.FromContext(ctx).Warn("No tenant owns the file, skipping")
+			return nil
+		}
+		tenantID = owner.ID
+		fileID = model.TenantJobID(owner.ID, key)
+		opts = owner.JobOptions(opts)
+		ctx = tenant.NewContext(ctx, owner)
+		ctx = logging.With(ctx, logging.KeyTenant, owner.ID)
+	}
+	source := model.JobSource{TenantID: tenantID, Bucket: bucket, Key: key}
+	
+	// Runtime settings are read once per attempt, so a change never applies halfway through a job
+	current := p.settings.Get(ctx)
+	if current.Version != "" {
+		ctx = logging.With(ctx, logging.KeyConfigVersion, current.Version)
+	}
+	opts = current.JobOptions(opts)
+	if owner != nil {
+		owner = current.Tenant(owner)
+	}
+	ctx = metrics.WithDimensions(ctx, metrics.Dimensions{
+		metrics.DimPrefix:   metrics.Prefix(key),
+		metrics.DimProvider: providerName(model.LanguageRoute{Provider: opts.Provider}),
+	})
+	
+	logging.FromContext(ctx).Info("Starting processing")
 	
 	// Check if this file has already been processed
 	existingItem, err := p.dynamoDBOperations.GetTranscriptionItem(ctx, fileID)
@@ -57,56 +195,168 @@ func (p *Processor) ProcessFile(ctx context.Context, bucket, key string) error {
 		return fmt.Errorf("error checking for existing transcription: %w", err)
 	}
 	
-	if existingItem != nil && (existingItem.Status == model.StatusCompleted || existingItem.Status == model.StatusInProgress) {
-		log.Printf("File %s is already processed or in progress, skipping", fileID)
+	// Only new, pending and failed jobs can be claimed
+	if existingItem != nil && !model.CanTransition(existingItem.Status, model.StatusClaimed) {
+		switch existingItem.Status {
+		case model.StatusCancelled:
+			logging.FromContext(ctx).Info("File was cancelled, skipping")
+		case model.StatusRejected:
+			logging.FromContext(ctx).Info("File was rejected, skipping")
+		default:
+			logging.FromContext(ctx).Info("File is already processed or in progress, skipping", "status", existingItem.Status)
+			p.count(ctx, metrics.SkippedDuplicates, nil)
+		}
 		return nil
 	}
 	
-	// Create or update the DynamoDB item to indicate processing has started
+	// Inputs that can never be transcribed are rejected rather than failed so nothing retries them
+	if !model.IsAudioFile(key) {
+		return p.reject(ctx, fileID, tenantID, bucket, key, opts, existingItem, fmt.Sprintf("unsupported audio format %q", filepath.Ext(key)))
+	}
+	if current.MaxAudioBytes > 0 {
+		size, err := p.s3Operations.ObjectSize(ctx, bucket, key)
+		if err != nil {
+			return fmt.Errorf("failed to check audio size: %w", err)
+		}
+		if size > current.MaxAudioBytes {
+			return p.reject(ctx, fileID, tenantID, bucket, key, opts, existingItem, fmt.Sprintf("audio is %d bytes, over the limit of %d", size, current.MaxAudioBytes))
+		}
+	}
+	
+	// A tenant over its quotas has the job deferred until the scheduler dispatches it again
+	if owner != nil && p.quotas != nil {
+		decision, err := p.quotas.Admit(ctx, owner, fileID)
+		if err != nil {
+			return fmt.Errorf("failed to check tenant quotas: %w", err)
+		}
+		if !decision.Admitted {
+			return p.deferJob(ctx, fileID, tenantID, bucket, key, opts, existingItem, decision)
+		}
+		defer p.releaseSlot(ctx, owner, fileID)
+	}
+	
+	// Retention counts from the latest attempt
+	policy, retained := model.RetentionFor(p.retention, source)
+	
+	// Create or update the DynamoDB item to claim the job; the state machine lets only one processor win
+	claimCtx, claimSpan := tracing.Start(ctx, "Claim")
 	if existingItem == nil {
 		// Create new item
 		item := &model.TranscriptionItem{
 			FileIdentifier: fileID,
-			Status:         model.StatusInProgress,
+			TenantID:       tenantID,
+			Status:         model.StatusClaimed,
 			SourceBucket:   bucket,
 			SourceKey:      key,
+			BatchID:        opts.BatchID,
+			BatchTenantID:  opts.BatchTenantID,
+			Metadata:       opts.Metadata,
+			LanguageHint:   opts.Language,
+			ConfigVersion:  current.Version,
 		}
-		
-		err = p.dynamoDBOperations.CreateTranscriptionItem(ctx, item)
-		if err != nil {
-			return fmt.Errorf("failed to create DynamoDB item: %w", err)
+		if retained {
+			item.ExpiresAt = policy.ExpiresAt(startTime)
 		}
+		
+		err = p.dynamoDBOperations.CreateTranscriptionItem(claimCtx, item)
 	} else {
-		// Update existing item
-		err = p.dynamoDBOperations.UpdateTranscriptionItemStatus(
-			ctx, fileID, model.StatusInProgress, "", "", "", 0)
-		if err != nil {
-			return fmt.Errorf("failed to update DynamoDB item status: %w", err)
+		// Update existing item, clearing the error left by a previous attempt and any deferral
+		claim := awsclient.NewItemUpdate(fileID).
+			Status(model.StatusClaimed).
+			Remove(awsclient.AttrErrorMessage, awsclient.AttrDeferredUntil, awsclient.AttrDeferredReason, awsclient.AttrDeferredOptions)
+		if retained {
+			claim.Set(awsclient.AttrExpiresAt, policy.ExpiresAt(startTime))
 		}
+		if current.Version != "" {
+			claim.Set(awsclient.AttrConfigVersion, current.Version)
+		}
+		joinBatch(claim, existingItem, opts)
+		err = p.dynamoDBOperations.UpdateTranscriptionItem(claimCtx, claim)
+	}
+	tracing.End(claimSpan, err)
+	if errors.Is(err, model.ErrInvalidTransition) {
+		logging.FromContext(ctx).Info("File was claimed by another invocation, skipping", logging.KeyError, err)
+		p.count(ctx, metrics.SkippedDuplicates, nil)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to claim DynamoDB item: %w", err)
+	}
+	
+	// However the attempt ends, its batches count the job once it has finished
+	defer p.recordBatchOutcome(ctx, fileID)
+
+	// Every claimed attempt is recorded in the item's history, whatever its outcome
+	attemptNumber := 1
+	if existingItem != nil {
+		attemptNumber = existingItem.Attempts() + 1
+	}
+	ctx = logging.With(ctx, logging.KeyAttempt, attemptNumber)
+	p.count(ctx, metrics.JobsStarted, nil)
+	if attemptNumber > 1 {
+		p.count(ctx, metrics.JobRetries, nil)
+	}
+	
+	attempt := model.AttemptRecord{
+		Status:    model.StatusFailed,
+		StartedAt: startTime.UTC(),
+		Provider:  providerName(model.LanguageRoute{Provider: opts.Provider}),
+		ModelID:   opts.ModelID,
 	}
+	defer p.recordAttempt(ctx, fileID, &attempt)
 	
 	// Generate a pre-signed URL for the audio file
-	audioURL, err := p.s3Operations.GeneratePresignedURL(ctx, bucket, key, 3600) // 1 hour expiration
+	presignCtx, presignSpan := tracing.Start(ctx, "Presign")
+	audioURL, err := p.s3Operations.GeneratePresignedURL(presignCtx, bucket, key, 3600) // 1 hour expiration
+	tracing.End(presignSpan, err)
 	if err != nil {
+		attempt.Error = fmt.Sprintf("Failed to generate pre-signed URL: %v", err)
+		p.jobFailed(ctx, reasonPresign)
+		
+		event := newJobEvent(fileID, bucket, key, opts, model.StatusFailed, startTime)
+		event.Error = attempt.Error
+		
 		// Update DynamoDB to indicate failure
-		updateErr := p.dynamoDBOperations.UpdateTranscriptionItemStatus(
-			ctx, fileID, model.StatusFailed, "", "", fmt.Sprintf("Failed to generate pre-signed URL: %v", err), 0)
+		updateErr := p.finishAttempt(ctx, failure(fileID, attempt.Error), event)
 		if updateErr != nil {
-			log.Printf("Failed to update DynamoDB item status: %v", updateErr)
+			logging.FromContext(ctx).Error("Failed to update DynamoDB item status", logging.KeyError, updateErr)
 		}
 		
 		return fmt.Errorf("failed to generate pre-signed URL: %w", err)
 	}
 	
+	if err := p.advance(ctx, fileID, model.StatusTranscribing); err != nil {
+		return p.abandon(ctx, fileID, &attempt, err)
+	}
+	
+	// A vocabulary that can't be loaded only costs accuracy, so carry on without it
+	var vocabulary *model.Vocabulary
+	if current.Enabled(settings.FlagVocabularies) {
+		vocabulary, err = p.vocabularyFor(ctx, source)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Transcribing without the custom vocabulary", logging.KeyError, err)
+		}
+	}
+	
 	// Call ElevenLabs API for transcription
-	log.Printf("Sending audio to ElevenLabs API for transcription")
-	transcriptionResp, err := p.elevenlabsClient.TranscribeAudio(ctx, audioURL)
+	logging.FromContext(ctx).Info("Sending audio for transcription")
+	transcriptionResp, route, replaced, err := p.transcribe(ctx, audioURL, opts.Language, model.LanguageRoute{Provider: opts.Provider, ModelID: opts.ModelID}, vocabulary)
+	attempt.Provider = providerName(route)
+	attempt.ModelID = route.ModelID
+	ctx = logging.With(ctx, logging.KeyProvider, attempt.Provider)
+	ctx = metrics.WithDimensions(ctx, metrics.Dimensions{metrics.DimProvider: attempt.Provider})
 	if err != nil {
+		attempt.Error = fmt.Sprintf("Transcription API error: %v", err)
+		p.jobFailed(ctx, reasonProvider)
+		p.recordUsage(ctx, fileID, tenantID, key, attemptNumber, replaced, nil)
+		
+		event := newJobEvent(fileID, bucket, key, opts, model.StatusFailed, startTime)
+		event.Error = attempt.Error
+		
 		// Update DynamoDB to indicate failure
-		updateErr := p.dynamoDBOperations.UpdateTranscriptionItemStatus(
-			ctx, fileID, model.StatusFailed, "", "", fmt.Sprintf("Transcription API error: %v", err), 0)
+		updateErr := p.finishAttempt(ctx, failure(fileID, attempt.Error), event)
 		if updateErr != nil {
-			log.Printf("Failed to update DynamoDB item status: %v", updateErr)
+			logging.FromContext(ctx).Error("Failed to update DynamoDB item status", logging.KeyError, updateErr)
 		}
 		
 		return fmt.Errorf("transcription API error: %w", err)
@@ -115,39 +365,275 @@ func (p *Processor) ProcessFile(ctx context.Context, bucket, key string) error {
 	// Calculate processing time
 	processingTime := time.Since(startTime).Seconds()
 	
+	// An operator may have cancelled the job while the provider was working, in which case the
+	// state machine refuses the move to POSTPROCESSING
+	if err := p.advance(ctx, fileID, model.StatusPostprocessing); err != nil {
+		return p.abandon(ctx, fileID, &attempt, err)
+	}
+	
+	// Record the detected language and which provider/model produced the transcript
+	if transcriptionResp.LanguageCode != "" || route != (model.LanguageRoute{}) {
+		route.Provider = providerName(route)
+		err = p.dynamoDBOperations.UpdateTranscriptionLanguage(
+			ctx, fileID, transcriptionResp.LanguageCode, transcriptionResp.LanguageProbability, route)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to record detected language", logging.KeyError, err)
+		}
+	}
+	
+	// Run the post-processing stages before anything is stored
+	transcriptionResp, processed, stages, err := p.postProcess(ctx, fileID, source, transcriptionResp, vocabulary)
+	attempt.Stages = stages
+	if err != nil {
+		attempt.Error = err.Error()
+		p.jobFailed(ctx, reasonPostprocess)
+		
+		failedEvent := newJobEvent(fileID, bucket, key, opts, model.StatusFailed, startTime)
+		failedEvent.Error = attempt.Error
+		if updateErr := p.finishAttempt(ctx, failure(fileID, attempt.Error), failedEvent); updateErr != nil {
+			logging.FromContext(ctx).Error("Failed to update DynamoDB item status", logging.KeyError, updateErr)
+		}
+		
+		return err
+	}
+	
 	// If output bucket is specified, store the transcript in S3
-	var outputLocation string
-	if p.outputBucket != "" && transcriptionResp.Text != "" {
-		// Generate output key based on input filename
-		baseName := filepath.Base(key)
-		extLess := baseName[:len(baseName)-len(filepath.Ext(baseName))]
-		outputKey := fmt.Sprintf("transcripts/%s.txt", extLess)
+	outputBucket := p.outputBucket
+	if opts.OutputBucket != "" {
+		outputBucket = opts.OutputBucket
+	}
+	
+	// Everything from here to the final update writes the results
+	storeCtx, storeSpan := tracing.Start(ctx, "Store")
+	var outputLocation, structuredLocation string
+	if outputBucket != "" && transcriptionResp.Text != "" {
+		// Generate output key based on the job's input key
+		outputKey := model.OutputKey("transcripts/", fileID)
 		
 		// Upload transcript to S3
-		err = p.s3Operations.UploadText(ctx, p.outputBucket, outputKey, transcriptionResp.Text)
+		err = p.uploadOutput(storeCtx, fileID, outputBucket, outputKey, transcriptionResp.Text)
 		if err != nil {
-			log.Printf("Warning: Failed to upload transcript to S3: %v", err)
+			logging.FromContext(ctx).Warn("Failed to upload transcript to S3", logging.KeyError, err)
 			// Continue processing instead of failing
 		} else {
-			outputLocation = fmt.Sprintf("s3://%s/%s", p.outputBucket, outputKey)
-			log.Printf("Uploaded transcript to %s", outputLocation)
+			outputLocation = fmt.Sprintf("s3://%s/%s", outputBucket, outputKey)
+			logging.FromContext(ctx).Info("Uploaded transcript", "location", outputLocation)
+			p.tagOutput(storeCtx, source, outputBucket, outputKey)
+		}
+		
+		// Keep word timings next to the text so subtitles can be rendered later
+		if len(transcriptionResp.Words) > 0 {
+			structuredLocation = p.uploadStructuredTranscript(storeCtx, outputBucket, model.StructuredOutputKey(outputKey), source, fileID, transcriptionResp)
 		}
 	}
 	
+	event := newJobEvent(fileID, bucket, key, opts, model.StatusCompleted, startTime)
+	event.OutputLocation = outputLocation
+	event.StructuredOutputLocation = structuredLocation
+	
 	// Update DynamoDB with successful result
-	err = p.dynamoDBOperations.UpdateTranscriptionItemStatus(
-		ctx,
-		fileID,
-		model.StatusCompleted,
-		transcriptionResp.Text,
-		outputLocation,
-		"", // No error message
-		processingTime,
-	)
+	completed := awsclient.NewItemUpdate(fileID).
+		Status(model.StatusCompleted).
+		Set(awsclient.AttrProcessingTime, processingTime).
+		Remove(awsclient.AttrErrorMessage)
+	if outputLocation != "" {
+		completed.Set(awsclient.AttrOutputLocation, outputLocation)
+	}
+	if vocabulary != nil {
+		completed.Set(awsclient.AttrVocabulary, vocabulary.Location).
+			Set(awsclient.AttrVocabularyVersion, vocabulary.Version)
+	} else {
+		completed.Remove(awsclient.AttrVocabulary, awsclient.AttrVocabularyVersion)
+	}
+	recordAttributes(completed, processed)
+	
+	// Large transcripts are kept in S3 since DynamoDB items are limited to 400KB
+	err = p.setTranscript(storeCtx, completed, source, fileID, transcriptionResp.Text, outputLocation)
+	tracing.End(storeSpan, err)
 	if err != nil {
-		log.Printf("Warning: Failed to update DynamoDB with successful result: %v", err)
+		attempt.Error = err.Error()
+		p.jobFailed(ctx, reasonStorage)
+		
+		failedEvent := newJobEvent(fileID, bucket, key, opts, model.StatusFailed, startTime)
+		failedEvent.Error = attempt.Error
+		if updateErr := p.finishAttempt(ctx, failure(fileID, attempt.Error), failedEvent); updateErr != nil {
+			logging.FromContext(ctx).Error("Failed to update DynamoDB item status", logging.KeyError, updateErr)
+		}
+		
+		return err
+	}
+	
+	err = p.finishAttempt(ctx, completed, event)
+	if errors.Is(err, awsclient.ErrStatusConflict) {
+		// The job left the processing path (e.g. it was cancelled) while it was transcribed
+		logging.FromContext(ctx).Info("File changed status during processing, discarding result", logging.KeyError, err)
+		attempt.Status = model.StatusCancelled
+		return nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to update DynamoDB with successful result", logging.KeyError, err)
 		// Continue despite error since transcription was successful
 	}
+	attempt.Status = model.StatusCompleted
+	p.jobCompleted(ctx, transcriptionResp)
+	p.recordUsage(ctx, fileID, tenantID, key, attemptNumber, replaced, &providerCall{route: route, resp: transcriptionResp})
+	p.consumeQuota(ctx, owner, transcriptionResp)
+	
+	logging.FromContext(ctx).Info("Successfully processed file", "processingSeconds", processingTime)
+	return nil
+}
+
+// uploadStructuredTranscript stores the transcript with word timings as JSON and returns its S3 URL.
+// Failures are logged only and return an empty location.
+func (p *Processor) uploadStructuredTranscript(ctx context.Context, bucket, key string, source model.JobSource, fileID string, resp *model.ElevenLabsResponse) string {
+	data, err := json.Marshal(model.Transcript{
+		FileIdentifier:      fileID,
+		Text:                resp.Text,
+		LanguageCode:        resp.LanguageCode,
+		LanguageProbability: resp.LanguageProbability,
+		Words:               resp.Words,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to marshal structured transcript", logging.KeyError, err)
+		return ""
+	}
+	
+	if err := p.uploadOutput(ctx, fileID, bucket, key, string(data)); err != nil {
+		logging.FromContext(ctx).Warn("Failed to upload structured transcript to S3", logging.KeyError, err)
+		return ""
+	}
+	p.tagOutput(ctx, source, bucket, key)
 	
-	log.Printf("Successfully processed file %s in %.2f seconds", fileID, processingTime)
-	return nil
\ No newline at end of file
+	return fmt.Sprintf("s3://%s/%s", bucket, key)
+}
+
+// newJobEvent builds the completion event for a finished attempt. The ID is derived from the job and
+// attempt so a retried write of the same outcome carries the same ID.
+func newJobEvent(fileID, bucket, key string, opts model.JobOptions, status model.TranscriptionStatus, startTime time.Time) *model.JobEvent {
+	eventType := model.EventTypeCompleted
+	if status == model.StatusFailed {
+		eventType = model.EventTypeFailed
+	}
+	
+	id := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", fileID, status, startTime.UnixNano())))
+	
+	return &model.JobEvent{
+		Version:         model.JobEventVersion,
+		ID:              hex.EncodeToString(id[:16]),
+		Type:            eventType,
+		Time:            time.Now().UTC().Truncate(time.Second),
+		FileIdentifier:  fileID,
+		Status:          status,
+		SourceBucket:    bucket,
+		SourceKey:       key,
+		BatchID:         opts.BatchID,
+		DurationSeconds: time.Since(startTime).Seconds(),
+		Metadata:        opts.Metadata,
+	}
+}
+
+// finishAttempt writes the attempt's final status. With events enabled, the event is recorded in the
+// outbox in the same transaction so it's delivered if and only if the status change is stored.
+func (p *Processor) finishAttempt(ctx context.Context, update *awsclient.ItemUpdate, event *model.JobEvent) error {
+	if !p.publishEvents {
+		return p.dynamoDBOperations.UpdateTranscriptionItem(ctx, update)
+	}
+	
+	return p.dynamoDBOperations.UpdateTranscriptionItemWithEvent(ctx, update, event)
+}
+
+// failure builds the update that marks a job FAILED with an error message
+func failure(fileID, errorMessage string) *awsclient.ItemUpdate {
+	return awsclient.NewItemUpdate(fileID).
+		Status(model.StatusFailed).
+		Set(awsclient.AttrErrorMessage, errorMessage)
+}
+
+// advance moves a claimed job to its next processing status
+func (p *Processor) advance(ctx context.Context, fileID string, status model.TranscriptionStatus) error {
+	return p.dynamoDBOperations.UpdateTranscriptionItem(ctx, awsclient.NewItemUpdate(fileID).Status(status))
+}
+
+// abandon stops processing after a failed status change. A rejected transition means the job left the
+// processing path (e.g. it was cancelled) and the attempt's result is discarded; other errors fail the job.
+func (p *Processor) abandon(ctx context.Context, fileID string, attempt *model.AttemptRecord, err error) error {
+	if errors.Is(err, model.ErrInvalidTransition) {
+		if p.isCancelled(ctx, fileID) {
+			logging.FromContext(ctx).Info("File was cancelled during processing, discarding result")
+			attempt.Status = model.StatusCancelled
+			return nil
+		}
+		attempt.Error = err.Error()
+		p.jobFailed(ctx, reasonStatus)
+		return fmt.Errorf("job %s changed status during processing: %w", fileID, err)
+	}
+	
+	attempt.Error = fmt.Sprintf("Failed to update job status: %v", err)
+	p.jobFailed(ctx, reasonStatus)
+	if updateErr := p.dynamoDBOperations.UpdateTranscriptionItem(ctx, failure(fileID, attempt.Error)); updateErr != nil {
+		logging.FromContext(ctx).Error("Failed to update DynamoDB item status", logging.KeyError, updateErr)
+	}
+	return fmt.Errorf("failed to update job status: %w", err)
+}
+
+// reject records a job whose input can never be transcribed. Rejected jobs aren't retried automatically.
+func (p *Processor) reject(
+	ctx context.Context,
+	fileID, tenantID, bucket, key string,
+	opts model.JobOptions,
+	existingItem *model.TranscriptionItem,
+	reason string,
+) error {
+	logging.FromContext(ctx).Warn("Rejecting file", "reason", reason)
+	
+	var err error
+	if existingItem == nil {
+		item := &model.TranscriptionItem{
+			FileIdentifier: fileID,
+			TenantID:       tenantID,
+			Status:         model.StatusRejected,
+			SourceBucket:   bucket,
+			SourceKey:      key,
+			ErrorMessage:   reason,
+			BatchID:        opts.BatchID,
+			BatchTenantID:  opts.BatchTenantID,
+			Metadata:       opts.Metadata,
+		}
+		if policy, ok := model.RetentionFor(p.retention, model.JobSource{TenantID: tenantID, Bucket: bucket, Key: key}); ok {
+			item.ExpiresAt = policy.ExpiresAt(time.Now())
+		}
+		err = p.dynamoDBOperations.CreateTranscriptionItem(ctx, item)
+	} else {
+		update := awsclient.NewItemUpdate(fileID).
+			Status(model.StatusRejected).
+			Set(awsclient.AttrErrorMessage, reason)
+		joinBatch(update, existingItem, opts)
+		err = p.dynamoDBOperations.UpdateTranscriptionItem(ctx, update)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record rejection: %w", err)
+	}
+	p.recordBatchOutcome(ctx, fileID)
+	
+	return fmt.Errorf("file %s rejected: %s", fileID, reason)
+}
+
+// recordAttempt appends the finished attempt to the item's history; failures are logged only
+func (p *Processor) recordAttempt(ctx context.Context, fileID string, attempt *model.AttemptRecord) {
+	attempt.FinishedAt = time.Now().UTC()
+	attempt.DurationSeconds = attempt.FinishedAt.Sub(attempt.StartedAt).Seconds()
+	
+	if err := p.dynamoDBOperations.AppendAttempt(ctx, fileID, *attempt); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record attempt history", logging.KeyError, err)
+	}
+}
+
+// isCancelled reports whether the item has been cancelled since processing started
+func (p *Processor) isCancelled(ctx context.Context, fileID string) bool {
+	item, err := p.dynamoDBOperations.GetTranscriptionItem(ctx, fileID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to check cancellation status", logging.KeyError, err)
+		return false
+	}
+	return item != nil && item.Status == model.StatusCancelled
+}
@@ -10,8 +10,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"github.com/yourusername/transcription-service/internal/elevenlabs"
+	"github.com/yourusername/transcription-service/internal/awsclient"
 	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/usage"
 )
 
 // Mock S3 operations
@@ -24,6 +25,14 @@ func (m *MockS3Operations) DownloadFile(ctx context.Context, bucket, key string)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockS3Operations) ReadObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	args := m.Called(ctx, bucket, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (m *MockS3Operations) GeneratePresignedURL(ctx context.Context, bucket, key string, expirationSeconds int) (string, error) {
 	args := m.Called(ctx, bucket, key, expirationSeconds)
 	return args.String(0), args.Error(1)
@@ -34,6 +43,21 @@ func (m *MockS3Operations) UploadText(ctx context.Context, bucket, key, content
 	return args.Error(0)
 }
 
+func (m *MockS3Operations) UploadEncryptedText(ctx context.Context, bucket, key, content, kmsKeyID string) error {
+	args := m.Called(ctx, bucket, key, content, kmsKeyID)
+	return args.Error(0)
+}
+
+func (m *MockS3Operations) TagObject(ctx context.Context, bucket, key string, tags map[string]string) error {
+	args := m.Called(ctx, bucket, key, tags)
+	return args.Error(0)
+}
+
+func (m *MockS3Operations) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	args := m.Called(ctx, bucket, key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Mock DynamoDB operations
 type MockDynamoDBOperations struct {
 	mock.Mock
@@ -44,16 +68,8 @@ func (m *MockDynamoDBOperations) CreateTranscriptionItem(ctx context.Context, it
 	return args.Error(0)
 }
 
-func (m *MockDynamoDBOperations) UpdateTranscriptionItemStatus(
-	ctx context.Context,
-	fileIdentifier string,
-	status model.TranscriptionStatus,
-	transcriptText string,
-	outputLocation string,
-	errorMessage string,
-	processingTime float64,
-) error {
-	args := m.Called(ctx, fileIdentifier, status, transcriptText, outputLocation, errorMessage, processingTime)
+func (m *MockDynamoDBOperations) UpdateTranscriptionItem(ctx context.Context, update *awsclient.ItemUpdate) error {
+	args := m.Called(ctx, update)
 	return args.Error(0)
 }
 
@@ -65,6 +81,58 @@ func (m *MockDynamoDBOperations) GetTranscriptionItem(ctx context.Context, fileI
 	return args.Get(0).(*model.TranscriptionItem), args.Error(1)
 }
 
+func (m *MockDynamoDBOperations) CreateBatchItem(ctx context.Context, batch *model.BatchItem) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBOperations) GetBatchItem(ctx context.Context, tenantID, batchID string) (*model.BatchItem, error) {
+	args := m.Called(ctx, tenantID, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BatchItem), args.Error(1)
+}
+
+func (m *MockDynamoDBOperations) RecordBatchProgress(ctx context.Context, tenantID, batchID, fileID string, succeeded bool) (*model.BatchItem, bool, error) {
+	args := m.Called(ctx, tenantID, batchID, fileID, succeeded)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*model.BatchItem), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDynamoDBOperations) UpdateTranscriptionLanguage(ctx context.Context, fileIdentifier, detectedLanguage string, probability float64, route model.LanguageRoute) error {
+	args := m.Called(ctx, fileIdentifier, detectedLanguage, probability, route)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBOperations) AppendAttempt(ctx context.Context, fileIdentifier string, record model.AttemptRecord) error {
+	args := m.Called(ctx, fileIdentifier, record)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBOperations) UpdateTranscriptionItemWithEvent(ctx context.Context, update *awsclient.ItemUpdate, event *model.JobEvent) error {
+	args := m.Called(ctx, update, event)
+	return args.Error(0)
+}
+
+// statusUpdate matches an item update that moves key to status and sets the given attribute values
+func statusUpdate(key string, status model.TranscriptionStatus, values ...interface{}) interface{} {
+	return mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		if update.FileIdentifier() != key || update.NewStatus() != status {
+			return false
+		}
+		for i := 0; i+1 < len(values); i += 2 {
+			value, ok := update.Value(values[i].(string))
+			if !ok || value != values[i+1] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 // Mock ElevenLabs client
 type MockElevenLabsClient struct {
 	mock.Mock
@@ -78,6 +146,14 @@ func (m *MockElevenLabsClient) TranscribeAudio(ctx context.Context, audioURL str
 	return args.Get(0).(*model.ElevenLabsResponse), args.Error(1)
 }
 
+func (m *MockElevenLabsClient) TranscribeAudioWithOptions(ctx context.Context, audioURL string, opts model.TranscribeOptions) (*model.ElevenLabsResponse, error) {
+	args := m.Called(ctx, audioURL, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ElevenLabsResponse), args.Error(1)
+}
+
 // Test the ProcessFile method
 func TestProcessFile(t *testing.T) {
 	// Create mocks
@@ -104,33 +180,42 @@ func TestProcessFile(t *testing.T) {
 	key := "audio/test-file.aac"
 	
 	// Setup mock expectations
-	mockDynamoDBOps.On("GetTranscriptionItem", ctx, key).Return(nil, nil)
-	mockDynamoDBOps.On("CreateTranscriptionItem", ctx, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
 		return item.FileIdentifier == key && 
-			   item.Status == model.StatusInProgress &&
+			   item.Status == model.StatusClaimed &&
 			   item.SourceBucket == bucket &&
 			   item.SourceKey == key
 	})).Return(nil)
 	
-	mockS3Ops.On("GeneratePresignedURL", ctx, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
 	
-	mockElevenLabsClient.On("TranscribeAudio", ctx, "https://presigned-url").Return(&model.ElevenLabsResponse{
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
 		ID:      "test-id",
 		Text:    "This is a test transcription.",
 		Success: true,
 	}, nil)
 	
-	mockS3Ops.On("UploadText", ctx, "test-output-bucket", "transcripts/test-file.txt", "This is a test transcription.").Return(nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", "transcripts/audio/test-file.aac.txt", "This is a test transcription.").Return(nil)
 	
-	mockDynamoDBOps.On("UpdateTranscriptionItemStatus", 
-		ctx, 
-		key, 
-		model.StatusCompleted, 
-		"This is a test transcription.",
-		"s3://test-output-bucket/transcripts/test-file.txt",
-		"",
-		mock.MatchedBy(func(pt float64) bool { return pt > 0 }),
-	).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		text, _ := update.Value(awsclient.AttrTranscriptText)
+		location, _ := update.Value(awsclient.AttrOutputLocation)
+		processingTime, _ := update.Value(awsclient.AttrProcessingTime)
+		return update.FileIdentifier() == key &&
+			   update.NewStatus() == model.StatusCompleted &&
+			   text == "This is a test transcription." &&
+			   location == "s3://test-output-bucket/transcripts/audio/test-file.aac.txt" &&
+			   processingTime.(float64) > 0 &&
+			   update.Removes(awsclient.AttrErrorMessage)
+	})).Return(nil)
+	
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Status == model.StatusCompleted && record.Provider == DefaultProviderName && !record.FinishedAt.IsZero()
+	})).Return(nil)
 	
 	// Call method
 	err := processor.ProcessFile(ctx, bucket, key)
@@ -178,7 +263,7 @@ func TestProcessFile_AlreadyProcessed(t *testing.T) {
 	}
 	
 	// Setup mock expectations
-	mockDynamoDBOps.On("GetTranscriptionItem", ctx, key).Return(existingItem, nil)
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(existingItem, nil)
 	
 	// Call method
 	err := processor.ProcessFile(ctx, bucket, key)
@@ -216,23 +301,22 @@ func TestProcessFile_TranscriptionError(t *testing.T) {
 	key := "audio/test-file.aac"
 	
 	// Setup mock expectations
-	mockDynamoDBOps.On("GetTranscriptionItem", ctx, key).Return(nil, nil)
-	mockDynamoDBOps.On("CreateTranscriptionItem", ctx, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
 	
-	mockS3Ops.On("GeneratePresignedURL", ctx, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
 	
 	apiError := errors.New("API error")
-	mockElevenLabsClient.On("TranscribeAudio", ctx, "https://presigned-url").Return(nil, apiError)
-	
-	mockDynamoDBOps.On("UpdateTranscriptionItemStatus", 
-		ctx, 
-		key, 
-		model.StatusFailed, 
-		"",
-		"",
-		"Transcription API error: API error",
-		float64(0),
-	).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(nil, apiError)
+	
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusFailed,
+		awsclient.AttrErrorMessage, "Transcription API error: API error")).Return(nil)
+	
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Status == model.StatusFailed && record.Error == "Transcription API error: API error"
+	})).Return(nil)
 	
 	// Call method
 	err := processor.ProcessFile(ctx, bucket, key)
@@ -243,4 +327,236 @@ func TestProcessFile_TranscriptionError(t *testing.T) {
 	mockDynamoDBOps.AssertExpectations(t)
 	mockS3Ops.AssertExpectations(t)
 	mockElevenLabsClient.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+// Test job cancelled while the provider was transcribing
+func TestProcessFile_CancelledDuringTranscription(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "audio/test-file.aac"
+	
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(&model.TranscriptionItem{Status: model.StatusPending}, nil).Once()
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusClaimed)).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Too late.",
+		Success: true,
+	}, nil)
+	
+	// The state machine refuses to move a cancelled job on
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(&awsclient.TransitionError{
+		FileIdentifier: key,
+		From:           model.StatusCancelled,
+		To:             model.StatusPostprocessing,
+		Exists:         true,
+	})
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(&model.TranscriptionItem{Status: model.StatusCancelled}, nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Status == model.StatusCancelled
+	})).Return(nil)
+	
+	err := processor.ProcessFile(ctx, bucket, key)
+	
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockS3Ops.AssertNotCalled(t, "UploadText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertNotCalled(t, "UpdateTranscriptionItem", ctx, statusUpdate(key, model.StatusCompleted))
+}
+
+// Test job cancelled after its transcript was stored but before it completed
+func TestProcessFile_CancelledBeforeCompletion(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	store := new(MockUsageStore)
+	
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	processor.SetUsage(usage.NewLedger(store, usage.Prices{"elevenlabs": 0.006}))
+	
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "audio/test-file.aac"
+	
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Too late.",
+		Words:   []model.Word{{Text: "Too late.", Start: 0, End: 1, Type: "word"}},
+		Success: true,
+	}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	
+	// The job was cancelled while its output was uploaded
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusCompleted)).Return(&awsclient.TransitionError{
+		FileIdentifier: key,
+		From:           model.StatusCancelled,
+		To:             model.StatusCompleted,
+		Exists:         true,
+	})
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(record model.AttemptRecord) bool {
+		return record.Status == model.StatusCancelled
+	})).Return(nil)
+	
+	err := processor.ProcessFile(ctx, bucket, key)
+	
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	store.AssertNotCalled(t, "RecordUsage", mock.Anything, mock.Anything)
+}
+
+// Test completion and failure events are written to the outbox with the final status
+func TestProcessFile_PublishesEvents(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	processor.SetPublishEvents(true)
+	
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "audio/test-file.aac"
+	
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Hello.",
+		Words:   []model.Word{{Text: "Hello.", Start: 0, End: 0.5, Type: "word"}},
+		Success: true,
+	}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItemWithEvent", mock.Anything,
+		statusUpdate(key, model.StatusCompleted,
+			awsclient.AttrTranscriptText, "Hello.",
+			awsclient.AttrOutputLocation, "s3://test-output-bucket/transcripts/audio/test-file.aac.txt"),
+		mock.MatchedBy(func(event *model.JobEvent) bool {
+			return event.Version == model.JobEventVersion &&
+				event.ID != "" &&
+				event.Type == model.EventTypeCompleted &&
+				event.Status == model.StatusCompleted &&
+				event.SourceBucket == bucket &&
+				event.OutputLocation == "s3://test-output-bucket/transcripts/audio/test-file.aac.txt" &&
+				event.StructuredOutputLocation == "s3://test-output-bucket/transcripts/audio/test-file.aac.json"
+		})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+	
+	err := processor.ProcessFile(ctx, bucket, key)
+	
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockDynamoDBOps.AssertNotCalled(t, "UpdateTranscriptionItem", ctx, statusUpdate(key, model.StatusCompleted))
+	
+	// A failed attempt writes a failure event with the error
+	failingKey := "audio/broken.aac"
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, failingKey).Return(nil, nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, failingKey, 3600).Return("", errors.New("access denied"))
+	mockDynamoDBOps.On("UpdateTranscriptionItemWithEvent", mock.Anything,
+		statusUpdate(failingKey, model.StatusFailed, awsclient.AttrErrorMessage, "Failed to generate pre-signed URL: access denied"),
+		mock.MatchedBy(func(event *model.JobEvent) bool {
+			return event.Type == model.EventTypeFailed &&
+				event.Status == model.StatusFailed &&
+				event.Error == "Failed to generate pre-signed URL: access denied"
+		})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, failingKey, mock.Anything).Return(nil)
+	
+	err = processor.ProcessFile(ctx, bucket, failingKey)
+	
+	assert.Error(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+// Test a job claimed by another invocation between the read and the claim
+func TestProcessFile_ClaimConflict(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	
+	ctx := context.Background()
+	key := "audio/test-file.aac"
+	
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(&awsclient.TransitionError{
+		FileIdentifier: key,
+		From:           model.StatusClaimed,
+		To:             model.StatusClaimed,
+		Exists:         true,
+	})
+	
+	err := processor.ProcessFile(ctx, "test-bucket", key)
+	
+	assert.NoError(t, err)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockS3Ops.AssertNotCalled(t, "GeneratePresignedURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertNotCalled(t, "AppendAttempt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test unsupported inputs are rejected without calling the provider
+func TestProcessFile_RejectsUnsupportedFormat(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	
+	ctx := context.Background()
+	key := "docs/readme.pdf"
+	
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.Status == model.StatusRejected && item.ErrorMessage == `unsupported audio format ".pdf"`
+	})).Return(nil)
+	
+	err := processor.ProcessFile(ctx, "test-bucket", key)
+	
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected")
+	mockDynamoDBOps.AssertExpectations(t)
+	mockElevenLabsClient.AssertNotCalled(t, "TranscribeAudio", mock.Anything, mock.Anything)
+	
+	// Rejected jobs are skipped when the object is seen again
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "docs/old.pdf").Return(&model.TranscriptionItem{Status: model.StatusRejected}, nil)
+	
+	err = processor.ProcessFile(ctx, "test-bucket", "docs/old.pdf")
+	
+	assert.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/metrics"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// ErrDeferred is returned for a job that was deferred by its tenant's quotas. It isn't a failure: the
+// scheduler dispatches the job again once it's due.
+var ErrDeferred = errors.New("job deferred by tenant quota")
+
+// SetQuotas enforces the tenants' quotas. A job whose tenant has too many jobs running, or has used up
+// its daily minutes, is left PENDING with the time it may be dispatched again instead of being failed.
+func (p *Processor) SetQuotas(limiter *quota.Limiter) {
+	p.quotas = limiter
+}
+
+// deferJob leaves a job over its tenant's quotas PENDING, keeping its options for the scheduler that
+// dispatches it again, and returns ErrDeferred.
+func (p *Processor) deferJob(
+	ctx context.Context,
+	fileID, tenantID, bucket, key string,
+	opts model.JobOptions,
+	existingItem *model.TranscriptionItem,
+	decision quota.Decision,
+) error {
+	logging.FromContext(ctx).Info("Tenant is over quota, deferring", "reason", decision.Reason, "retryAt", decision.RetryAt)
+	p.count(ctx, metrics.JobsDeferred, nil)
+
+	retryAt := decision.RetryAt.UTC().Truncate(time.Second)
+	var err error
+	if existingItem == nil {
+		err = p.dynamoDBOperations.CreateTranscriptionItem(ctx, &model.TranscriptionItem{
+			FileIdentifier:  fileID,
+			TenantID:        tenantID,
+			Status:          model.StatusPending,
+			SourceBucket:    bucket,
+			SourceKey:       key,
+			BatchID:         opts.BatchID,
+			BatchTenantID:   opts.BatchTenantID,
+			Metadata:        opts.Metadata,
+			LanguageHint:    opts.Language,
+			DeferredUntil:   &retryAt,
+			DeferredReason:  decision.Reason,
+			DeferredOptions: &opts,
+		})
+	} else {
+		update := awsclient.NewItemUpdate(fileID).
+			Set(awsclient.AttrDeferredUntil, retryAt).
+			Set(awsclient.AttrDeferredReason, decision.Reason).
+			Set(awsclient.AttrDeferredOptions, opts)
+		if existingItem.Status != model.StatusPending {
+			update.Status(model.StatusPending)
+		}
+		joinBatch(update, existingItem, opts)
+		err = p.dynamoDBOperations.UpdateTranscriptionItem(ctx, update)
+	}
+	if errors.Is(err, model.ErrInvalidTransition) {
+		logging.FromContext(ctx).Info("File was claimed by another invocation, skipping", logging.KeyError, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to defer job: %w", err)
+	}
+
+	return ErrDeferred
+}
+
+// releaseSlot gives back the job's running slot when an admitted attempt ends; failures are logged only
+func (p *Processor) releaseSlot(ctx context.Context, owner *tenant.Tenant, fileID string) {
+	if err := p.quotas.Release(ctx, owner, fileID); err != nil {
+		logging.FromContext(ctx).Warn("Failed to release tenant job slot", logging.KeyError, err)
+	}
+}
+
+// consumeQuota counts a completed job's audio against its tenant's daily minutes, with the duration the
+// usage ledger records, so silence counts too; failures are logged only
+func (p *Processor) consumeQuota(ctx context.Context, owner *tenant.Tenant, resp *model.ElevenLabsResponse) {
+	if owner == nil || p.quotas == nil {
+		return
+	}
+
+	if err := p.quotas.Consume(ctx, owner, audioSeconds(resp)); err != nil {
+		logging.FromContext(ctx).Warn("Failed to count tenant minutes", logging.KeyError, err)
+	}
+}
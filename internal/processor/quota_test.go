@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/quota"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// MockQuotaStore is a mock implementation of quota.Store
+type MockQuotaStore struct {
+	mock.Mock
+}
+
+func (m *MockQuotaStore) AcquireJobSlot(ctx context.Context, tenantID, jobID string, limit int, expires time.Time) (bool, error) {
+	args := m.Called(ctx, tenantID, jobID, limit, expires)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQuotaStore) ReleaseJobSlot(ctx context.Context, tenantID, jobID string) error {
+	args := m.Called(ctx, tenantID, jobID)
+	return args.Error(0)
+}
+
+func (m *MockQuotaStore) AddTenantMinutes(ctx context.Context, tenantID, day string, minutes float64) error {
+	args := m.Called(ctx, tenantID, day, minutes)
+	return args.Error(0)
+}
+
+func (m *MockQuotaStore) GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error) {
+	args := m.Called(ctx, tenantID, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.QuotaUsage), args.Error(1)
+}
+
+func quotaTenants(t *testing.T) *tenant.Registry {
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "bulk", "bucket": "audio", "prefix": "bulk/", "quotas": {"maxConcurrentJobs": 1}, "options": {"language": "de"}}
+	]}`))
+	assert.NoError(t, err)
+	return registry
+}
+
+func TestProcessFile_DeferredOverQuota(t *testing.T) {
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	store := new(MockQuotaStore)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       new(MockS3Operations),
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(quotaTenants(t), nil)
+	processor.SetQuotas(quota.NewLimiter(store, time.Minute))
+
+	ctx := context.Background()
+	jobID := "tenant#bulk#bulk/0001.mp3"
+	before := time.Now()
+
+	// The tenant's only slot is taken, so the new job is recorded PENDING with its options
+	store.On("AcquireJobSlot", mock.Anything, "bulk", mock.Anything, 1, mock.Anything).Return(false, nil)
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, jobID).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.FileIdentifier == jobID &&
+			item.Status == model.StatusPending &&
+			item.TenantID == "bulk" &&
+			item.DeferredUntil != nil && item.DeferredUntil.After(before) &&
+			item.DeferredReason == "1 jobs already running" &&
+			item.DeferredOptions.Language == "de"
+	})).Return(nil)
+
+	err := processor.ProcessFile(ctx, "audio", "bulk/0001.mp3")
+
+	assert.ErrorIs(t, err, ErrDeferred)
+	mockDynamoDBOps.AssertExpectations(t)
+	mockElevenLabsClient.AssertNotCalled(t, "TranscribeAudio", mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "ReleaseJobSlot", mock.Anything, mock.Anything, mock.Anything)
+
+	// A job that failed before is moved back to PENDING
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "tenant#bulk#bulk/0002.mp3").Return(&model.TranscriptionItem{
+		FileIdentifier: "tenant#bulk#bulk/0002.mp3",
+		Status:         model.StatusFailed,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		_, deferred := update.Value(awsclient.AttrDeferredUntil)
+		return update.FileIdentifier() == "tenant#bulk#bulk/0002.mp3" && update.NewStatus() == model.StatusPending && deferred
+	})).Return(nil)
+
+	assert.ErrorIs(t, processor.ProcessFile(ctx, "audio", "bulk/0002.mp3"), ErrDeferred)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestProcessFile_QuotaSlotReleased(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	store := new(MockQuotaStore)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(quotaTenants(t), nil)
+	processor.SetQuotas(quota.NewLimiter(store, time.Minute))
+
+	jobID := "tenant#bulk#bulk/0001.mp3"
+	store.On("AcquireJobSlot", mock.Anything, "bulk", jobID, 1, mock.Anything).Return(true, nil)
+	store.On("AddTenantMinutes", mock.Anything, "bulk", mock.Anything, 3.0).Return(nil)
+	store.On("ReleaseJobSlot", mock.Anything, "bulk", jobID).Return(nil).Once()
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, jobID).Return(&model.TranscriptionItem{
+		FileIdentifier: jobID,
+		Status:         model.StatusPending,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		return update.NewStatus() == model.StatusClaimed
+	})).Return(nil).Once()
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, jobID, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionLanguage", mock.Anything, jobID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", "bulk/0001.mp3", 3600).Return("https://presigned-url", nil)
+	// The trailing minute of silence counts against the quota as well
+	mockElevenLabsClient.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-url", mock.Anything).Return(&model.ElevenLabsResponse{
+		Text:          "Hallo",
+		Words:         []model.Word{{Text: "Hallo", Start: 0, End: 120}},
+		AudioDuration: 180,
+		Success:       true,
+	}, nil)
+
+	assert.NoError(t, processor.ProcessFile(context.Background(), "audio", "bulk/0001.mp3"))
+	store.AssertExpectations(t)
+}
+
+func TestProcessManifest_DeferredFile(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	store := new(MockQuotaStore)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(quotaTenants(t), nil)
+	processor.SetQuotas(quota.NewLimiter(store, time.Minute))
+
+	manifest := `{"batchId": "b", "files": [{"bucket": "audio", "key": "bulk/0001.mp3"}]}`
+	mockS3Ops.On("ReadObject", mock.Anything, "submissions", "jobs/dispatch/batch-0001.manifest.json").Return([]byte(manifest), nil)
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.Anything).Return(awsclient.ErrBatchExists)
+	mockDynamoDBOps.On("GetBatchItem", mock.Anything, "", "b").Return(&model.BatchItem{BatchID: "b", BatchStatus: model.BatchStatusInProgress}, nil)
+
+	// The deferred job isn't counted until the scheduler has dispatched it and it finishes
+	store.On("AcquireJobSlot", mock.Anything, "bulk", mock.Anything, 1, mock.Anything).Return(false, nil)
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "tenant#bulk#bulk/0001.mp3").Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.Status == model.StatusPending && item.BatchID == "b" && item.DeferredUntil != nil
+	})).Return(nil)
+
+	assert.NoError(t, processor.ProcessManifest(context.Background(), "submissions", "jobs/dispatch/batch-0001.manifest.json"))
+	mockDynamoDBOps.AssertExpectations(t)
+	mockDynamoDBOps.AssertNotCalled(t, "RecordBatchProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
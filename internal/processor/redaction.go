@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/redact"
+)
+
+// RedactionStage returns the post-processing stage that masks personal data. When unredactedBucket is
+// set, transcripts that had anything masked are also kept there in their original form, encrypted with
+// kmsKeyID, for the few people allowed to see them.
+func (p *Processor) RedactionStage(redactor *redact.Redactor, unredactedBucket, kmsKeyID string) postprocess.Stage {
+	return postprocess.StageFunc(func(ctx context.Context, doc *postprocess.Document) error {
+		result := redactor.Redact(doc.Transcript.Text, doc.Transcript.Words)
+		if !result.Redacted() {
+			doc.Record(awsclient.AttrRedactions, nil)
+			doc.Record(awsclient.AttrUnredactedLocation, nil)
+			return nil
+		}
+		logging.FromContext(ctx).Info("Redacted transcript", "counts", result.Counts)
+
+		var unredactedLocation interface{}
+		if unredactedBucket != "" {
+			textKey := model.OutputKey("unredacted/", doc.FileIdentifier)
+			if err := p.s3Operations.UploadEncryptedText(ctx, unredactedBucket, textKey, doc.Transcript.Text, kmsKeyID); err != nil {
+				// Without the original the redacted text would be all that's left
+				return fmt.Errorf("failed to store unredacted transcript: %w", err)
+			}
+			// The raw copy expires with the rest of the job's outputs
+			p.tagOutput(ctx, doc.Source, unredactedBucket, textKey)
+			unredactedLocation = fmt.Sprintf("s3://%s/%s", unredactedBucket, textKey)
+		}
+
+		doc.Transcript.Text = result.Text
+		doc.Transcript.Words = result.Words
+		doc.Record(awsclient.AttrRedactions, result.Counts)
+		doc.Record(awsclient.AttrUnredactedLocation, unredactedLocation)
+		return nil
+	})
+}
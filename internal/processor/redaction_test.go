@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+	"github.com/yourusername/transcription-service/internal/redact"
+)
+
+func TestProcessFile_Redaction(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	redactor, err := redact.NewRedactor([]string{"email"}, nil)
+	assert.NoError(t, err)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	pipeline, err := postprocess.New([]postprocess.Spec{{Name: "redact", OnError: postprocess.FailJob}}, map[string]postprocess.Stage{
+		"redact": processor.RedactionStage(redactor, "vault-bucket", "alias/unredacted"),
+	})
+	assert.NoError(t, err)
+	processor.SetPostProcessing(pipeline)
+	processor.SetRetention([]model.RetentionPolicy{{Scope: model.Scope{Bucket: "test-bucket", Prefix: "calls/"}, Period: 30 * 24 * time.Hour}})
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "calls/support.mp3"
+	original := "Mail me at jo@example.com"
+
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    original,
+		Success: true,
+		Words: []model.Word{
+			{Text: "Mail", Start: 0, End: 0.3, Type: "word"},
+			{Text: " ", Start: 0.3, End: 0.4, Type: "spacing"},
+			{Text: "me", Start: 0.4, End: 0.6, Type: "word"},
+			{Text: " ", Start: 0.6, End: 0.7, Type: "spacing"},
+			{Text: "at", Start: 0.7, End: 0.9, Type: "word"},
+			{Text: " ", Start: 0.9, End: 1.0, Type: "spacing"},
+			{Text: "jo@example.com", Start: 1.0, End: 2.5, Type: "word"},
+		},
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+
+	// The original only goes to the encrypted vault; every other output is redacted
+	mockS3Ops.On("UploadEncryptedText", mock.Anything, "vault-bucket", "unredacted/calls/support.mp3.txt", original, "alias/unredacted").Return(nil)
+	retention := map[string]string{model.RetentionTagKey: "30"}
+	mockS3Ops.On("TagObject", mock.Anything, "vault-bucket", "unredacted/calls/support.mp3.txt", retention).Return(nil)
+	mockS3Ops.On("TagObject", mock.Anything, "test-output-bucket", mock.Anything, retention).Return(nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", "transcripts/calls/support.mp3.txt", "Mail me at [EMAIL]").Return(nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", "transcripts/calls/support.mp3.json", mock.MatchedBy(func(content string) bool {
+		return strings.Contains(content, `"text":"[EMAIL]","start":1,"end":2.5`) && !strings.Contains(content, "jo@example.com")
+	})).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		text, _ := update.Value(awsclient.AttrTranscriptText)
+		counts, _ := update.Value(awsclient.AttrRedactions)
+		location, _ := update.Value(awsclient.AttrUnredactedLocation)
+		return update.NewStatus() == model.StatusCompleted &&
+			text == "Mail me at [EMAIL]" &&
+			assert.ObjectsAreEqual(map[string]int{"email": 1}, counts) &&
+			location == "s3://vault-bucket/unredacted/calls/support.mp3.txt"
+	})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.MatchedBy(func(attempt model.AttemptRecord) bool {
+		return len(attempt.Stages) == 1 && attempt.Stages[0].Name == "redact" && !attempt.Stages[0].Skipped
+	})).Return(nil)
+
+	err = processor.ProcessFile(ctx, bucket, key)
+
+	assert.NoError(t, err)
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestProcessFile_PostProcessingFailure(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	redactor, err := redact.NewRedactor([]string{"email"}, nil)
+	assert.NoError(t, err)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	pipeline, err := postprocess.New([]postprocess.Spec{{Name: "redact", OnError: postprocess.FailJob}}, map[string]postprocess.Stage{
+		"redact": processor.RedactionStage(redactor, "vault-bucket", "alias/unredacted"),
+	})
+	assert.NoError(t, err)
+	processor.SetPostProcessing(pipeline)
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "calls/support.mp3"
+
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Mail me at jo@example.com",
+		Success: true,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	mockS3Ops.On("UploadEncryptedText", mock.Anything, "vault-bucket", "unredacted/calls/support.mp3.txt", mock.Anything, mock.Anything).Return(errors.New("access denied"))
+
+	// Nothing unredacted may be stored when the stage can't finish
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusFailed)).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+
+	err = processor.ProcessFile(ctx, bucket, key)
+
+	assert.EqualError(t, err, "post-processing stage redact failed: failed to store unredacted transcript: access denied")
+	mockS3Ops.AssertNotCalled(t, "UploadText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertExpectations(t)
+}
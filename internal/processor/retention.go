@@ -0,0 +1,27 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// SetRetention sets the retention policies applied to processed jobs. A matching policy sets the item's
+// ExpiresAt TTL when the job is claimed and tags its S3 outputs for lifecycle expiration.
+func (p *Processor) SetRetention(policies []model.RetentionPolicy) {
+	p.retention = policies
+}
+
+// tagOutput tags an output object of the job with its retention period; failures are logged only
+func (p *Processor) tagOutput(ctx context.Context, source model.JobSource, bucket, key string) {
+	policy, ok := model.RetentionFor(p.retention, source)
+	if !ok {
+		return
+	}
+
+	if err := p.s3Operations.TagObject(ctx, bucket, key, policy.Tags()); err != nil {
+		logging.FromContext(ctx).Warn("Failed to tag output for retention", "location", fmt.Sprintf("s3://%s/%s", bucket, key), logging.KeyError, err)
+	}
+}
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestProcessFile_Retention(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "test-output-bucket",
+	}
+	processor.SetRetention([]model.RetentionPolicy{
+		{Period: 365 * 24 * time.Hour},
+		{Scope: model.Scope{Bucket: "test-bucket", Prefix: "calls/"}, Period: 30 * 24 * time.Hour},
+		{Scope: model.Scope{Bucket: "other-bucket", Prefix: "calls/support"}, Period: 7 * 24 * time.Hour},
+	})
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "calls/support.mp3"
+	before := time.Now()
+
+	// A retried job gets a fresh TTL from the new claim
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(&model.TranscriptionItem{
+		FileIdentifier: key,
+		Status:         model.StatusFailed,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		value, ok := update.Value(awsclient.AttrExpiresAt)
+		if !ok || update.NewStatus() != model.StatusClaimed {
+			return false
+		}
+		expiresAt := value.(int64)
+		return expiresAt >= before.Add(30*24*time.Hour).Unix() && expiresAt <= time.Now().Add(30*24*time.Hour).Unix()
+	})).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Thanks for calling.",
+		Success: true,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	mockS3Ops.On("UploadText", mock.Anything, "test-output-bucket", "transcripts/calls/support.mp3.txt", "Thanks for calling.").Return(nil)
+	mockS3Ops.On("TagObject", mock.Anything, "test-output-bucket", "transcripts/calls/support.mp3.txt", map[string]string{
+		model.RetentionTagKey: "30",
+	}).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusCompleted)).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+
+	err := processor.ProcessFile(ctx, bucket, key)
+
+	assert.NoError(t, err)
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestProcessFile_TenantRetention(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(testTenants(t), nil)
+	processor.SetTranscriptStorage("artifacts", 5)
+	processor.SetRetention([]model.RetentionPolicy{
+		{Scope: model.Scope{TenantID: "support"}, Period: 30 * 24 * time.Hour},
+		{Scope: model.Scope{TenantID: "sales"}, Period: 7 * 24 * time.Hour},
+	})
+
+	ctx := context.Background()
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, mock.Anything).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Policies match the tenant owning the audio, not the tenant-partitioned job ID, so the structured
+	// transcript is tagged like the text
+	words := []model.Word{{Text: "Thanks", Start: 0, End: 0.4, Type: "word"}}
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", "support/call.mp3", 3600).Return("https://presigned-support", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-support").Return(&model.ElevenLabsResponse{
+		Text:    "Thanks",
+		Words:   words,
+		Success: true,
+	}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "support-out", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("TagObject", mock.Anything, "support-out", "transcripts/support/support/call.mp3.txt", map[string]string{model.RetentionTagKey: "30"}).Return(nil)
+	mockS3Ops.On("TagObject", mock.Anything, "support-out", model.StructuredOutputKey("transcripts/support/support/call.mp3.txt"), map[string]string{model.RetentionTagKey: "30"}).Return(nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "support/call.mp3"))
+
+	// A large transcript without an output bucket is kept as an artifact, tagged the same way
+	artifactKey := "artifacts/transcripts/sales/sales/call.mp3.txt"
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", "sales/call.mp3", 3600).Return("https://presigned-sales", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-sales").Return(&model.ElevenLabsResponse{
+		Text:    "Thanks for calling.",
+		Success: true,
+	}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "artifacts", artifactKey, "Thanks for calling.").Return(nil)
+	mockS3Ops.On("TagObject", mock.Anything, "artifacts", artifactKey, map[string]string{model.RetentionTagKey: "7"}).Return(nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "sales/call.mp3"))
+	mockS3Ops.AssertExpectations(t)
+}
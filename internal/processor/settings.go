@@ -0,0 +1,9 @@
+package processor
+
+import "github.com/yourusername/transcription-service/internal/settings"
+
+// SetSettings applies runtime settings to each attempt: feature flags, the default route, tenant quota
+// overrides and the audio size limit. The settings version is logged and recorded on the job item.
+func (p *Processor) SetSettings(cache *settings.Cache) {
+	p.settings = cache
+}
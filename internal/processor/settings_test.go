@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/settings"
+)
+
+// staticSource serves a fixed settings document
+type staticSource string
+
+func (s staticSource) Fetch(ctx context.Context) ([]byte, error) {
+	return []byte(s), nil
+}
+
+func TestProcessFile_RuntimeSettings(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		vocabularies:       &vocabularies{sources: []model.VocabularySource{{Location: "s3://config/vocab.json"}}},
+	}
+	processor.SetSettings(settings.NewCache(staticSource(`{"version": "v7", "modelId": "scribe_v2",
+		"flags": {"vocabularies": false}, "maxAudioBytes": 1000}`), 0))
+	ctx := context.Background()
+
+	// Files over the size guardrail are rejected
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "large.mp3").Return(nil, nil)
+	mockS3Ops.On("ObjectSize", mock.Anything, "audio", "large.mp3").Return(int64(5000), nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.FileIdentifier == "large.mp3" && item.Status == model.StatusRejected &&
+			item.ErrorMessage == "audio is 5000 bytes, over the limit of 1000"
+	})).Return(nil).Once()
+
+	err := processor.ProcessFile(ctx, "audio", "large.mp3")
+	assert.ErrorContains(t, err, "rejected")
+	mockElevenLabsClient.AssertNotCalled(t, "TranscribeAudioWithOptions", mock.Anything, mock.Anything, mock.Anything)
+
+	// Other files record the settings version, use the default model and skip the disabled vocabulary
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "call.mp3").Return(&model.TranscriptionItem{
+		FileIdentifier: "call.mp3",
+		Status:         model.StatusFailed,
+	}, nil)
+	mockS3Ops.On("ObjectSize", mock.Anything, "audio", "call.mp3").Return(int64(800), nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		version, ok := update.Value(awsclient.AttrConfigVersion)
+		return update.NewStatus() == model.StatusClaimed && ok && version == "v7"
+	})).Return(nil).Once()
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, "call.mp3", mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionLanguage", mock.Anything, "call.mp3", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", "call.mp3", 3600).Return("https://presigned-url", nil)
+	mockElevenLabsClient.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-url", mock.MatchedBy(func(opts model.TranscribeOptions) bool {
+		return opts.ModelID == "scribe_v2" && len(opts.Keywords) == 0
+	})).Return(&model.ElevenLabsResponse{Text: "Hello", Success: true}, nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "call.mp3"))
+	mockDynamoDBOps.AssertExpectations(t)
+	mockS3Ops.AssertNotCalled(t, "ReadObject", mock.Anything, "config", "vocab.json")
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// ClientFactory builds the default provider's client for a tenant's API key secret
+type ClientFactory func(ctx context.Context, secretName string) (TranscriptionClient, error)
+
+// SetTenants serves several tenants from one deployment. Every file is resolved to the tenant owning
+// its bucket and prefix; files no tenant owns are skipped. Jobs are stored under tenant-partitioned
+// identifiers, take the tenant's default options and output bucket, and use the tenant's provider
+// API key when it has one.
+func (p *Processor) SetTenants(registry *tenant.Registry, clients ClientFactory) {
+	p.tenants = registry
+	p.tenantClients = clients
+	p.clientCache = map[string]TranscriptionClient{}
+}
+
+// defaultClient returns the default provider's client for the job's tenant. Clients are built once
+// per secret and reused across warm invocations.
+func (p *Processor) defaultClient(ctx context.Context) (TranscriptionClient, error) {
+	t, ok := tenant.FromContext(ctx)
+	if !ok || t.SecretName == "" || p.tenantClients == nil {
+		return p.elevenlabsClient, nil
+	}
+
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+
+	if client, ok := p.clientCache[t.SecretName]; ok {
+		return client, nil
+	}
+
+	client, err := p.tenantClients(ctx, t.SecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider client for tenant %s: %w", t.ID, err)
+	}
+	p.clientCache[t.SecretName] = client
+	return client, nil
+}
+
+// crossTenant reports whether a tenant's manifest lists a file owned by a different tenant
+func (p *Processor) crossTenant(tenantID, fileBucket, fileKey string) bool {
+	if tenantID == "" {
+		return false
+	}
+
+	fileOwner, ok := p.tenants.Resolve(fileBucket, fileKey)
+	return !ok || fileOwner.ID != tenantID
+}
+
+// jobID returns the identifier of a file's job: its key, in the owning tenant's partition when there are tenants
+func (p *Processor) jobID(bucket, key string) string {
+	if owner, ok := p.tenants.Resolve(bucket, key); ok {
+		return model.TenantJobID(owner.ID, key)
+	}
+	return key
+}
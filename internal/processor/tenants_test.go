@@ -0,0 +1,157 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+func testTenants(t *testing.T) *tenant.Registry {
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "support", "bucket": "audio", "prefix": "support/", "secretName": "elevenlabs/support", "outputBucket": "support-out"},
+		{"id": "sales", "bucket": "audio", "prefix": "sales/"}
+	]}`))
+	assert.NoError(t, err)
+	return registry
+}
+
+func TestProcessFile_Tenant(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	defaultClient := new(MockElevenLabsClient)
+	supportClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   defaultClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	var secrets []string
+	processor.SetTenants(testTenants(t), func(ctx context.Context, secretName string) (TranscriptionClient, error) {
+		secrets = append(secrets, secretName)
+		return supportClient, nil
+	})
+
+	ctx := context.Background()
+	jobID := "tenant#support#support/call.mp3"
+
+	// The job is stored in the tenant's partition and transcribed with the tenant's API key
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, jobID).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.MatchedBy(func(item *model.TranscriptionItem) bool {
+		return item.FileIdentifier == jobID && item.TenantID == "support" && item.SourceKey == "support/call.mp3"
+	})).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, jobID, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", "support/call.mp3", 3600).Return("https://presigned-url", nil)
+	supportClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{Text: "hi", Success: true}, nil)
+	mockS3Ops.On("UploadText", mock.Anything, "support-out", "transcripts/support/support/call.mp3.txt", "hi").Return(nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "support/call.mp3"))
+	mockDynamoDBOps.AssertExpectations(t)
+	mockS3Ops.AssertExpectations(t)
+	defaultClient.AssertNotCalled(t, "TranscribeAudio", mock.Anything, mock.Anything)
+
+	// The tenant's client is reused
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "tenant#support#support/again.mp3").Return(&model.TranscriptionItem{Status: model.StatusCompleted}, nil)
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "support/again.mp3"))
+	assert.Equal(t, []string{"elevenlabs/support"}, secrets)
+
+	// Files no tenant owns are skipped without creating a job
+	assert.NoError(t, processor.ProcessFile(ctx, "audio", "marketing/call.mp3"))
+	mockDynamoDBOps.AssertNotCalled(t, "GetTranscriptionItem", mock.Anything, "marketing/call.mp3")
+}
+
+func TestProcessFile_TenantRejected(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+
+	processor := &Processor{
+		elevenlabsClient:   new(MockElevenLabsClient),
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(testTenants(t), nil)
+
+	jobID := "tenant#support#support/notes.pdf"
+	var rejected *model.TranscriptionItem
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, jobID).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		rejected = args.Get(1).(*model.TranscriptionItem)
+	}).Return(nil)
+
+	err := processor.ProcessFile(context.Background(), "audio", "support/notes.pdf")
+	assert.ErrorContains(t, err, "rejected")
+
+	// The tenant's listing of rejected jobs filters on TenantID, so the item carries it
+	query := model.TranscriptionQuery{Status: model.StatusRejected, TenantID: "support"}
+	assert.Equal(t, jobID, rejected.FileIdentifier)
+	assert.Equal(t, query.Status, rejected.Status)
+	assert.Equal(t, query.TenantID, rejected.TenantID)
+}
+
+func TestProcessManifest_CrossTenant(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+
+	processor := &Processor{
+		elevenlabsClient:   new(MockElevenLabsClient),
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTenants(testTenants(t), nil)
+
+	manifest := `{"batchId": "batch-x", "files": [{"key": "support/call.mp3"}]}`
+	mockS3Ops.On("ReadObject", mock.Anything, "audio", "sales/batch-x.manifest.json").Return([]byte(manifest), nil)
+	mockDynamoDBOps.On("CreateBatchItem", mock.Anything, mock.MatchedBy(func(batch *model.BatchItem) bool {
+		return batch.TenantID == "sales"
+	})).Return(nil)
+	mockDynamoDBOps.On("RecordBatchProgress", mock.Anything, "sales", "batch-x", "tenant#support#support/call.mp3", false).Return(&model.BatchItem{BatchID: "batch-x"}, false, nil)
+
+	err := processor.ProcessManifest(context.Background(), "audio", "sales/batch-x.manifest.json")
+
+	assert.EqualError(t, err, "1 of 1 files in batch batch-x failed")
+	mockDynamoDBOps.AssertNotCalled(t, "GetTranscriptionItem", mock.Anything, mock.Anything)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestProcessFile_TenantOutputs(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+		outputBucket:       "shared-out",
+	}
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "support", "bucket": "audio", "prefix": "support/"},
+		{"id": "sales", "bucket": "audio", "prefix": "sales/"}
+	]}`))
+	assert.NoError(t, err)
+	processor.SetTenants(registry, nil)
+
+	// Tenants sharing the output bucket upload a file with the same name, and each gets its own transcript
+	ctx := context.Background()
+	for _, tenantID := range []string{"support", "sales"} {
+		key := tenantID + "/call.mp3"
+		jobID := model.TenantJobID(tenantID, key)
+		outputKey := "transcripts/" + tenantID + "/" + tenantID + "/call.mp3.txt"
+
+		mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, jobID).Return(nil, nil)
+		mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+		mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+		mockDynamoDBOps.On("AppendAttempt", mock.Anything, jobID, mock.Anything).Return(nil)
+		mockS3Ops.On("GeneratePresignedURL", mock.Anything, "audio", key, 3600).Return("https://presigned-"+tenantID, nil)
+		mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-"+tenantID).Return(&model.ElevenLabsResponse{Text: tenantID, Success: true}, nil)
+		mockS3Ops.On("UploadText", mock.Anything, "shared-out", outputKey, tenantID).Return(nil).Once()
+
+		assert.NoError(t, processor.ProcessFile(ctx, "audio", key))
+	}
+	mockS3Ops.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/envelope"
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// SetTranscriptStorage configures where transcripts too large to keep on the DynamoDB item are written.
+// An empty artifactsBucket uses the audio file's bucket; a zero inlineLimit uses model.DefaultInlineTranscriptLimit.
+func (p *Processor) SetTranscriptStorage(artifactsBucket string, inlineLimit int) {
+	p.artifactsBucket = artifactsBucket
+	p.inlineLimit = inlineLimit
+}
+
+// setTranscript adds the transcript to a completion update: inline when it's small enough, otherwise as a
+// reference to an S3 copy. A large transcript already uploaded to outputLocation is referenced there.
+func (p *Processor) setTranscript(ctx context.Context, update *awsclient.ItemUpdate, source model.JobSource, fileID, text, outputLocation string) error {
+	if text == "" {
+		return nil
+	}
+
+	limit := p.inlineLimit
+	if limit <= 0 {
+		limit = model.DefaultInlineTranscriptLimit
+	}
+	if len(text) <= limit {
+		// A previous attempt may have stored a large transcript externally
+		if p.encrypter == nil {
+			update.Set(awsclient.AttrTranscriptText, text).Remove(awsclient.AttrTranscriptRef, awsclient.AttrEncryptedTranscript)
+			return nil
+		}
+
+		sealed, err := p.encrypter.Seal(ctx, []byte(text), envelope.JobContext(fileID))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt transcript: %w", err)
+		}
+		update.Set(awsclient.AttrEncryptedTranscript, sealed).Remove(awsclient.AttrTranscriptText, awsclient.AttrTranscriptRef)
+		return nil
+	}
+
+	location := outputLocation
+	if location == "" {
+		artifactsBucket := p.artifactsBucket
+		if artifactsBucket == "" {
+			artifactsBucket = source.Bucket
+		}
+		key := model.TranscriptArtifactKey(fileID)
+
+		if err := p.uploadOutput(ctx, fileID, artifactsBucket, key, text); err != nil {
+			return fmt.Errorf("failed to store large transcript: %w", err)
+		}
+		location = fmt.Sprintf("s3://%s/%s", artifactsBucket, key)
+		p.tagOutput(ctx, source, artifactsBucket, key)
+	}
+
+	logging.FromContext(ctx).Info("Transcript is too large for the item, keeping a reference", "bytes", len(text), "location", location)
+	ref := model.NewTranscriptRef(location, text)
+	if p.encrypter != nil {
+		// The preview would be plaintext on the item
+		ref.Preview = ""
+	}
+	update.Set(awsclient.AttrTranscriptRef, ref).Remove(awsclient.AttrTranscriptText, awsclient.AttrEncryptedTranscript)
+	return nil
+}
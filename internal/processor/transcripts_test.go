@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestProcessFile_LargeTranscript(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetTranscriptStorage("", 16)
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "audio/long.aac"
+	text := strings.Repeat("word ", 200)
+
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    text,
+		Success: true,
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+
+	// With no output bucket the text goes to the default artifacts location in the source bucket
+	mockS3Ops.On("UploadText", mock.Anything, bucket, "artifacts/transcripts/audio/long.aac.txt", text).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		value, ok := update.Value(awsclient.AttrTranscriptRef)
+		if !ok || update.NewStatus() != model.StatusCompleted {
+			return false
+		}
+		ref := value.(*model.TranscriptRef)
+		_, hasText := update.Value(awsclient.AttrTranscriptText)
+		return ref.Location == "s3://test-bucket/artifacts/transcripts/audio/long.aac.txt" &&
+			ref.Size == len(text) &&
+			len(ref.Preview) == model.TranscriptPreviewLength &&
+			ref.Verify(text) == nil &&
+			!hasText &&
+			update.Removes(awsclient.AttrTranscriptText)
+	})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+
+	err := processor.ProcessFile(ctx, bucket, key)
+
+	assert.NoError(t, err)
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+}
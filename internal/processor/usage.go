@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/metrics"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/usage"
+)
+
+// SetUsage enables the usage ledger; every provider call that returned a transcript records what it
+// consumed
+func (p *Processor) SetUsage(ledger *usage.Ledger) {
+	p.ledger = ledger
+}
+
+// recordUsage adds an attempt's provider calls to the usage ledger: the calls whose transcripts were
+// replaced, then the call that completed the job, if it did. The transcript is already stored, so a
+// failure is logged rather than failing the job.
+func (p *Processor) recordUsage(ctx context.Context, fileID, tenantID, key string, attempt int, replaced []providerCall, final *providerCall) {
+	if p.ledger == nil {
+		return
+	}
+
+	record := func(call providerCall, number int, isReplaced bool) {
+		err := p.ledger.Record(ctx, &model.UsageEntry{
+			JobHash:      model.JobHash(fileID),
+			Attempt:      attempt,
+			Call:         number,
+			Replaced:     isReplaced,
+			TenantID:     tenantID,
+			Prefix:       metrics.Prefix(key),
+			Provider:     providerName(call.route),
+			ModelID:      call.route.ModelID,
+			AudioSeconds: audioSeconds(call.resp),
+			Characters:   utf8.RuneCountInString(call.resp.Text),
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to record usage", "call", number, logging.KeyError, err)
+		}
+	}
+
+	for i, call := range replaced {
+		record(call, i+1, true)
+	}
+	if final != nil {
+		record(*final, len(replaced)+1, false)
+	}
+}
@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/usage"
+)
+
+// MockUsageStore is a mock implementation of usage.Store
+type MockUsageStore struct {
+	mock.Mock
+}
+
+func (m *MockUsageStore) RecordUsage(ctx context.Context, entry *model.UsageEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func TestProcessFile_RecordsUsage(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	mockElevenLabsClient := new(MockElevenLabsClient)
+	store := new(MockUsageStore)
+
+	processor := &Processor{
+		elevenlabsClient:   mockElevenLabsClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetUsage(usage.NewLedger(store, usage.Prices{"elevenlabs": 0.006}))
+
+	bucket := "test-bucket"
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "calls/ok.mp3").Return(&model.TranscriptionItem{
+		FileIdentifier: "calls/ok.mp3",
+		Status:         model.StatusFailed,
+		History:        []model.AttemptRecord{{Status: model.StatusFailed}},
+	}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, mock.Anything, 3600).Return("https://presigned-url", nil)
+	mockElevenLabsClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:    "Grüße.",
+		Words:   []model.Word{{Text: "Grüße.", Start: 0.5, End: 90}},
+		Success: true,
+	}, nil)
+	store.On("RecordUsage", mock.Anything, mock.MatchedBy(func(entry *model.UsageEntry) bool {
+		return entry.JobHash == model.JobHash("calls/ok.mp3") &&
+			entry.Attempt == 2 &&
+			entry.Prefix == "calls/" &&
+			entry.Provider == "elevenlabs" &&
+			entry.AudioSeconds == 90 &&
+			entry.Characters == 6 &&
+			math.Abs(entry.EstimatedCost-0.009) < 1e-9
+	})).Return(nil)
+
+	assert.NoError(t, processor.ProcessFile(context.Background(), bucket, "calls/ok.mp3"))
+	store.AssertExpectations(t)
+
+	// Failed jobs aren't billed
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, "calls/bad.wma").Return(nil, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItemWithEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+
+	_ = processor.ProcessFile(context.Background(), bucket, "calls/bad.wma")
+	store.AssertNumberOfCalls(t, "RecordUsage", 1)
+}
+
+func TestProcessFile_RecordsReroutedUsage(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	defaultClient := new(MockElevenLabsClient)
+	japaneseClient := new(MockElevenLabsClient)
+	store := new(MockUsageStore)
+
+	processor := &Processor{
+		elevenlabsClient:   defaultClient,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.RegisterProvider("whisper", japaneseClient)
+	processor.SetLanguageRouting([]string{"en"}, map[string]model.LanguageRoute{
+		"ja": {Provider: "whisper", ModelID: "large-v3"},
+	})
+	processor.SetUsage(usage.NewLedger(store, usage.Prices{"elevenlabs": 0.006, "whisper": 0.003}))
+
+	key := "calls/ja.mp3"
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionLanguage", mock.Anything, key, "ja", 0.97, mock.Anything).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "bucket", key, 3600).Return("https://presigned-url", nil)
+
+	// The provider reports the length of the audio, trailing silence included
+	defaultClient.On("TranscribeAudio", mock.Anything, "https://presigned-url").Return(&model.ElevenLabsResponse{
+		Text:                "garbled",
+		LanguageCode:        "ja",
+		LanguageProbability: 0.97,
+		Words:               []model.Word{{Text: "garbled", Start: 0, End: 60}},
+		AudioDuration:       120,
+		Success:             true,
+	}, nil)
+	japaneseClient.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-url", mock.Anything).Return(&model.ElevenLabsResponse{
+		Text:    "こんにちは",
+		Success: true,
+	}, nil)
+
+	// Both calls are billed; only the one that produced the transcript counts as the job
+	store.On("RecordUsage", mock.Anything, mock.MatchedBy(func(entry *model.UsageEntry) bool {
+		return entry.Call == 1 &&
+			entry.Replaced &&
+			entry.Provider == "elevenlabs" &&
+			entry.AudioSeconds == 120 &&
+			entry.Characters == 7 &&
+			math.Abs(entry.EstimatedCost-0.012) < 1e-9
+	})).Return(nil).Once()
+	store.On("RecordUsage", mock.Anything, mock.MatchedBy(func(entry *model.UsageEntry) bool {
+		return entry.Call == 2 &&
+			!entry.Replaced &&
+			entry.Provider == "whisper" &&
+			entry.ModelID == "large-v3" &&
+			entry.AudioSeconds == 120 &&
+			entry.Characters == 5 &&
+			math.Abs(entry.EstimatedCost-0.006) < 1e-9
+	})).Return(nil).Once()
+
+	assert.NoError(t, processor.ProcessFile(context.Background(), "bucket", key))
+	store.AssertExpectations(t)
+
+	// A failed re-route still bills the detection call
+	failedKey := "calls/ja-failed.mp3"
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, failedKey).Return(nil, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItemWithEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, "bucket", failedKey, 3600).Return("https://presigned-failed", nil)
+	defaultClient.On("TranscribeAudio", mock.Anything, "https://presigned-failed").Return(&model.ElevenLabsResponse{
+		Text:          "garbled",
+		LanguageCode:  "ja",
+		AudioDuration: 30,
+		Success:       true,
+	}, nil)
+	japaneseClient.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-failed", mock.Anything).Return(nil, errors.New("unavailable"))
+	store.On("RecordUsage", mock.Anything, mock.MatchedBy(func(entry *model.UsageEntry) bool {
+		return entry.Call == 1 && entry.Replaced && entry.AudioSeconds == 30
+	})).Return(nil).Once()
+
+	assert.Error(t, processor.ProcessFile(context.Background(), "bucket", failedKey))
+	store.AssertExpectations(t)
+	store.AssertNumberOfCalls(t, "RecordUsage", 3)
+}
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// vocabularyCacheTTL is how long a loaded vocabulary file is reused before it is read again
+const vocabularyCacheTTL = 5 * time.Minute
+
+// KeywordBooster is implemented by transcription clients whose provider supports keyword boosting
+type KeywordBooster interface {
+	SupportsKeywords() bool
+}
+
+type cachedVocabulary struct {
+	vocabulary *model.Vocabulary
+	loadedAt   time.Time
+}
+
+// vocabularies loads vocabulary files from S3 and caches them for the life of the container
+type vocabularies struct {
+	sources []model.VocabularySource
+	mu      sync.Mutex
+	cache   map[string]cachedVocabulary
+}
+
+// SetVocabularies sets the custom vocabulary files used for jobs, by source key prefix
+func (p *Processor) SetVocabularies(sources []model.VocabularySource) {
+	p.vocabularies = &vocabularies{
+		sources: sources,
+		cache:   map[string]cachedVocabulary{},
+	}
+}
+
+// vocabularyFor returns the vocabulary for a job, or nil if none is configured
+func (p *Processor) vocabularyFor(ctx context.Context, job model.JobSource) (*model.Vocabulary, error) {
+	if p.vocabularies == nil {
+		return nil, nil
+	}
+	source, ok := model.VocabularyFor(p.vocabularies.sources, job)
+	if !ok {
+		return nil, nil
+	}
+
+	v := p.vocabularies
+	v.mu.Lock()
+	cached, ok := v.cache[source.Location]
+	v.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < vocabularyCacheTTL {
+		return cached.vocabulary, nil
+	}
+
+	bucket, objectKey, err := awsclient.ParseS3URI(source.Location)
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.s3Operations.ReadObject(ctx, bucket, objectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vocabulary %s: %w", source.Location, err)
+	}
+
+	vocabulary, err := parseVocabulary(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocabulary %s: %w", source.Location, err)
+	}
+	vocabulary.Location = source.Location
+
+	v.mu.Lock()
+	v.cache[source.Location] = cachedVocabulary{vocabulary: vocabulary, loadedAt: time.Now()}
+	v.mu.Unlock()
+	return vocabulary, nil
+}
+
+// parseVocabulary decodes a vocabulary file, versioning it by content when it doesn't declare a version
+func parseVocabulary(data []byte) (*model.Vocabulary, error) {
+	var vocabulary model.Vocabulary
+	if err := json.Unmarshal(data, &vocabulary); err != nil {
+		return nil, err
+	}
+	for _, correction := range vocabulary.Corrections {
+		if len(correction.From) == 0 || correction.To == "" {
+			return nil, fmt.Errorf("corrections need from and to")
+		}
+	}
+
+	if vocabulary.Version == "" {
+		sum := sha256.Sum256(data)
+		vocabulary.Version = hex.EncodeToString(sum[:])[:12]
+	}
+	return &vocabulary, nil
+}
+
+// keywordsFor returns the keywords to send to a client, if it can use them
+func keywordsFor(client TranscriptionClient, vocabulary *model.Vocabulary) []string {
+	if vocabulary == nil || len(vocabulary.Keywords) == 0 {
+		return nil
+	}
+	if booster, ok := client.(KeywordBooster); ok && booster.SupportsKeywords() {
+		return vocabulary.Keywords
+	}
+	return nil
+}
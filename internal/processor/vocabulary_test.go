@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/postprocess"
+)
+
+// MockBoostingClient is a transcription client whose provider supports keyword boosting
+type MockBoostingClient struct {
+	MockElevenLabsClient
+}
+
+func (m *MockBoostingClient) SupportsKeywords() bool {
+	return true
+}
+
+const testVocabulary = `{
+	"version": "2024-05-01",
+	"keywords": ["ElevenLabs", "Scribe"],
+	"corrections": [{"from": ["eleven labs"], "to": "ElevenLabs"}]
+}`
+
+func TestProcessFile_Vocabulary(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	mockDynamoDBOps := new(MockDynamoDBOperations)
+	client := new(MockBoostingClient)
+
+	processor := &Processor{
+		elevenlabsClient:   client,
+		s3Operations:       mockS3Ops,
+		dynamoDBOperations: mockDynamoDBOps,
+	}
+	processor.SetVocabularies([]model.VocabularySource{
+		{Location: "s3://config-bucket/vocabularies/default.json"},
+		{Scope: model.Scope{Bucket: "test-bucket", Prefix: "calls/"}, Location: "s3://config-bucket/vocabularies/calls.json"},
+	})
+	pipeline, err := postprocess.New([]postprocess.Spec{{Name: "vocabulary", OnError: postprocess.FailJob}},
+		map[string]postprocess.Stage{"vocabulary": postprocess.Vocabulary()})
+	assert.NoError(t, err)
+	processor.SetPostProcessing(pipeline)
+
+	ctx := context.Background()
+	bucket := "test-bucket"
+	key := "calls/support.mp3"
+
+	// The file is read once and cached for later jobs
+	mockS3Ops.On("ReadObject", mock.Anything, "config-bucket", "vocabularies/calls.json").Return([]byte(testVocabulary), nil).Once()
+	mockDynamoDBOps.On("GetTranscriptionItem", mock.Anything, key).Return(nil, nil)
+	mockDynamoDBOps.On("CreateTranscriptionItem", mock.Anything, mock.Anything).Return(nil)
+	mockS3Ops.On("GeneratePresignedURL", mock.Anything, bucket, key, 3600).Return("https://presigned-url", nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusTranscribing)).Return(nil)
+	client.On("TranscribeAudioWithOptions", mock.Anything, "https://presigned-url", model.TranscribeOptions{
+		Keywords: []string{"ElevenLabs", "Scribe"},
+	}).Return(&model.ElevenLabsResponse{Text: "Welcome to eleven labs", Success: true}, nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, statusUpdate(key, model.StatusPostprocessing)).Return(nil)
+	mockDynamoDBOps.On("UpdateTranscriptionItem", mock.Anything, mock.MatchedBy(func(update *awsclient.ItemUpdate) bool {
+		text, _ := update.Value(awsclient.AttrTranscriptText)
+		location, _ := update.Value(awsclient.AttrVocabulary)
+		version, _ := update.Value(awsclient.AttrVocabularyVersion)
+		return update.NewStatus() == model.StatusCompleted &&
+			text == "Welcome to ElevenLabs" &&
+			location == "s3://config-bucket/vocabularies/calls.json" &&
+			version == "2024-05-01"
+	})).Return(nil)
+	mockDynamoDBOps.On("AppendAttempt", mock.Anything, key, mock.Anything).Return(nil)
+
+	assert.NoError(t, processor.ProcessFile(ctx, bucket, key))
+	assert.NoError(t, processor.ProcessFile(ctx, bucket, key))
+
+	mockS3Ops.AssertExpectations(t)
+	mockDynamoDBOps.AssertExpectations(t)
+}
+
+func TestCallProvider_KeywordsOnlyForBoostingProviders(t *testing.T) {
+	client := new(MockElevenLabsClient)
+	processor := &Processor{elevenlabsClient: client}
+	vocabulary := &model.Vocabulary{Keywords: []string{"ElevenLabs"}}
+
+	ctx := context.Background()
+	client.On("TranscribeAudio", mock.Anything, "url").Return(&model.ElevenLabsResponse{Text: "hi", Success: true}, nil)
+
+	_, err := processor.callProvider(ctx, "url", model.LanguageRoute{}, "", vocabulary)
+
+	assert.NoError(t, err)
+	client.AssertNotCalled(t, "TranscribeAudioWithOptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVocabularyFor_Errors(t *testing.T) {
+	mockS3Ops := new(MockS3Operations)
+	processor := &Processor{s3Operations: mockS3Ops}
+	processor.SetVocabularies([]model.VocabularySource{{Scope: model.Scope{Bucket: "audio", Prefix: "calls/"}, Location: "s3://config-bucket/calls.json"}})
+
+	ctx := context.Background()
+	job := model.JobSource{Bucket: "audio", Key: "calls/a.mp3"}
+
+	vocabulary, err := processor.vocabularyFor(ctx, model.JobSource{Bucket: "audio", Key: "other/a.mp3"})
+	assert.NoError(t, err)
+	assert.Nil(t, vocabulary)
+
+	// The same key in another bucket isn't in scope
+	vocabulary, err = processor.vocabularyFor(ctx, model.JobSource{Bucket: "archive", Key: "calls/a.mp3"})
+	assert.NoError(t, err)
+	assert.Nil(t, vocabulary)
+
+	mockS3Ops.On("ReadObject", mock.Anything, "config-bucket", "calls.json").Return(nil, errors.New("access denied")).Once()
+	_, err = processor.vocabularyFor(ctx, job)
+	assert.EqualError(t, err, "failed to read vocabulary s3://config-bucket/calls.json: access denied")
+
+	mockS3Ops.On("ReadObject", mock.Anything, "config-bucket", "calls.json").Return([]byte(`{"corrections": [{"from": []}]}`), nil).Once()
+	_, err = processor.vocabularyFor(ctx, job)
+	assert.EqualError(t, err, "invalid vocabulary s3://config-bucket/calls.json: corrections need from and to")
+}
+
+func TestParseVocabulary_DefaultVersion(t *testing.T) {
+	vocabulary, err := parseVocabulary([]byte(`{"keywords": ["Scribe"]}`))
+
+	assert.NoError(t, err)
+	assert.Len(t, vocabulary.Version, 12)
+}
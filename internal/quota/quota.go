@@ -0,0 +1,167 @@
+// Package quota keeps one tenant's bulk upload from starving the others. Each tenant may have a limit
+// on how many of its jobs run at once and on how many minutes of audio it transcribes per UTC day;
+// both are enforced with atomic counters in DynamoDB. Jobs over quota are deferred, not failed, and
+// the backlog of deferred jobs is drained in weighted fair-share order.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// DefaultRetryDelay is how long a job deferred for the concurrency limit waits before it's dispatched again
+const DefaultRetryDelay = time.Minute
+
+// DefaultDrainBatchSize is how many deferred jobs one drain of the backlog dispatches at most
+const DefaultDrainBatchSize = 25
+
+// SlotLease is how long an admitted job holds its running slot at most. It outlasts the 15 minutes a
+// Lambda invocation may run, so only slots whose job was lost without releasing them expire.
+const SlotLease = 20 * time.Minute
+
+// Store keeps the per-tenant running slots and minute counters
+type Store interface {
+	AcquireJobSlot(ctx context.Context, tenantID, jobID string, limit int, expires time.Time) (bool, error)
+	ReleaseJobSlot(ctx context.Context, tenantID, jobID string) error
+	AddTenantMinutes(ctx context.Context, tenantID, day string, minutes float64) error
+	GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error)
+}
+
+// Decision is the outcome of asking to start a tenant's job
+type Decision struct {
+	// Admitted is true when the job may start; it then holds one of the tenant's running slots
+	Admitted bool
+
+	// Reason explains why a job was deferred
+	Reason string
+
+	// RetryAt is when a deferred job may be dispatched again
+	RetryAt time.Time
+}
+
+// Limiter enforces tenant quotas
+type Limiter struct {
+	store      Store
+	retryDelay time.Duration
+	now        func() time.Time
+}
+
+// NewLimiter creates a limiter. Jobs deferred because the tenant has too many running are retried
+// after retryDelay; jobs deferred for the daily minutes wait for the next UTC day.
+func NewLimiter(store Store, retryDelay time.Duration) *Limiter {
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelay
+	}
+	return &Limiter{
+		store:      store,
+		retryDelay: retryDelay,
+		now:        time.Now,
+	}
+}
+
+// Admit decides whether a tenant's job may start now. Every admitted job must be released with Release;
+// a slot that isn't expires after SlotLease. The daily minutes are checked against what finished jobs
+// used, so the job that crosses the quota still runs to completion.
+func (l *Limiter) Admit(ctx context.Context, t *tenant.Tenant, jobID string) (Decision, error) {
+	now := l.now().UTC()
+
+	if t.Quotas.DailyMinutes > 0 {
+		used, err := l.store.GetTenantUsage(ctx, t.ID, now.Format(model.UsageDayFormat))
+		if err != nil {
+			return Decision{}, err
+		}
+		if used.Minutes >= t.Quotas.DailyMinutes {
+			return Decision{
+				Reason:  fmt.Sprintf("daily quota of %g minutes used", t.Quotas.DailyMinutes),
+				RetryAt: now.Truncate(24 * time.Hour).Add(24 * time.Hour),
+			}, nil
+		}
+	}
+
+	// Every tenant's running jobs are counted, limited or not, for fair-share ordering
+	acquired, err := l.store.AcquireJobSlot(ctx, t.ID, jobID, t.Quotas.MaxConcurrentJobs, now.Add(SlotLease))
+	if err != nil {
+		return Decision{}, err
+	}
+	if !acquired {
+		return Decision{
+			Reason:  fmt.Sprintf("%d jobs already running", t.Quotas.MaxConcurrentJobs),
+			RetryAt: now.Add(l.retryDelay),
+		}, nil
+	}
+
+	return Decision{Admitted: true}, nil
+}
+
+// Release gives back the running slot of an admitted job
+func (l *Limiter) Release(ctx context.Context, t *tenant.Tenant, jobID string) error {
+	return l.store.ReleaseJobSlot(ctx, t.ID, jobID)
+}
+
+// Consume counts the audio a tenant's job transcribed against the tenant's daily minutes
+func (l *Limiter) Consume(ctx context.Context, t *tenant.Tenant, seconds float64) error {
+	if seconds <= 0 {
+		return nil
+	}
+	return l.store.AddTenantMinutes(ctx, t.ID, l.now().UTC().Format(model.UsageDayFormat), seconds/60)
+}
+
+// FairShare orders a backlog of deferred jobs for dispatch and returns at most limit of them (all when
+// limit is zero). Each pick goes to the tenant with the fewest running and already picked jobs relative
+// to its weight, so a tenant with weight 2 gets twice the slots of a tenant with weight 1 however many
+// jobs either has queued. Tenants at their concurrency limit get nothing more; within a tenant the
+// oldest job goes first.
+func FairShare(backlog []model.TranscriptionItem, running map[string]int, tenants *tenant.Registry, limit int) []model.TranscriptionItem {
+	queues := map[string][]model.TranscriptionItem{}
+	for _, item := range backlog {
+		queues[item.TenantID] = append(queues[item.TenantID], item)
+	}
+
+	ids := make([]string, 0, len(queues))
+	load := map[string]int{}
+	for id, queue := range queues {
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].CreatedAt.Before(queue[j].CreatedAt)
+		})
+		ids = append(ids, id)
+		load[id] = running[id]
+	}
+	sort.Strings(ids)
+
+	var order []model.TranscriptionItem
+	for limit <= 0 || len(order) < limit {
+		next, nextShare, found := "", 0.0, false
+		for _, id := range ids {
+			if len(queues[id]) == 0 {
+				continue
+			}
+
+			var quotas tenant.Quotas
+			if t, ok := tenants.Get(id); ok {
+				quotas = t.Quotas
+			}
+			if quotas.MaxConcurrentJobs > 0 && load[id] >= quotas.MaxConcurrentJobs {
+				continue
+			}
+
+			share := float64(load[id]) / quotas.ShareWeight()
+			if !found || share < nextShare {
+				next, nextShare, found = id, share, true
+			}
+		}
+		if !found {
+			break
+		}
+
+		order = append(order, queues[next][0])
+		queues[next] = queues[next][1:]
+		load[next]++
+	}
+
+	return order
+}
@@ -0,0 +1,137 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// MockStore is a mock implementation of Store
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) AcquireJobSlot(ctx context.Context, tenantID, jobID string, limit int, expires time.Time) (bool, error) {
+	args := m.Called(ctx, tenantID, jobID, limit, expires)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseJobSlot(ctx context.Context, tenantID, jobID string) error {
+	args := m.Called(ctx, tenantID, jobID)
+	return args.Error(0)
+}
+
+func (m *MockStore) AddTenantMinutes(ctx context.Context, tenantID, day string, minutes float64) error {
+	args := m.Called(ctx, tenantID, day, minutes)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetTenantUsage(ctx context.Context, tenantID, day string) (*model.QuotaUsage, error) {
+	args := m.Called(ctx, tenantID, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.QuotaUsage), args.Error(1)
+}
+
+func testRegistry(t *testing.T) *tenant.Registry {
+	registry, err := tenant.Parse([]byte(`{"tenants": [
+		{"id": "bulk", "bucket": "audio", "prefix": "bulk/", "quotas": {"maxConcurrentJobs": 2}},
+		{"id": "support", "bucket": "audio", "prefix": "support/", "quotas": {"dailyMinutes": 60, "weight": 2}},
+		{"id": "sales", "bucket": "audio", "prefix": "sales/"}
+	]}`))
+	assert.NoError(t, err)
+	return registry
+}
+
+func TestAdmit(t *testing.T) {
+	store := new(MockStore)
+	limiter := NewLimiter(store, 5*time.Minute)
+	limiter.now = func() time.Time { return time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC) }
+	registry := testRegistry(t)
+	ctx := context.Background()
+
+	bulk, _ := registry.Get("bulk")
+	support, _ := registry.Get("support")
+
+	// Under the concurrency limit the job takes a slot, leased for longer than an invocation can run
+	lease := time.Date(2024, 3, 1, 15, 50, 0, 0, time.UTC)
+	store.On("AcquireJobSlot", ctx, "bulk", "tenant#bulk#bulk/a.mp3", 2, lease).Return(true, nil).Once()
+	decision, err := limiter.Admit(ctx, bulk, "tenant#bulk#bulk/a.mp3")
+	assert.NoError(t, err)
+	assert.True(t, decision.Admitted)
+
+	// At the limit it waits for the retry delay
+	store.On("AcquireJobSlot", ctx, "bulk", "tenant#bulk#bulk/b.mp3", 2, lease).Return(false, nil).Once()
+	decision, err = limiter.Admit(ctx, bulk, "tenant#bulk#bulk/b.mp3")
+	assert.NoError(t, err)
+	assert.False(t, decision.Admitted)
+	assert.Equal(t, "2 jobs already running", decision.Reason)
+	assert.Equal(t, time.Date(2024, 3, 1, 15, 35, 0, 0, time.UTC), decision.RetryAt)
+
+	// With the day's minutes used up it waits for the next day without taking a slot
+	store.On("GetTenantUsage", ctx, "support", "2024-03-01").Return(&model.QuotaUsage{Minutes: 61}, nil).Once()
+	decision, err = limiter.Admit(ctx, support, "tenant#support#support/a.mp3")
+	assert.NoError(t, err)
+	assert.False(t, decision.Admitted)
+	assert.Equal(t, "daily quota of 60 minutes used", decision.Reason)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), decision.RetryAt)
+	store.AssertNotCalled(t, "AcquireJobSlot", ctx, "support", mock.Anything, mock.Anything, mock.Anything)
+
+	store.AssertExpectations(t)
+}
+
+func TestConsume(t *testing.T) {
+	store := new(MockStore)
+	limiter := NewLimiter(store, 0)
+	limiter.now = func() time.Time { return time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC) }
+	ctx := context.Background()
+	sales := &tenant.Tenant{ID: "sales"}
+
+	store.On("AddTenantMinutes", ctx, "sales", "2024-03-01", 1.5).Return(nil)
+
+	assert.NoError(t, limiter.Consume(ctx, sales, 90))
+	assert.NoError(t, limiter.Consume(ctx, sales, 0))
+	store.AssertNumberOfCalls(t, "AddTenantMinutes", 1)
+}
+
+func TestFairShare(t *testing.T) {
+	registry := testRegistry(t)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var backlog []model.TranscriptionItem
+	add := func(tenantID string, n int) {
+		for i := 0; i < n; i++ {
+			backlog = append(backlog, model.TranscriptionItem{
+				FileIdentifier: model.TenantJobID(tenantID, string(rune('a'+i))),
+				TenantID:       tenantID,
+				CreatedAt:      start.Add(time.Duration(i) * time.Minute),
+			})
+		}
+	}
+	// The bulk upload arrived first and is much larger
+	add("bulk", 50)
+	add("support", 4)
+	add("sales", 2)
+
+	order := FairShare(backlog, map[string]int{"bulk": 1}, registry, 6)
+
+	var tenants []string
+	for _, item := range order {
+		tenants = append(tenants, item.TenantID)
+	}
+	// bulk already has one running and a limit of 2; support has twice the weight of sales
+	assert.Equal(t, []string{"sales", "support", "support", "bulk", "sales", "support"}, tenants)
+
+	// Within a tenant the oldest job goes first
+	assert.Equal(t, "tenant#support#a", order[1].FileIdentifier)
+	assert.Equal(t, "tenant#support#b", order[2].FileIdentifier)
+
+	// Without a limit everything dispatchable is returned
+	assert.Len(t, FairShare(backlog, nil, registry, 0), 2+4+2)
+}
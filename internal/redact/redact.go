@@ -0,0 +1,198 @@
+// Package redact finds personal data in transcripts and masks it in both the text and the timed words.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/transcript"
+)
+
+// Pattern detects one type of personal data
+type Pattern struct {
+	// Name identifies the type in masks ("[CARD]") and redaction counts
+	Name string
+
+	// Regexp finds candidate matches
+	Regexp *regexp.Regexp
+
+	// Validate, if set, rejects candidates that only look like a match, such as digit runs that fail the
+	// Luhn check
+	Validate func(match string) bool
+}
+
+// Built-in pattern sets, selected by name
+var builtins = map[string]Pattern{
+	"card": {
+		Name:     "card",
+		Regexp:   regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate: validCard,
+	},
+	"ssn": {
+		Name:     "ssn",
+		Regexp:   regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		Validate: validSSN,
+	},
+	"phone": {
+		Name:   "phone",
+		Regexp: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\b\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`),
+	},
+	"email": {
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	},
+}
+
+// DefaultPatternSets are the built-in sets in the order they are matched. Earlier sets win where matches
+// overlap, so card numbers aren't mistaken for phone numbers.
+var DefaultPatternSets = []string{"card", "ssn", "phone", "email"}
+
+// Redactor masks personal data
+type Redactor struct {
+	patterns []Pattern
+}
+
+// NewRedactor creates a redactor for the named built-in pattern sets followed by custom patterns,
+// given as name to regular expression
+func NewRedactor(sets []string, custom map[string]string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, set := range sets {
+		pattern, ok := builtins[strings.ToLower(set)]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction pattern set %q", set)
+		}
+		r.patterns = append(r.patterns, pattern)
+	}
+
+	// Map order is random; sort so overlapping custom patterns resolve the same way every time
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		re, err := regexp.Compile(custom[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", name, err)
+		}
+		r.patterns = append(r.patterns, Pattern{Name: name, Regexp: re})
+	}
+
+	return r, nil
+}
+
+// Span is a match of a pattern in a string, by byte offsets
+type Span struct {
+	Start int
+	End   int
+	Name  string
+}
+
+// Result is a redacted transcript
+type Result struct {
+	// Text is the transcript text with every match replaced by its mask
+	Text string
+
+	// Words are the timed words with matches masked. A match spanning several words becomes a single
+	// word covering their combined time (see transcript.RewriteWords), so subtitles stay in sync.
+	Words []model.Word
+
+	// Counts is the number of matches in the text, by pattern name
+	Counts map[string]int
+}
+
+// Redacted reports whether anything was masked
+func (r *Result) Redacted() bool {
+	return len(r.Counts) > 0
+}
+
+// Find returns the non-overlapping matches in s, ordered by position
+func (r *Redactor) Find(s string) []Span {
+	var spans []Span
+	for _, pattern := range r.patterns {
+		for _, loc := range pattern.Regexp.FindAllStringIndex(s, -1) {
+			if loc[0] == loc[1] || overlaps(spans, loc[0], loc[1]) {
+				continue
+			}
+			if pattern.Validate != nil && !pattern.Validate(s[loc[0]:loc[1]]) {
+				continue
+			}
+			spans = append(spans, Span{Start: loc[0], End: loc[1], Name: pattern.Name})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	return spans
+}
+
+// Redact masks personal data in a transcript's text and words
+func (r *Redactor) Redact(text string, words []model.Word) *Result {
+	spans := r.Find(text)
+	result := &Result{
+		Text:   transcript.ApplyEdits(text, masks(spans)),
+		Words:  transcript.RewriteWords(words, func(joined string) []transcript.Edit { return masks(r.Find(joined)) }),
+		Counts: map[string]int{},
+	}
+	for _, span := range spans {
+		result.Counts[span.Name]++
+	}
+	if len(result.Counts) == 0 {
+		result.Counts = nil
+	}
+	return result
+}
+
+// masks returns the edits replacing each span with "[NAME]"
+func masks(spans []Span) []transcript.Edit {
+	edits := make([]transcript.Edit, len(spans))
+	for i, span := range spans {
+		edits[i] = transcript.Edit{Start: span.Start, End: span.End, Text: "[" + strings.ToUpper(span.Name) + "]"}
+	}
+	return edits
+}
+
+// overlaps reports whether [start, end) overlaps any span
+func overlaps(spans []Span, start, end int) bool {
+	for _, span := range spans {
+		if start < span.End && span.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// validCard reports whether a digit run is a plausible card number: 13 to 19 digits passing the Luhn check
+func validCard(match string) bool {
+	var digits []int
+	for _, c := range match {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validSSN rejects numbers the SSA never issues: area 000, 666 or 9xx, group 00 and serial 0000
+func validSSN(match string) bool {
+	area, group, serial := match[0:3], match[4:6], match[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
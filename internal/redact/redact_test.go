@@ -0,0 +1,82 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestRedact_Text(t *testing.T) {
+	r, err := NewRedactor(DefaultPatternSets, map[string]string{"account": `ACC-\d{6}`})
+	assert.NoError(t, err)
+
+	result := r.Redact(
+		"Card 4111 1111 1111 1111, not 4111 1111 1111 1112. "+
+			"Call (555) 123-4567 or mail jo.doe@example.co.uk. SSN 123-45-6789, account ACC-004211.",
+		nil)
+
+	assert.Equal(t,
+		"Card [CARD], not 4111 1111 1111 1112. "+
+			"Call [PHONE] or mail [EMAIL]. SSN [SSN], account [ACCOUNT].",
+		result.Text)
+	assert.Equal(t, map[string]int{"card": 1, "phone": 1, "email": 1, "ssn": 1, "account": 1}, result.Counts)
+	assert.True(t, result.Redacted())
+}
+
+func TestRedact_Nothing(t *testing.T) {
+	r, err := NewRedactor(DefaultPatternSets, nil)
+	assert.NoError(t, err)
+
+	result := r.Redact("Thanks for calling.", nil)
+
+	assert.Equal(t, "Thanks for calling.", result.Text)
+	assert.False(t, result.Redacted())
+}
+
+func TestRedact_WordsStayAligned(t *testing.T) {
+	r, err := NewRedactor([]string{"phone", "email"}, nil)
+	assert.NoError(t, err)
+
+	words := []model.Word{
+		{Text: "Call", Start: 0.0, End: 0.3, Type: "word"},
+		{Text: " ", Start: 0.3, End: 0.4, Type: "spacing"},
+		{Text: "555", Start: 0.4, End: 0.9, Type: "word"},
+		{Text: " ", Start: 0.9, End: 1.0, Type: "spacing"},
+		{Text: "123", Start: 1.0, End: 1.5, Type: "word"},
+		{Text: " ", Start: 1.5, End: 1.6, Type: "spacing"},
+		{Text: "4567.", Start: 1.6, End: 2.2, Type: "word"},
+		{Text: " ", Start: 2.2, End: 2.3, Type: "spacing"},
+		{Text: "Or", Start: 2.3, End: 2.5, Type: "word"},
+		{Text: " ", Start: 2.5, End: 2.6, Type: "spacing"},
+		{Text: "a@b.com.", Start: 2.6, End: 3.4, Type: "word"},
+	}
+
+	result := r.Redact("Call 555 123 4567. Or a@b.com.", words)
+
+	assert.Equal(t, "Call [PHONE]. Or [EMAIL].", result.Text)
+	assert.Equal(t, []model.Word{
+		{Text: "Call", Start: 0.0, End: 0.3, Type: "word"},
+		{Text: " ", Start: 0.3, End: 0.4, Type: "spacing"},
+		{Text: "[PHONE].", Start: 0.4, End: 2.2, Type: "word"},
+		{Text: " ", Start: 2.2, End: 2.3, Type: "spacing"},
+		{Text: "Or", Start: 2.3, End: 2.5, Type: "word"},
+		{Text: " ", Start: 2.5, End: 2.6, Type: "spacing"},
+		{Text: "[EMAIL].", Start: 2.6, End: 3.4, Type: "word"},
+	}, result.Words)
+}
+
+func TestNewRedactor_Invalid(t *testing.T) {
+	_, err := NewRedactor([]string{"passport"}, nil)
+	assert.EqualError(t, err, `unknown redaction pattern set "passport"`)
+
+	_, err = NewRedactor(nil, map[string]string{"broken": `(`})
+	assert.Error(t, err)
+}
+
+func TestValidCard(t *testing.T) {
+	assert.True(t, validCard("4111111111111111"))
+	assert.True(t, validCard("5500-0000-0000-0004"))
+	assert.False(t, validCard("4111111111111112"))
+	assert.False(t, validCard("411111111111"))
+}
@@ -0,0 +1,165 @@
+// Package settings serves runtime settings that operators change without a redeploy: feature flags,
+// tenant quota overrides, the default provider and guardrails. The settings document lives in SSM
+// Parameter Store, S3 or AppConfig and is cached across warm invocations, so a change reaches every
+// function within one TTL.
+package settings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/logging"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// DefaultTTL is how long fetched settings are used before they are fetched again
+const DefaultTTL = time.Minute
+
+// Feature flags; every feature is on unless its flag turns it off
+const (
+	// FlagVocabularies applies custom vocabularies
+	FlagVocabularies = "vocabularies"
+)
+
+// Settings is one version of the runtime settings document, e.g.
+// {"version":"42","flags":{"vocabularies":false},"maxAudioBytes":524288000,"tenantQuotas":{"bulk":{"maxConcurrentJobs":2}}}
+type Settings struct {
+	// Version identifies the document in logs and on job items; when the document doesn't set it, it
+	// is derived from the content
+	Version string `json:"version,omitempty"`
+
+	// Flags switch features off without a redeploy
+	Flags map[string]bool `json:"flags,omitempty"`
+
+	// Provider and ModelID are the route for jobs that don't name one, ahead of the tenant's defaults
+	Provider string `json:"provider,omitempty"`
+	ModelID  string `json:"modelId,omitempty"`
+
+	// MaxAudioBytes rejects larger audio files; zero allows any size
+	MaxAudioBytes int64 `json:"maxAudioBytes,omitempty"`
+
+	// TenantQuotas replace the registry's quotas of the named tenants
+	TenantQuotas map[string]tenant.Quotas `json:"tenantQuotas,omitempty"`
+}
+
+// Parse reads a settings document
+func Parse(data []byte) (*Settings, error) {
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	var problems []string
+	if s.Provider != "" && !model.IsProvider(s.Provider) {
+		problems = append(problems, fmt.Sprintf("provider %q is not available (expected one of %s)", s.Provider, strings.Join(model.Providers, ", ")))
+	}
+	if s.MaxAudioBytes < 0 {
+		problems = append(problems, "maxAudioBytes must not be negative")
+	}
+	for id, q := range s.TenantQuotas {
+		if q.MaxConcurrentJobs < 0 || q.DailyMinutes < 0 || q.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("tenant %s: quotas must not be negative", id))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid settings: %s", strings.Join(problems, "; "))
+	}
+
+	if s.Version == "" {
+		sum := sha256.Sum256(data)
+		s.Version = hex.EncodeToString(sum[:6])
+	}
+	return &s, nil
+}
+
+// Enabled reports whether a feature is on
+func (s *Settings) Enabled(flag string) bool {
+	on, ok := s.Flags[flag]
+	return on || !ok
+}
+
+// JobOptions fills in the default route for jobs that don't name a provider or model
+func (s *Settings) JobOptions(opts model.JobOptions) model.JobOptions {
+	if opts.Provider == "" && opts.ModelID == "" {
+		opts.Provider = s.Provider
+		opts.ModelID = s.ModelID
+	}
+	return opts
+}
+
+// Tenant returns the tenant with its quotas overridden, or the tenant itself when nothing overrides them
+func (s *Settings) Tenant(t *tenant.Tenant) *tenant.Tenant {
+	q, ok := s.TenantQuotas[t.ID]
+	if !ok {
+		return t
+	}
+
+	overridden := *t
+	overridden.Quotas = q
+	return &overridden
+}
+
+// Cache holds the settings fetched from a source for the TTL. Settings that can't be fetched or parsed
+// don't replace the last good ones.
+type Cache struct {
+	source Source
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	current   *Settings
+	fetchedAt time.Time
+}
+
+// NewCache creates a cache refreshing the settings from source once they are older than ttl
+func NewCache(source Source, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		source:  source,
+		ttl:     ttl,
+		now:     time.Now,
+		current: &Settings{},
+	}
+}
+
+// Get returns the current settings. A nil cache, as in deployments without runtime settings, and a
+// cache that never fetched the settings successfully return the defaults.
+func (c *Cache) Get(ctx context.Context) *Settings {
+	if c == nil {
+		return &Settings{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.fetchedAt.IsZero() && now.Sub(c.fetchedAt) < c.ttl {
+		return c.current
+	}
+	// Failed fetches wait for the TTL too, so a broken source isn't called on every job
+	c.fetchedAt = now
+
+	data, err := c.source.Fetch(ctx)
+	if err == nil {
+		var fetched *Settings
+		if fetched, err = Parse(data); err == nil {
+			if fetched.Version != c.current.Version {
+				logging.FromContext(ctx).Info("Loaded runtime settings", "version", fetched.Version, "previous", c.current.Version)
+			}
+			c.current = fetched
+			return c.current
+		}
+	}
+
+	logging.FromContext(ctx).Warn("Keeping last known good runtime settings", "version", c.current.Version, logging.KeyError, err)
+	return c.current
+}
@@ -0,0 +1,116 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+	"github.com/yourusername/transcription-service/internal/tenant"
+)
+
+// sourceFunc adapts a function to Source
+type sourceFunc func(ctx context.Context) ([]byte, error)
+
+func (f sourceFunc) Fetch(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`{"version": "7", "flags": {"vocabularies": false}, "provider": "elevenlabs",
+		"tenantQuotas": {"bulk": {"maxConcurrentJobs": 4}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "7", s.Version)
+	assert.False(t, s.Enabled(FlagVocabularies))
+	assert.True(t, s.Enabled("unknown"))
+
+	// Jobs and tenants that name a route keep it
+	assert.Equal(t, model.JobOptions{Provider: "elevenlabs"}, s.JobOptions(model.JobOptions{}))
+	assert.Equal(t, model.JobOptions{ModelID: "scribe_v1"}, s.JobOptions(model.JobOptions{ModelID: "scribe_v1"}))
+
+	bulk := &tenant.Tenant{ID: "bulk", Quotas: tenant.Quotas{MaxConcurrentJobs: 1, Weight: 2}}
+	assert.Equal(t, tenant.Quotas{MaxConcurrentJobs: 4}, s.Tenant(bulk).Quotas)
+	assert.Equal(t, 1, bulk.Quotas.MaxConcurrentJobs)
+	sales := &tenant.Tenant{ID: "sales"}
+	assert.Same(t, sales, s.Tenant(sales))
+
+	// Without a version the content identifies the document
+	s, err = Parse([]byte(`{"maxAudioBytes": 1024}`))
+	assert.NoError(t, err)
+	assert.Len(t, s.Version, 12)
+
+	_, err = Parse([]byte(`{"provider": "whisper", "maxAudioBytes": -1, "tenantQuotas": {"bulk": {"dailyMinutes": -5}}}`))
+	assert.EqualError(t, err, `invalid settings: provider "whisper" is not available (expected one of elevenlabs); `+
+		"maxAudioBytes must not be negative; tenant bulk: quotas must not be negative")
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fetches := 0
+	document := `{"version": "1"}`
+	var fetchErr error
+
+	cache := NewCache(sourceFunc(func(ctx context.Context) ([]byte, error) {
+		fetches++
+		return []byte(document), fetchErr
+	}), time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	assert.Equal(t, "1", cache.Get(ctx).Version)
+
+	// Within the TTL the cached settings are used
+	document = `{"version": "2"}`
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, "1", cache.Get(ctx).Version)
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, "2", cache.Get(ctx).Version)
+
+	// Fetch and parse failures keep the last known good settings until the next TTL
+	fetchErr = errors.New("throttled")
+	now = now.Add(time.Minute)
+	assert.Equal(t, "2", cache.Get(ctx).Version)
+	fetchErr = nil
+	document = `{"maxAudioBytes": -1}`
+	assert.Equal(t, "2", cache.Get(ctx).Version)
+	assert.Equal(t, 3, fetches)
+	now = now.Add(time.Minute)
+	assert.Equal(t, "2", cache.Get(ctx).Version)
+	assert.Equal(t, 4, fetches)
+
+	// Without settings, or before any were fetched, the defaults apply
+	var none *Cache
+	assert.Equal(t, &Settings{}, none.Get(ctx))
+	broken := NewCache(sourceFunc(func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("access denied")
+	}), 0)
+	assert.Equal(t, &Settings{}, broken.Get(ctx))
+}
+
+func TestNewSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/applications/transcription/environments/prod/configurations/runtime", r.URL.Path)
+		w.Write([]byte(`{"version": "3"}`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	t.Setenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT", serverURL.Port())
+
+	source, err := NewSource("appconfig:transcription/prod/runtime", nil, nil)
+	assert.NoError(t, err)
+	data, err := source.Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"version": "3"}`, string(data))
+
+	_, err = NewSource("appconfig:transcription/prod", nil, nil)
+	assert.Error(t, err)
+	_, err = NewSource("dynamodb://settings", nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+)
+
+// Source fetches the settings document
+type Source interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// ParameterReader reads SSM parameters
+type ParameterReader interface {
+	GetParameter(ctx context.Context, name string) (string, error)
+}
+
+// ObjectReader reads S3 objects
+type ObjectReader interface {
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// defaultAppConfigPort is where the AppConfig Lambda extension listens unless configured otherwise
+const defaultAppConfigPort = "2772"
+
+// NewSource returns the source for a location: ssm:<parameter>, s3://bucket/key or
+// appconfig:<application>/<environment>/<profile>. AppConfig is read through the AppConfig Lambda
+// extension, which does its own polling.
+func NewSource(location string, parameters ParameterReader, objects ObjectReader) (Source, error) {
+	switch {
+	case strings.HasPrefix(location, "ssm:"):
+		return parameterSource{reader: parameters, name: strings.TrimPrefix(location, "ssm:")}, nil
+
+	case strings.HasPrefix(location, "s3://"):
+		bucket, key, err := awsclient.ParseS3URI(location)
+		if err != nil {
+			return nil, err
+		}
+		return objectSource{reader: objects, bucket: bucket, key: key}, nil
+
+	case strings.HasPrefix(location, "appconfig:"):
+		parts := strings.Split(strings.TrimPrefix(location, "appconfig:"), "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid AppConfig location %q, expected appconfig:<application>/<environment>/<profile>", location)
+		}
+		port := os.Getenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+		if port == "" {
+			port = defaultAppConfigPort
+		}
+		return appConfigSource{
+			client: http.DefaultClient,
+			url:    fmt.Sprintf("http://localhost:%s/applications/%s/environments/%s/configurations/%s", port, parts[0], parts[1], parts[2]),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid settings location %q, expected ssm:<parameter>, s3://bucket/key or appconfig:<application>/<environment>/<profile>", location)
+	}
+}
+
+type parameterSource struct {
+	reader ParameterReader
+	name   string
+}
+
+func (s parameterSource) Fetch(ctx context.Context) ([]byte, error) {
+	value, err := s.reader.GetParameter(ctx, s.name)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+type objectSource struct {
+	reader      ObjectReader
+	bucket, key string
+}
+
+func (s objectSource) Fetch(ctx context.Context) ([]byte, error) {
+	return s.reader.ReadObject(ctx, s.bucket, s.key)
+}
+
+type appConfigSource struct {
+	client *http.Client
+	url    string
+}
+
+func (s appConfigSource) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the AppConfig extension: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("AppConfig extension returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,219 @@
+// Package tenant maps input locations to the teams that own them. One deployment serves several
+// tenants; each has its own input bucket or prefix, provider API key, output location, quotas and
+// default job options, and its jobs are stored under tenant-partitioned identifiers.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/awsclient"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Tenant is one team served by the deployment
+type Tenant struct {
+	// ID names the tenant in job identifiers; lowercase letters, digits and dashes
+	ID string `json:"id"`
+
+	// Bucket and Prefix select the input objects that belong to the tenant
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix,omitempty"`
+
+	// SecretName is the Secrets Manager secret with the tenant's provider API key; empty uses the default
+	SecretName string `json:"secretName,omitempty"`
+
+	// OutputBucket receives the tenant's transcripts; empty uses the default
+	OutputBucket string `json:"outputBucket,omitempty"`
+
+	// Quotas limit the tenant's use of the shared deployment
+	Quotas Quotas `json:"quotas,omitempty"`
+
+	// Options are defaults for the tenant's jobs; options given with a job take precedence
+	Options model.JobOptions `json:"options,omitempty"`
+}
+
+// Quotas limit a tenant's use of the shared deployment. Zero means unlimited.
+type Quotas struct {
+	// MaxConcurrentJobs is how many of the tenant's jobs may be transcribing at once
+	MaxConcurrentJobs int `json:"maxConcurrentJobs,omitempty"`
+
+	// DailyMinutes is how many minutes of audio the tenant may transcribe per UTC day
+	DailyMinutes float64 `json:"dailyMinutes,omitempty"`
+
+	// Weight is the tenant's share of the deployment when the backlog is drained, relative to other
+	// tenants; zero counts as 1
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// ShareWeight returns the weight used for fair-share ordering
+func (q Quotas) ShareWeight() float64 {
+	if q.Weight <= 0 {
+		return 1
+	}
+	return q.Weight
+}
+
+// Owns reports whether an input object belongs to the tenant
+func (t *Tenant) Owns(bucket, key string) bool {
+	return t.Bucket == bucket && strings.HasPrefix(key, t.Prefix)
+}
+
+// JobOptions fills in the tenant's defaults for options the job doesn't set
+func (t *Tenant) JobOptions(opts model.JobOptions) model.JobOptions {
+	if opts.OutputBucket == "" {
+		opts.OutputBucket = t.Options.OutputBucket
+	}
+	if opts.OutputBucket == "" {
+		opts.OutputBucket = t.OutputBucket
+	}
+	if opts.Language == "" {
+		opts.Language = t.Options.Language
+	}
+	if opts.Provider == "" && opts.ModelID == "" {
+		opts.Provider = t.Options.Provider
+		opts.ModelID = t.Options.ModelID
+	}
+	return opts
+}
+
+// validID matches tenant IDs, which must not contain the '#' separating them from the key
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Registry holds the configured tenants
+type Registry struct {
+	tenants []Tenant
+}
+
+// Parse reads a registry document such as {"tenants":[{"id":"support","bucket":"audio","prefix":"support/"}]}
+func Parse(data []byte) (*Registry, error) {
+	var doc struct {
+		Tenants []Tenant `json:"tenants"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid tenant registry: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var problems []string
+	for i, t := range doc.Tenants {
+		switch {
+		case !validID.MatchString(t.ID):
+			problems = append(problems, fmt.Sprintf("tenant %d: invalid id %q", i+1, t.ID))
+		case seen[t.ID]:
+			problems = append(problems, fmt.Sprintf("tenant %s: duplicate id", t.ID))
+		}
+		seen[t.ID] = true
+
+		if t.Bucket == "" {
+			problems = append(problems, fmt.Sprintf("tenant %s: bucket is required", t.ID))
+		}
+		if t.Quotas.MaxConcurrentJobs < 0 || t.Quotas.DailyMinutes < 0 || t.Quotas.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("tenant %s: quotas must not be negative", t.ID))
+		}
+		for _, other := range doc.Tenants[:i] {
+			if other.Bucket == t.Bucket && other.Prefix == t.Prefix {
+				problems = append(problems, fmt.Sprintf("tenant %s: s3://%s/%s is already assigned to %s", t.ID, t.Bucket, t.Prefix, other.ID))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid tenant registry: %s", strings.Join(problems, "; "))
+	}
+
+	return &Registry{tenants: doc.Tenants}, nil
+}
+
+// ObjectReader reads S3 objects
+type ObjectReader interface {
+	ReadObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// Load reads the registry from a local file or an s3://bucket/key location
+func Load(ctx context.Context, location string, objects ObjectReader) (*Registry, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(location, "s3://") {
+		var bucket, key string
+		if bucket, key, err = awsclient.ParseS3URI(location); err != nil {
+			return nil, err
+		}
+		if objects == nil {
+			return nil, errors.New("tenant registry in S3 needs an S3 reader")
+		}
+		data, err = objects.ReadObject(ctx, bucket, key)
+	} else {
+		data, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant registry %s: %w", location, err)
+	}
+
+	return Parse(data)
+}
+
+// Resolve returns the tenant owning an input object; the longest matching prefix wins. A nil
+// registry, as in single-tenant deployments, resolves nothing.
+func (r *Registry) Resolve(bucket, key string) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	var match *Tenant
+	for i := range r.tenants {
+		t := &r.tenants[i]
+		if t.Owns(bucket, key) && (match == nil || len(t.Prefix) > len(match.Prefix)) {
+			match = t
+		}
+	}
+	return match, match != nil
+}
+
+// Get returns a tenant by ID
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	for i := range r.tenants {
+		if r.tenants[i].ID == id {
+			return &r.tenants[i], true
+		}
+	}
+	return nil, false
+}
+
+// Tenants returns every configured tenant
+func (r *Registry) Tenants() []Tenant {
+	if r == nil {
+		return nil
+	}
+	return r.tenants
+}
+
+// JobID returns the identifier under which the job for an input object is stored: partitioned by
+// its tenant when one owns it, otherwise the key itself
+func (r *Registry) JobID(bucket, key string) string {
+	if t, ok := r.Resolve(bucket, key); ok {
+		return model.TenantJobID(t.ID, key)
+	}
+	return key
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the tenant a job belongs to
+func NewContext(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant carried by ctx, if any
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok && t != nil
+}
@@ -0,0 +1,100 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+const registryJSON = `{"tenants": [
+	{"id": "support", "bucket": "audio", "prefix": "support/", "secretName": "elevenlabs/support",
+	 "outputBucket": "support-transcripts", "options": {"language": "en"}},
+	{"id": "support-emea", "bucket": "audio", "prefix": "support/emea/"},
+	{"id": "sales", "bucket": "sales-audio", "quotas": {"maxConcurrentJobs": 2, "dailyMinutes": 600}}
+]}`
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry, err := Parse([]byte(registryJSON))
+	assert.NoError(t, err)
+
+	owner, ok := registry.Resolve("audio", "support/emea/call.mp3")
+	assert.True(t, ok)
+	assert.Equal(t, "support-emea", owner.ID)
+
+	owner, ok = registry.Resolve("audio", "support/call.mp3")
+	assert.True(t, ok)
+	assert.Equal(t, "support", owner.ID)
+
+	owner, ok = registry.Resolve("sales-audio", "any/call.mp3")
+	assert.True(t, ok)
+	assert.Equal(t, 2, owner.Quotas.MaxConcurrentJobs)
+
+	_, ok = registry.Resolve("audio", "marketing/call.mp3")
+	assert.False(t, ok)
+
+	assert.Equal(t, "tenant#support#support/call.mp3", registry.JobID("audio", "support/call.mp3"))
+	assert.Equal(t, "marketing/call.mp3", registry.JobID("audio", "marketing/call.mp3"))
+
+	// Single-tenant deployments have no registry
+	var none *Registry
+	_, ok = none.Resolve("audio", "support/call.mp3")
+	assert.False(t, ok)
+	assert.Equal(t, "support/call.mp3", none.JobID("audio", "support/call.mp3"))
+}
+
+func TestParse_ReportsEveryProblem(t *testing.T) {
+	_, err := Parse([]byte(`{"tenants": [
+		{"id": "Support#1", "bucket": "audio"},
+		{"id": "sales"},
+		{"id": "sales", "bucket": "audio", "quotas": {"dailyMinutes": -1}},
+		{"id": "ops", "bucket": "ops-audio", "quotas": {"weight": -2}}
+	]}`))
+
+	assert.EqualError(t, err, "invalid tenant registry: "+
+		`tenant 1: invalid id "Support#1"; `+
+		"tenant sales: bucket is required; "+
+		"tenant sales: duplicate id; "+
+		"tenant sales: quotas must not be negative; "+
+		"tenant sales: s3://audio/ is already assigned to Support#1; "+
+		"tenant ops: quotas must not be negative")
+}
+
+func TestTenant_JobOptions(t *testing.T) {
+	registry, err := Parse([]byte(registryJSON))
+	assert.NoError(t, err)
+	support, _ := registry.Get("support")
+
+	opts := support.JobOptions(model.JobOptions{BatchID: "b1"})
+	assert.Equal(t, model.JobOptions{BatchID: "b1", OutputBucket: "support-transcripts", Language: "en"}, opts)
+
+	// Options given with the job win
+	opts = support.JobOptions(model.JobOptions{OutputBucket: "elsewhere", Language: "de"})
+	assert.Equal(t, "elsewhere", opts.OutputBucket)
+	assert.Equal(t, "de", opts.Language)
+}
+
+func TestLoad_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	assert.NoError(t, os.WriteFile(path, []byte(registryJSON), 0o600))
+
+	registry, err := Load(context.Background(), path, nil)
+	assert.NoError(t, err)
+	assert.Len(t, registry.Tenants(), 3)
+
+	_, err = Load(context.Background(), "s3://config/tenants.json", nil)
+	assert.EqualError(t, err, "tenant registry in S3 needs an S3 reader")
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	owner := &Tenant{ID: "support"}
+	got, ok := FromContext(NewContext(context.Background(), owner))
+	assert.True(t, ok)
+	assert.Same(t, owner, got)
+}
@@ -0,0 +1,183 @@
+// Package tracing sets up OpenTelemetry tracing for the Lambda functions: spans around each processing
+// stage, instrumented AWS SDK and HTTP clients, and trace context carried across the asynchronous hops
+// from the processor to the notification sinks.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this service's own spans
+const instrumentationName = "github.com/yourusername/transcription-service"
+
+// Exporters selectable with Config.Exporter
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config selects where spans are exported
+type Config struct {
+	// Exporter is ExporterOTLP, ExporterStdout or ExporterNone (the default)
+	Exporter string
+
+	// Endpoint is the OTLP/HTTP collector endpoint, e.g. localhost:4318. When empty the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT variable or the exporter default applies.
+	Endpoint string
+
+	// ServiceName is the service.name resource attribute
+	ServiceName string
+}
+
+// Provider exports the spans of one function
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// Setup installs the global tracer provider and W3C trace context propagation. With ExporterNone spans
+// are still created, so trace IDs propagate, but nothing is exported.
+func Setup(ctx context.Context, cfg Config) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "", ExporterNone:
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(cfg.ServiceName))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return &Provider{tp: tp}, nil
+}
+
+// Flush exports buffered spans. Lambda freezes the process between invocations, so call it before
+// each invocation returns.
+func (p *Provider) Flush(ctx context.Context) {
+	if err := p.tp.ForceFlush(ctx); err != nil {
+		otel.Handle(err)
+	}
+}
+
+// Shutdown flushes and stops the exporter
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
+
+// ParseExporter validates an exporter name, defaulting to ExporterNone
+func ParseExporter(value string) (string, error) {
+	switch value {
+	case "":
+		return ExporterNone, nil
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown trace exporter %q", value)
+}
+
+// Handler wraps a Lambda handler so spans are flushed before each invocation returns
+func Handler[E, R any](p *Provider, handler func(context.Context, E) (R, error)) func(context.Context, E) (R, error) {
+	return func(ctx context.Context, event E) (R, error) {
+		defer p.Flush(ctx)
+		return handler(ctx, event)
+	}
+}
+
+// ErrorHandler is Handler for Lambda handlers that return only an error
+func ErrorHandler[E any](p *Provider, handler func(context.Context, E) error) func(context.Context, E) error {
+	return func(ctx context.Context, event E) error {
+		defer p.Flush(ctx)
+		return handler(ctx, event)
+	}
+}
+
+// Start starts a span as a child of the one in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends a span, marking it failed when err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InstrumentAWS adds a span for every AWS SDK call made with clients built from cfg
+func InstrumentAWS(cfg *aws.Config) {
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+}
+
+// HTTPClient returns a copy of client whose requests are traced and carry the trace context
+func HTTPClient(client *http.Client) *http.Client {
+	traced := *client
+	transport := traced.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	traced.Transport = otelhttp.NewTransport(transport)
+	return &traced
+}
+
+// Inject returns the trace context of ctx as string headers (traceparent, tracestate, baggage) for
+// messages that leave the process
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns a context continuing the trace recorded by Inject
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// InjectHTTP adds the trace context of ctx to outgoing request headers
+func InjectHTTP(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider that keeps finished spans in memory
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	_, err := Setup(context.Background(), Config{Exporter: ExporterNone, ServiceName: "test"})
+	assert.NoError(t, err)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestInjectExtract(t *testing.T) {
+	record(t)
+
+	ctx, span := Start(context.Background(), "Process")
+	headers := Inject(ctx)
+	span.End()
+
+	assert.Contains(t, headers, "traceparent")
+	remote := trace.SpanContextFromContext(Extract(context.Background(), headers))
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+
+	// Without a span there is nothing to carry
+	assert.Nil(t, Inject(context.Background()))
+	assert.Equal(t, context.Background(), Extract(context.Background(), nil))
+}
+
+func TestEnd(t *testing.T) {
+	recorder := record(t)
+
+	_, ok := Start(context.Background(), "Ok")
+	End(ok, nil)
+	_, failed := Start(context.Background(), "Failed")
+	End(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+	assert.Len(t, spans[1].Events(), 1)
+}
+
+func TestHTTPClient(t *testing.T) {
+	recorder := record(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "Notify")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+	resp, err := HTTPClient(&http.Client{}).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	span.End()
+
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestParseExporter(t *testing.T) {
+	exporter, err := ParseExporter("")
+	assert.NoError(t, err)
+	assert.Equal(t, ExporterNone, exporter)
+
+	exporter, err = ParseExporter(ExporterStdout)
+	assert.NoError(t, err)
+	assert.Equal(t, ExporterStdout, exporter)
+
+	_, err = ParseExporter("zipkin")
+	assert.EqualError(t, err, `unknown trace exporter "zipkin"`)
+}
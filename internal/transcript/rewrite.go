@@ -0,0 +1,96 @@
+package transcript
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Edit replaces the non-empty byte range [Start, End) of a string with Text
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// ApplyEdits applies non-overlapping edits, ordered by position, to s
+func ApplyEdits(s string, edits []Edit) string {
+	return applyEdits(s, edits, 0)
+}
+
+// RewriteWords applies edits to timed words. find is given the words joined back into text, so it can
+// match across token boundaries; each group of words an edit touches is replaced by a single word
+// covering their combined time, keeping the rest of the timeline untouched.
+func RewriteWords(words []model.Word, find func(joined string) []Edit) []model.Word {
+	if len(words) == 0 {
+		return words
+	}
+
+	var b strings.Builder
+	offsets := make([]int, len(words)+1)
+	for i, word := range words {
+		offsets[i] = b.Len()
+		b.WriteString(word.Text)
+	}
+	offsets[len(words)] = b.Len()
+	joined := b.String()
+
+	edits := find(joined)
+	if len(edits) == 0 {
+		return words
+	}
+
+	// wordAt returns the index of the word containing byte offset pos
+	wordAt := func(pos int) int {
+		return sort.Search(len(words), func(i int) bool { return offsets[i+1] > pos })
+	}
+	lastWord := func(edit Edit) int {
+		return wordAt(edit.End - 1)
+	}
+
+	rewritten := make([]model.Word, 0, len(words))
+	next := 0
+	for i := 0; i < len(edits); {
+		first := wordAt(edits[i].Start)
+		last := lastWord(edits[i])
+
+		// Edits that share a word are applied together
+		j := i + 1
+		for j < len(edits) && wordAt(edits[j].Start) <= last {
+			if end := lastWord(edits[j]); end > last {
+				last = end
+			}
+			j++
+		}
+
+		rewritten = append(rewritten, words[next:first]...)
+		wordType := words[first].Type
+		if first != last {
+			wordType = "word"
+		}
+		rewritten = append(rewritten, model.Word{
+			Text:  applyEdits(joined[offsets[first]:offsets[last+1]], edits[i:j], offsets[first]),
+			Start: words[first].Start,
+			End:   words[last].End,
+			Type:  wordType,
+		})
+
+		next = last + 1
+		i = j
+	}
+	return append(rewritten, words[next:]...)
+}
+
+// applyEdits applies edits whose offsets are relative to base
+func applyEdits(s string, edits []Edit, base int) string {
+	var b strings.Builder
+	pos := 0
+	for _, edit := range edits {
+		b.WriteString(s[pos : edit.Start-base])
+		b.WriteString(edit.Text)
+		pos = edit.End - base
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
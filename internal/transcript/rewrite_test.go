@@ -0,0 +1,34 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func TestRewriteWords(t *testing.T) {
+	// Replace "world. Second" (across three tokens) and "line" (one token)
+	words := RewriteWords(testWords(), func(joined string) []Edit {
+		start := strings.Index(joined, "world.")
+		line := strings.Index(joined, "line")
+		return []Edit{
+			{Start: start, End: start + len("world. Second"), Text: "there"},
+			{Start: line, End: line + len("line"), Text: "LINE"},
+		}
+	})
+
+	assert.Equal(t, []model.Word{
+		{Text: "Hello", Start: 0.0, End: 0.4, Type: "word"},
+		{Text: " ", Start: 0.4, End: 0.5, Type: "spacing"},
+		{Text: "there", Start: 0.5, End: 61.6, Type: "word"},
+		{Text: " ", Start: 61.6, End: 61.7, Type: "spacing"},
+		{Text: "LINE", Start: 61.7, End: 62.05, Type: "word"},
+	}, words)
+}
+
+func TestApplyEdits(t *testing.T) {
+	assert.Equal(t, "a-b-c", ApplyEdits("a b c", []Edit{{Start: 1, End: 2, Text: "-"}, {Start: 3, End: 4, Text: "-"}}))
+	assert.Equal(t, "abc", ApplyEdits("abc", nil))
+}
@@ -0,0 +1,107 @@
+// Package transcript renders structured transcripts into subtitle and text formats.
+package transcript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+const (
+	// maxCueDuration is the longest a single subtitle cue may stay on screen, in seconds
+	maxCueDuration = 6.0
+
+	// maxCueChars is the longest a single subtitle cue's text may be
+	maxCueChars = 84
+)
+
+// Cue is one timed subtitle line
+type Cue struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// BuildCues groups timed words into subtitle cues, breaking at sentence ends,
+// maxCueDuration and maxCueChars. Spacing tokens are folded into the text.
+func BuildCues(words []model.Word) []Cue {
+	var cues []Cue
+	var current *Cue
+	var text strings.Builder
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(text.String())
+		if current.Text != "" {
+			cues = append(cues, *current)
+		}
+		current = nil
+		text.Reset()
+	}
+
+	for _, word := range words {
+		if word.Type == "spacing" {
+			if current != nil {
+				text.WriteString(" ")
+			}
+			continue
+		}
+
+		if current != nil && (word.End-current.Start > maxCueDuration || text.Len()+len(word.Text) > maxCueChars) {
+			flush()
+		}
+
+		if current == nil {
+			current = &Cue{Start: word.Start}
+		}
+		text.WriteString(word.Text)
+		current.End = word.End
+
+		if endsSentence(word.Text) {
+			flush()
+		}
+	}
+	flush()
+
+	return cues
+}
+
+// FormatSRT renders cues as SubRip subtitles
+func FormatSRT(cues []Cue) string {
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(cue.Start, ","), timestamp(cue.End, ","), cue.Text)
+	}
+	return b.String()
+}
+
+// FormatVTT renders cues as WebVTT subtitles
+func FormatVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", timestamp(cue.Start, "."), timestamp(cue.End, "."), cue.Text)
+	}
+	return b.String()
+}
+
+// timestamp formats seconds as HH:MM:SS followed by the millisecond separator and milliseconds
+func timestamp(seconds float64, msSeparator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int64(seconds*1000 + 0.5)
+	ms := total % 1000
+	s := (total / 1000) % 60
+	m := (total / 60000) % 60
+	h := total / 3600000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, msSeparator, ms)
+}
+
+// endsSentence reports whether a token closes a sentence
+func endsSentence(token string) bool {
+	return strings.HasSuffix(token, ".") || strings.HasSuffix(token, "?") || strings.HasSuffix(token, "!")
+}
@@ -0,0 +1,58 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+func testWords() []model.Word {
+	return []model.Word{
+		{Text: "Hello", Start: 0.0, End: 0.4, Type: "word"},
+		{Text: " ", Start: 0.4, End: 0.5, Type: "spacing"},
+		{Text: "world.", Start: 0.5, End: 1.0, Type: "word"},
+		{Text: " ", Start: 1.0, End: 1.2, Type: "spacing"},
+		{Text: "Second", Start: 61.2, End: 61.6, Type: "word"},
+		{Text: " ", Start: 61.6, End: 61.7, Type: "spacing"},
+		{Text: "line", Start: 61.7, End: 62.05, Type: "word"},
+	}
+}
+
+func TestBuildCues(t *testing.T) {
+	cues := BuildCues(testWords())
+
+	assert.Equal(t, []Cue{
+		{Start: 0.0, End: 1.0, Text: "Hello world."},
+		{Start: 61.2, End: 62.05, Text: "Second line"},
+	}, cues)
+}
+
+func TestBuildCues_SplitsLongCues(t *testing.T) {
+	var words []model.Word
+	for i := 0; i < 20; i++ {
+		words = append(words, model.Word{Text: "word", Start: float64(i), End: float64(i) + 0.5, Type: "word"})
+	}
+
+	cues := BuildCues(words)
+
+	assert.Greater(t, len(cues), 1)
+	for _, cue := range cues {
+		assert.LessOrEqual(t, cue.End-cue.Start, maxCueDuration)
+	}
+}
+
+func TestFormatSRT(t *testing.T) {
+	srt := FormatSRT(BuildCues(testWords()))
+
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:01,000\nHello world.\n\n"+
+		"2\n00:01:01,200 --> 00:01:02,050\nSecond line\n\n", srt)
+}
+
+func TestFormatVTT(t *testing.T) {
+	vtt := FormatVTT(BuildCues(testWords()))
+
+	assert.Equal(t, "WEBVTT\n\n"+
+		"00:00:00.000 --> 00:00:01.000\nHello world.\n\n"+
+		"00:01:01.200 --> 00:01:02.050\nSecond line\n\n", vtt)
+}
@@ -0,0 +1,100 @@
+package usage
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Summary is one row of a usage report
+type Summary struct {
+	// Day is the UTC date, or empty when the report covers the whole range
+	Day string `json:"day,omitempty"`
+
+	TenantID string `json:"tenantId,omitempty"`
+	Prefix   string `json:"prefix"`
+	Provider string `json:"provider"`
+	ModelID  string `json:"modelId,omitempty"`
+
+	Jobs          int     `json:"jobs"`
+	AudioMinutes  float64 `json:"audioMinutes"`
+	Characters    int     `json:"characters"`
+	EstimatedCost float64 `json:"estimatedCost"`
+}
+
+// Summarize adds up daily totals per tenant, prefix, provider and model, keeping days apart when daily
+// is set. Rows are ordered by day, tenant, prefix, provider and model.
+func Summarize(totals []model.UsageDaily, daily bool) []Summary {
+	type group struct{ day, tenantID, prefix, provider, modelID string }
+	rows := map[group]*Summary{}
+
+	for _, total := range totals {
+		key := group{tenantID: total.TenantID, prefix: total.Prefix, provider: total.Provider, modelID: total.ModelID}
+		if daily {
+			key.day = total.UsageDay
+		}
+
+		row, ok := rows[key]
+		if !ok {
+			row = &Summary{Day: key.day, TenantID: key.tenantID, Prefix: key.prefix, Provider: key.provider, ModelID: key.modelID}
+			rows[key] = row
+		}
+		row.Jobs += total.Jobs
+		row.AudioMinutes += total.AudioSeconds / 60
+		row.Characters += total.Characters
+		row.EstimatedCost += total.EstimatedCost
+	}
+
+	summaries := make([]Summary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, *row)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.ModelID < b.ModelID
+	})
+	return summaries
+}
+
+// WriteCSV writes a report with a header row. Minutes are rounded to two decimals and costs to four.
+func WriteCSV(w io.Writer, summaries []Summary) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"day", "tenant", "prefix", "provider", "model", "jobs", "audio_minutes", "characters", "estimated_cost_usd"}); err != nil {
+		return err
+	}
+
+	for _, s := range summaries {
+		record := []string{
+			s.Day,
+			s.TenantID,
+			s.Prefix,
+			s.Provider,
+			s.ModelID,
+			strconv.Itoa(s.Jobs),
+			strconv.FormatFloat(s.AudioMinutes, 'f', 2, 64),
+			strconv.Itoa(s.Characters),
+			strconv.FormatFloat(s.EstimatedCost, 'f', 4, 64),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,72 @@
+// Package usage keeps the ledger of what completed jobs consumed (audio seconds, transcript
+// characters and an estimated cost) and summarizes it for finance reports.
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// Prices maps "provider/model" or "provider" to the USD price of one minute of audio.
+// A model's own price wins over its provider's.
+type Prices map[string]float64
+
+// ParsePrices reads a price table such as {"elevenlabs":0.0067,"elevenlabs/scribe_v1":0.0067}
+func ParsePrices(value string) (Prices, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var prices Prices
+	if err := json.Unmarshal([]byte(value), &prices); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of provider or provider/model to price per minute: %w", err)
+	}
+	for name, price := range prices {
+		if price < 0 {
+			return nil, fmt.Errorf("negative price for %s", name)
+		}
+	}
+	return prices, nil
+}
+
+// Cost estimates the cost of transcribing seconds of audio; providers without a price cost nothing
+func (p Prices) Cost(provider, modelID string, seconds float64) float64 {
+	price, ok := p[provider+"/"+modelID]
+	if !ok {
+		price = p[provider]
+	}
+	return price * seconds / 60
+}
+
+// Store persists usage entries and their daily totals
+type Store interface {
+	RecordUsage(ctx context.Context, entry *model.UsageEntry) error
+}
+
+// Ledger prices and records the usage of completed jobs
+type Ledger struct {
+	store  Store
+	prices Prices
+	now    func() time.Time
+}
+
+// NewLedger creates a ledger that prices entries with prices
+func NewLedger(store Store, prices Prices) *Ledger {
+	return &Ledger{
+		store:  store,
+		prices: prices,
+		now:    time.Now,
+	}
+}
+
+// Record prices an entry, dates it and stores it. Recording the same job attempt twice only counts once.
+func (l *Ledger) Record(ctx context.Context, entry *model.UsageEntry) error {
+	entry.CompletedAt = l.now().UTC().Truncate(time.Second)
+	entry.Day = entry.CompletedAt.Format(model.UsageDayFormat)
+	entry.EstimatedCost = l.prices.Cost(entry.Provider, entry.ModelID, entry.AudioSeconds)
+	return l.store.RecordUsage(ctx, entry)
+}
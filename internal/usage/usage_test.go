@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/transcription-service/internal/model"
+)
+
+// memoryStore keeps recorded entries in memory
+type memoryStore struct {
+	entries []model.UsageEntry
+}
+
+func (s *memoryStore) RecordUsage(ctx context.Context, entry *model.UsageEntry) error {
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+func TestParsePrices(t *testing.T) {
+	prices, err := ParsePrices(`{"elevenlabs":0.006,"elevenlabs/scribe_v1_experimental":0.009}`)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.009, prices.Cost("elevenlabs", "scribe_v1", 90), 1e-9)
+	assert.InDelta(t, 0.0135, prices.Cost("elevenlabs", "scribe_v1_experimental", 90), 1e-9)
+	assert.Zero(t, prices.Cost("whisper", "", 90))
+
+	prices, err = ParsePrices("")
+	assert.NoError(t, err)
+	assert.Zero(t, prices.Cost("elevenlabs", "", 90))
+
+	_, err = ParsePrices(`["elevenlabs"]`)
+	assert.Error(t, err)
+}
+
+func TestLedger_Record(t *testing.T) {
+	store := &memoryStore{}
+	ledger := NewLedger(store, Prices{"elevenlabs": 0.006})
+	ledger.now = func() time.Time { return time.Date(2024, 3, 1, 23, 59, 59, 500, time.FixedZone("CET", 3600)) }
+
+	err := ledger.Record(context.Background(), &model.UsageEntry{
+		JobHash:      model.JobHash("calls/a.mp3"),
+		Attempt:      1,
+		Prefix:       "calls/",
+		Provider:     "elevenlabs",
+		AudioSeconds: 120,
+		Characters:   1500,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, store.entries, 1)
+	assert.Equal(t, "2024-03-01", store.entries[0].Day)
+	assert.Equal(t, time.Date(2024, 3, 1, 22, 59, 59, 0, time.UTC), store.entries[0].CompletedAt)
+	assert.InDelta(t, 0.012, store.entries[0].EstimatedCost, 1e-9)
+}
+
+func TestSummarize(t *testing.T) {
+	totals := []model.UsageDaily{
+		{UsageDay: "2024-03-02", Prefix: "calls/", Provider: "elevenlabs", Jobs: 2, AudioSeconds: 120, Characters: 900, EstimatedCost: 0.012},
+		{UsageDay: "2024-03-01", Prefix: "calls/", Provider: "elevenlabs", Jobs: 1, AudioSeconds: 30, Characters: 300, EstimatedCost: 0.003},
+		{UsageDay: "2024-03-01", Prefix: "/", Provider: "elevenlabs", Jobs: 1, AudioSeconds: 60, Characters: 500, EstimatedCost: 0.006},
+	}
+
+	assert.Equal(t, []Summary{
+		{Prefix: "/", Provider: "elevenlabs", Jobs: 1, AudioMinutes: 1, Characters: 500, EstimatedCost: 0.006},
+		{Prefix: "calls/", Provider: "elevenlabs", Jobs: 3, AudioMinutes: 2.5, Characters: 1200, EstimatedCost: 0.015},
+	}, Summarize(totals, false))
+
+	daily := Summarize(totals, true)
+	assert.Len(t, daily, 3)
+	assert.Equal(t, "2024-03-01", daily[0].Day)
+	assert.Equal(t, "/", daily[0].Prefix)
+	assert.Equal(t, "2024-03-02", daily[2].Day)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []Summary{
+		{Day: "2024-03-01", TenantID: "support", Prefix: "calls/", Provider: "elevenlabs", ModelID: "scribe_v1", Jobs: 3, AudioMinutes: 2.5, Characters: 1200, EstimatedCost: 0.015},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "day,tenant,prefix,provider,model,jobs,audio_minutes,characters,estimated_cost_usd\n"+
+		"2024-03-01,support,calls/,elevenlabs,scribe_v1,3,2.50,1200,0.0150\n", buf.String())
+}